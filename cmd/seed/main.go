@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"educabot.com/bookshop/internal/core/validation"
+	"educabot.com/bookshop/internal/repositories/http"
+	"educabot.com/bookshop/internal/repositories/sqlite"
+)
+
+// seed carga el catálogo desde la API externa en una base SQLite local
+// Uso: go run ./cmd/seed -db bookshop.db [-url https://...]
+func main() {
+	dbPath := flag.String("db", "bookshop.db", "path to the SQLite database file")
+	apiURL := flag.String("url", "", "books API URL (defaults to the mockapi catalog)")
+	timeout := flag.Duration("timeout", 30*time.Second, "overall timeout for the seed")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// Los libros inválidos (IDs repetidos, sin precio...) violarían las restricciones de la base
+	// y harían fallar la carga completa, así que se descartan como en el servidor
+	validationOption := http.WithValidation(validation.NewPipeline(validation.PolicyDrop, validation.DefaultRules()...))
+	source := http.NewHTTPBooksRepository(validationOption)
+	if *apiURL != "" {
+		source = http.NewHTTPBooksRepositoryWithConfig(&nethttp.Client{Timeout: 10 * time.Second}, *apiURL, validationOption)
+	}
+
+	books, err := source.GetBooks(ctx)
+	if err != nil {
+		log.Fatalf("Failed to fetch books: %v", err)
+	}
+	if dropped := source.Quarantined(); len(dropped) > 0 {
+		log.Printf("Dropped %d invalid books", len(dropped))
+		for _, book := range dropped {
+			log.Printf("  book at index %d: %s", book.Index, strings.Join(book.Reasons, "; "))
+		}
+	}
+
+	repository, err := sqlite.Open(ctx, *dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open database: %v", err)
+	}
+	defer repository.Close()
+
+	if err := repository.ReplaceBooks(ctx, books); err != nil {
+		log.Fatalf("Failed to store books: %v", err)
+	}
+
+	fmt.Printf("Seeded %d books into %s\n", len(books), *dbPath)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// BookRequest representa el cuerpo de las solicitudes de escritura de libros
+type BookRequest struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	Author    string `json:"author"`
+	UnitsSold uint   `json:"units_sold"`
+	// Price acepta {"amount": 1299, "currency": "USD"} o, como antes, un número en unidades enteras
+	Price domain.Money `json:"price"`
+	// Version es la versión leída por el cliente; en las actualizaciones puede enviarse en If-Match
+	Version uint64 `json:"version"`
+}
+
+func (r BookRequest) book() domain.Book {
+	return domain.Book{ID: r.ID, Name: r.Name, Author: r.Author, UnitsSold: r.UnitsSold, Price: r.Price, Version: r.Version}
+}
+
+// CreateBook es el handler para agregar un libro al catálogo
+type CreateBook struct {
+	catalogService ports.CatalogService
+}
+
+// NewCreateBook crea una nueva instancia del handler de alta de libros
+func NewCreateBook(catalogService ports.CatalogService) CreateBook {
+	return CreateBook{catalogService}
+}
+
+// Handle devuelve la función de controlador para Gin
+func (h CreateBook) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request BookRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil || request.Price.Amount < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book payload"})
+			return
+		}
+
+		book, err := h.catalogService.CreateBook(ctx.Request.Context(), request.book())
+		if err != nil {
+			writeBookError(ctx, err)
+			return
+		}
+
+		ctx.Header("Location", fmt.Sprintf("/books/%d", book.ID))
+		ctx.Header("ETag", etag(book.Version))
+		ctx.JSON(http.StatusCreated, book)
+	}
+}
+
+// UpdateBook es el handler para reemplazar un libro del catálogo
+type UpdateBook struct {
+	catalogService ports.CatalogService
+}
+
+// NewUpdateBook crea una nueva instancia del handler de modificación de libros
+func NewUpdateBook(catalogService ports.CatalogService) UpdateBook {
+	return UpdateBook{catalogService}
+}
+
+// Handle devuelve la función de controlador para Gin
+func (h UpdateBook) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := bookID(ctx)
+		if !ok {
+			return
+		}
+		var request BookRequest
+		if err := ctx.ShouldBindJSON(&request); err != nil || request.Price.Amount < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book payload"})
+			return
+		}
+		if request.ID != 0 && request.ID != id {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Book ID in the body does not match the URL"})
+			return
+		}
+		request.ID = id
+
+		version, ok := ifMatchVersion(ctx, request.Version)
+		if !ok {
+			return
+		}
+		request.Version = version
+
+		book, err := h.catalogService.UpdateBook(ctx.Request.Context(), request.book())
+		if err != nil {
+			writeBookError(ctx, err)
+			return
+		}
+
+		ctx.Header("ETag", etag(book.Version))
+		ctx.JSON(http.StatusOK, book)
+	}
+}
+
+// DeleteBook es el handler para eliminar un libro del catálogo
+type DeleteBook struct {
+	catalogService ports.CatalogService
+}
+
+// NewDeleteBook crea una nueva instancia del handler de baja de libros
+func NewDeleteBook(catalogService ports.CatalogService) DeleteBook {
+	return DeleteBook{catalogService}
+}
+
+// Handle devuelve la función de controlador para Gin
+// La versión esperada se toma de If-Match o del parámetro version; sin ninguno se elimina sin comprobarla
+func (h DeleteBook) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, ok := bookID(ctx)
+		if !ok {
+			return
+		}
+
+		var version uint64
+		if raw := ctx.Query("version"); raw != "" {
+			parsed, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version parameter"})
+				return
+			}
+			version = parsed
+		}
+		version, ok = ifMatchVersion(ctx, version)
+		if !ok {
+			return
+		}
+
+		if err := h.catalogService.DeleteBook(ctx.Request.Context(), id, version); err != nil {
+			writeBookError(ctx, err)
+			return
+		}
+		ctx.Status(http.StatusNoContent)
+	}
+}
+
+// bookID lee el ID de la ruta; si no es válido responde 400 y devuelve false
+func bookID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid book ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ifMatchVersion toma la versión del header If-Match (por ejemplo "3" o W/"3") si está presente
+func ifMatchVersion(ctx *gin.Context, fallback uint64) (uint64, bool) {
+	header := ctx.GetHeader("If-Match")
+	if header == "" {
+		return fallback, true
+	}
+	raw := strings.Trim(strings.TrimPrefix(strings.TrimSpace(header), "W/"), `"`)
+	version, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid If-Match header"})
+		return 0, false
+	}
+	return version, true
+}
+
+func etag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// writeBookError responde con el código correspondiente al error de escritura
+// El motivo se incluye porque describe el libro enviado por el cliente, no el estado interno
+func writeBookError(ctx *gin.Context, err error) {
+	status, message := statusForError(err)
+	response := gin.H{"error": message}
+	if status == http.StatusBadRequest || status == http.StatusConflict || status == http.StatusNotFound {
+		response["detail"] = err.Error()
+	}
+	ctx.JSON(status, response)
+}
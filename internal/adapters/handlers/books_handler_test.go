@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/services"
+	"educabot.com/bookshop/internal/repositories/cache"
+	"educabot.com/bookshop/internal/repositories/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCatalogService es un mock del servicio de escritura del catálogo
+type MockCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogService) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	args := m.Called(ctx, book)
+	return args.Get(0).(domain.Book), args.Error(1)
+}
+
+func (m *MockCatalogService) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	args := m.Called(ctx, book)
+	return args.Get(0).(domain.Book), args.Error(1)
+}
+
+func (m *MockCatalogService) DeleteBook(ctx context.Context, id uint, version uint64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func booksRouter(service *MockCatalogService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/books", NewCreateBook(service).Handle())
+	r.PUT("/books/:id", NewUpdateBook(service).Handle())
+	r.DELETE("/books/:id", NewDeleteBook(service).Handle())
+	return r
+}
+
+func serveBooks(r *gin.Engine, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	return res
+}
+
+func TestCreateBook_Created(t *testing.T) {
+	service := new(MockCatalogService)
+	created := domain.Book{ID: 4, Name: "New Book", Author: "Jane Doe", UnitsSold: 0, Price: domain.Price(10), Version: 1}
+	service.On("CreateBook", mock.Anything, domain.Book{Name: "New Book", Author: "Jane Doe", Price: domain.Price(10)}).Return(created, nil)
+
+	res := serveBooks(booksRouter(service), http.MethodPost, "/books", `{"name": "New Book", "author": "Jane Doe", "price": 10}`, nil)
+
+	assert.Equal(t, http.StatusCreated, res.Code)
+	assert.Equal(t, "/books/4", res.Header().Get("Location"))
+	assert.Equal(t, `"1"`, res.Header().Get("ETag"))
+	var body domain.Book
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, created, body)
+	service.AssertExpectations(t)
+}
+
+func TestCreateBook_InvalidPayload(t *testing.T) {
+	service := new(MockCatalogService)
+
+	for _, payload := range []string{`{"name": "Book", "price": -1}`, `not json`} {
+		res := serveBooks(booksRouter(service), http.MethodPost, "/books", payload, nil)
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	}
+	service.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+}
+
+func TestUpdateBook_UsesIfMatchVersion(t *testing.T) {
+	service := new(MockCatalogService)
+	expected := domain.Book{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", Price: domain.Price(55), Version: 3}
+	service.On("UpdateBook", mock.Anything, expected).Return(domain.Book{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", Price: domain.Price(55), Version: 4}, nil)
+
+	res := serveBooks(booksRouter(service), http.MethodPut, "/books/2",
+		`{"name": "Clean Code", "author": "Robert C. Martin", "price": 55, "version": 1}`,
+		map[string]string{"If-Match": `"3"`})
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, `"4"`, res.Header().Get("ETag"))
+	service.AssertExpectations(t)
+}
+
+func TestUpdateBook_Errors(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		body           string
+		err            error
+		expectedStatus int
+	}{
+		{"invalid id", "/books/abc", `{}`, nil, http.StatusBadRequest},
+		{"mismatched id", "/books/2", `{"id": 3, "name": "Book"}`, nil, http.StatusBadRequest},
+		{"invalid book", "/books/2", `{"name": ""}`, fmt.Errorf("%w: missing name", domain.ErrInvalidBook), http.StatusBadRequest},
+		{"not found", "/books/9", `{"name": "Book"}`, fmt.Errorf("%w: id 9", domain.ErrBookNotFound), http.StatusNotFound},
+		{"version conflict", "/books/2", `{"name": "Book"}`, fmt.Errorf("%w: id 2 is at version 2, not 1", domain.ErrVersionConflict), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := new(MockCatalogService)
+			service.On("UpdateBook", mock.Anything, mock.Anything).Return(domain.Book{}, tt.err)
+
+			res := serveBooks(booksRouter(service), http.MethodPut, tt.target, tt.body, nil)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+			assert.Contains(t, res.Body.String(), "error")
+		})
+	}
+}
+
+func TestDeleteBook(t *testing.T) {
+	service := new(MockCatalogService)
+	service.On("DeleteBook", mock.Anything, uint(2), uint64(5)).Return(nil)
+	service.On("DeleteBook", mock.Anything, uint(3), uint64(0)).Return(fmt.Errorf("%w: id 3", domain.ErrBookNotFound))
+
+	r := booksRouter(service)
+	assert.Equal(t, http.StatusNoContent, serveBooks(r, http.MethodDelete, "/books/2?version=5", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, serveBooks(r, http.MethodDelete, "/books/3", "", nil).Code)
+	assert.Equal(t, http.StatusBadRequest, serveBooks(r, http.MethodDelete, "/books/2", "", map[string]string{"If-Match": "*"}).Code)
+	service.AssertExpectations(t)
+}
+
+func TestBooksEndpoints_WritesInvalidateCachedMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repository := memory.NewMemoryBooksRepository()
+	cached := cache.NewCachedBooksRepository(repository, cache.DefaultConfig())
+
+	r := gin.New()
+	r.GET("/", NewGetMetrics(services.NewMetricsService(cached)).Handle())
+	r.POST("/books", NewCreateBook(services.NewCatalogService(repository, cached)).Handle())
+
+	metrics := func() map[string]interface{} {
+		res := serveBooks(r, http.MethodGet, "/?author=Jane+Doe", "", nil)
+		require.Equal(t, http.StatusOK, res.Code)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+		return body
+	}
+
+	assert.Equal(t, float64(0), metrics()["books_written_by_author"])
+
+	res := serveBooks(r, http.MethodPost, "/books", `{"name": "Cheap Book", "author": "Jane Doe", "units_sold": 1, "price": 5}`, nil)
+	require.Equal(t, http.StatusCreated, res.Code)
+
+	// Sin invalidar, la caché seguiría devolviendo el catálogo anterior durante el TTL
+	body := metrics()
+	assert.Equal(t, float64(1), body["books_written_by_author"])
+	assert.Equal(t, "Cheap Book", body["cheapest_book"])
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// GetChaos es el handler que expone la configuración vigente de inyección de fallas
+type GetChaos struct {
+	injector ports.FaultInjector
+}
+
+// NewGetChaos crea una nueva instancia del handler de consulta de fallas
+func NewGetChaos(injector ports.FaultInjector) GetChaos {
+	return GetChaos{injector}
+}
+
+// Handle devuelve la función de controlador para Gin
+func (h GetChaos) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, h.injector.ChaosConfig())
+	}
+}
+
+// UpdateChaos es el handler que reemplaza la configuración de inyección de fallas,
+// para activar o cambiar las fallas durante un game day sin reiniciar el servicio
+type UpdateChaos struct {
+	injector ports.FaultInjector
+}
+
+// NewUpdateChaos crea una nueva instancia del handler de configuración de fallas
+func NewUpdateChaos(injector ports.FaultInjector) UpdateChaos {
+	return UpdateChaos{injector}
+}
+
+// Handle devuelve la función de controlador para Gin
+// El cuerpo es la configuración completa; los campos omitidos quedan en cero
+func (h UpdateChaos) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var config domain.ChaosConfig
+		if err := ctx.ShouldBindJSON(&config); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chaos config", "detail": err.Error()})
+			return
+		}
+		if err := h.injector.SetChaosConfig(config); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chaos config", "detail": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusOK, h.injector.ChaosConfig())
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFaultInjector guarda la configuración y valida como el inyector real
+type stubFaultInjector struct {
+	config domain.ChaosConfig
+}
+
+func (s *stubFaultInjector) ChaosConfig() domain.ChaosConfig {
+	return s.config
+}
+
+func (s *stubFaultInjector) SetChaosConfig(config domain.ChaosConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	s.config = config
+	return nil
+}
+
+func chaosRouter(injector *stubFaultInjector) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin/chaos", NewGetChaos(injector).Handle())
+	r.PUT("/admin/chaos", NewUpdateChaos(injector).Handle())
+	return r
+}
+
+func TestUpdateChaos_ReplacesConfig(t *testing.T) {
+	injector := &stubFaultInjector{}
+	r := chaosRouter(injector)
+
+	body := `{"enabled":true,"seed":42,"latency":{"distribution":"uniform","base_ms":100,"jitter_ms":50},"error_rate":0.2,"error":"timeout"}`
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodPut, "/admin/chaos", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	expected := domain.ChaosConfig{
+		Enabled:   true,
+		Seed:      42,
+		Latency:   domain.Latency{Distribution: domain.LatencyUniform, BaseMS: 100, JitterMS: 50},
+		ErrorRate: 0.2,
+		Error:     domain.ChaosTimeout,
+	}
+	assert.Equal(t, expected, injector.config)
+
+	// La consulta devuelve la configuración recién aplicada
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/admin/chaos", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+	var got domain.ChaosConfig
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &got))
+	assert.Equal(t, expected, got)
+}
+
+func TestUpdateChaos_RejectsInvalidConfig(t *testing.T) {
+	injector := &stubFaultInjector{config: domain.ChaosConfig{Enabled: true}}
+	r := chaosRouter(injector)
+
+	for _, body := range []string{`{"error_rate":1.5}`, `{"latency":{"distribution":"gaussian"}}`, `not json`} {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest(http.MethodPut, "/admin/chaos", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, res.Code, body)
+		assert.Contains(t, res.Body.String(), "detail", body)
+	}
+	// La configuración anterior sigue vigente
+	assert.Equal(t, domain.ChaosConfig{Enabled: true}, injector.config)
+}
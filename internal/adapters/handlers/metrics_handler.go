@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
+	"educabot.com/bookshop/internal/core/domain"
 	"educabot.com/bookshop/internal/core/ports"
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +16,10 @@ type GetMetricsRequest struct {
 	Author string `form:"author"`
 }
 
+// StatusClientClosedRequest es el código no estándar (popularizado por nginx) que se usa
+// cuando el cliente cancela la petición antes de recibir la respuesta
+const StatusClientClosedRequest = 499
+
 // GetMetrics es el handler para obtener métricas de libros
 type GetMetrics struct {
 	metricsService ports.MetricsService
@@ -32,24 +40,108 @@ func (h GetMetrics) Handle() gin.HandlerFunc {
 		}
 
 		// Usar el contexto de la petición solo para la operación que lo necesita (obtener libros)
-		requestCtx := ctx.Request.Context()
-		books := h.metricsService.GetBooks(requestCtx)
+		// El contexto lleva un FetchInfo para que los repositorios informen cómo obtuvieron los datos
+		requestCtx := domain.WithFetchInfo(ctx.Request.Context())
+		books, err := h.metricsService.GetBooks(requestCtx)
 
 		// Verificar si se obtuvieron libros correctamente
-		if len(books) == 0 {
-			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not retrieve books data"})
+		// Un catálogo vacío no es un error: las métricas se calculan sobre cero libros
+		if err != nil {
+			status, message := statusForError(err)
+			ctx.JSON(status, gin.H{"error": message})
 			return
 		}
 
 		// Las operaciones de cálculo puro no necesitan contexto
+		// El conteo por autor se calcula sobre el mismo catálogo que el resto de las métricas,
+		// sin una segunda consulta al proveedor
 		meanUnitsSold := h.metricsService.GetMeanUnitsSold(books)
-		cheapestBook := h.metricsService.GetCheapestBook(books).Name
+		cheapestBook := h.metricsService.GetCheapestBook(books)
 		booksWrittenByAuthor := h.metricsService.GetBooksWrittenByAuthor(books, query.Author)
 
-		ctx.JSON(http.StatusOK, gin.H{
+		response := gin.H{
 			"mean_units_sold":         meanUnitsSold,
-			"cheapest_book":           cheapestBook,
+			"cheapest_book":           cheapestBook.Name,
 			"books_written_by_author": booksWrittenByAuthor,
-		})
+		}
+		if len(books) > 0 {
+			response["cheapest_book_price"] = moneyResponse(cheapestBook.Price)
+		}
+		// Con precios en varias monedas se informa además el libro más barato de cada una
+		if cheapestByCurrency := h.metricsService.GetCheapestBookByCurrency(books); len(cheapestByCurrency) > 1 {
+			byCurrency := gin.H{}
+			for currency, book := range cheapestByCurrency {
+				byCurrency[string(currency)] = gin.H{"name": book.Name, "price": moneyResponse(book.Price)}
+			}
+			response["cheapest_book_by_currency"] = byCurrency
+		}
+
+		if info, ok := domain.FetchInfoFromContext(requestCtx); ok {
+			// Informar la antigüedad de los datos cuando se sirvieron desde una caché
+			if info.Cached {
+				ageSeconds := int64(info.CacheAge / time.Second)
+				ctx.Header("Age", strconv.FormatInt(ageSeconds, 10))
+				response["cache"] = gin.H{
+					"age_seconds": ageSeconds,
+					"stale":       info.Stale,
+				}
+			}
+			// Informar cuando los datos provienen de un snapshot guardado en disco
+			if info.FromSnapshot {
+				response["snapshot"] = gin.H{
+					"taken_at": info.SnapshotAt.UTC().Format(time.RFC3339),
+				}
+			}
+			// Advertir cuando las métricas se calcularon sobre un catálogo incompleto
+			if info.Partial {
+				response["partial"] = true
+			}
+			// Advertir cuando el proveedor no estaba disponible y se sirvió un catálogo alternativo
+			if info.Fallback {
+				response["fallback"] = true
+			}
+			if len(info.Warnings) > 0 {
+				response["warnings"] = info.Warnings
+			}
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// moneyResponse representa un importe con su texto formateado para mostrarlo sin conocer los decimales de la moneda
+func moneyResponse(price domain.Money) gin.H {
+	return gin.H{
+		"amount":    price.Amount,
+		"currency":  price.Currency,
+		"formatted": price.String(),
+	}
+}
+
+// statusForError traduce los errores tipados del repositorio a un código HTTP y un mensaje
+func statusForError(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidQuery):
+		return http.StatusBadRequest, "Invalid books query"
+	case errors.Is(err, domain.ErrInvalidBook):
+		return http.StatusBadRequest, "Invalid book"
+	case errors.Is(err, domain.ErrBookNotFound):
+		return http.StatusNotFound, "Book not found"
+	case errors.Is(err, domain.ErrBookExists):
+		return http.StatusConflict, "Book already exists"
+	case errors.Is(err, domain.ErrVersionConflict):
+		return http.StatusConflict, "Book was modified by another request"
+	case errors.Is(err, domain.ErrContextCanceled):
+		return StatusClientClosedRequest, "Request canceled by client"
+	case errors.Is(err, domain.ErrUpstreamTimeout):
+		return http.StatusGatewayTimeout, "Books provider timed out"
+	case errors.Is(err, domain.ErrMalformedPayload):
+		return http.StatusBadGateway, "Books provider returned an invalid response"
+	case errors.Is(err, domain.ErrPayloadTooLarge):
+		return http.StatusBadGateway, "Books provider response exceeds the configured limits"
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return http.StatusBadGateway, "Books provider is unavailable"
+	default:
+		return http.StatusServiceUnavailable, "Could not retrieve books data"
 	}
 }
@@ -3,9 +3,12 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"educabot.com/bookshop/internal/core/domain"
 	"github.com/gin-gonic/gin"
@@ -19,9 +22,21 @@ type MockMetricsService struct {
 }
 
 // Implementación del mock con el diseño correcto de contexto
-func (m *MockMetricsService) GetBooks(ctx context.Context) []domain.Book {
+func (m *MockMetricsService) GetBooks(ctx context.Context) ([]domain.Book, error) {
 	args := m.Called(ctx)
-	return args.Get(0).([]domain.Book)
+	books, _ := args.Get(0).([]domain.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockMetricsService) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	args := m.Called(ctx, query)
+	books, _ := args.Get(0).([]domain.Book)
+	return books, args.Error(1)
+}
+
+func (m *MockMetricsService) CountBooksWrittenByAuthor(ctx context.Context, author string) (uint, error) {
+	args := m.Called(ctx, author)
+	return args.Get(0).(uint), args.Error(1)
 }
 
 func (m *MockMetricsService) GetMeanUnitsSold(books []domain.Book) uint {
@@ -34,6 +49,11 @@ func (m *MockMetricsService) GetCheapestBook(books []domain.Book) domain.Book {
 	return args.Get(0).(domain.Book)
 }
 
+func (m *MockMetricsService) GetCheapestBookByCurrency(books []domain.Book) map[domain.Currency]domain.Book {
+	args := m.Called(books)
+	return args.Get(0).(map[domain.Currency]domain.Book)
+}
+
 func (m *MockMetricsService) GetBooksWrittenByAuthor(books []domain.Book, author string) uint {
 	args := m.Called(books, author)
 	return args.Get(0).(uint)
@@ -47,15 +67,16 @@ func TestGetMetrics_OK(t *testing.T) {
 
 	// Configurar libros de prueba
 	testBooks := []domain.Book{
-		{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: 40},
-		{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: 50},
-		{ID: 3, Name: "The Pragmatic Programmer", Author: "Andrew Hunt", UnitsSold: 13000, Price: 45},
+		{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: domain.Price(40)},
+		{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+		{ID: 3, Name: "The Pragmatic Programmer", Author: "Andrew Hunt", UnitsSold: 13000, Price: domain.Price(45)},
 	}
 
 	// Configurar expectativas del mock
-	mockService.On("GetBooks", mock.Anything).Return(testBooks)
+	mockService.On("GetBooks", mock.Anything).Return(testBooks, nil)
 	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(11000))
-	mockService.On("GetCheapestBook", testBooks).Return(domain.Book{Name: "The Go Programming Language"})
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
 	mockService.On("GetBooksWrittenByAuthor", testBooks, "Alan Donovan").Return(uint(1))
 
 	// Crear el handler con el mock del servicio
@@ -74,6 +95,9 @@ func TestGetMetrics_OK(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.Code)
 	assert.Equal(t, 11000, int(resBody["mean_units_sold"].(float64)))
 	assert.Equal(t, "The Go Programming Language", resBody["cheapest_book"])
+	assert.Equal(t, map[string]interface{}{"amount": float64(4000), "currency": "USD", "formatted": "40.00 USD"}, resBody["cheapest_book_price"])
+	// Con una sola moneda no hace falta el desglose
+	assert.NotContains(t, resBody, "cheapest_book_by_currency")
 	assert.Equal(t, 1, int(resBody["books_written_by_author"].(float64)))
 
 	// Verificar que se llamaron los métodos esperados
@@ -86,8 +110,13 @@ func TestGetMetrics_EmptyBooks(t *testing.T) {
 	// Crear un mock del servicio
 	mockService := new(MockMetricsService)
 
-	// Configurar el mock para que devuelva una lista vacía de libros
-	mockService.On("GetBooks", mock.Anything).Return([]domain.Book{})
+	// Un catálogo vacío es una respuesta legítima y no debe parecer una caída
+	emptyBooks := []domain.Book{}
+	mockService.On("GetBooks", mock.Anything).Return(emptyBooks, nil)
+	mockService.On("GetMeanUnitsSold", emptyBooks).Return(uint(0))
+	mockService.On("GetCheapestBook", emptyBooks).Return(domain.Book{})
+	mockService.On("GetCheapestBookByCurrency", emptyBooks).Return(map[domain.Currency]domain.Book{})
+	mockService.On("GetBooksWrittenByAuthor", emptyBooks, "").Return(uint(0))
 
 	// Crear el handler con el mock del servicio
 	handler := NewGetMetrics(mockService)
@@ -99,12 +128,245 @@ func TestGetMetrics_EmptyBooks(t *testing.T) {
 	res := httptest.NewRecorder()
 	r.ServeHTTP(res, req)
 
-	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, http.StatusOK, res.Code)
 
 	var resBody map[string]interface{}
 	json.Unmarshal(res.Body.Bytes(), &resBody)
-	assert.Contains(t, resBody, "error")
+	assert.Equal(t, 0, int(resBody["mean_units_sold"].(float64)))
+	assert.Equal(t, "", resBody["cheapest_book"])
 
 	// Verificar que se llamaron los métodos esperados
 	mockService.AssertExpectations(t)
 }
+
+func TestGetMetrics_RepositoryErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"upstream unavailable", fmt.Errorf("%w: unexpected status code 500", domain.ErrUpstreamUnavailable), http.StatusBadGateway},
+		{"upstream timeout", fmt.Errorf("%w: deadline exceeded", domain.ErrUpstreamTimeout), http.StatusGatewayTimeout},
+		{"malformed payload", fmt.Errorf("%w: invalid character", domain.ErrMalformedPayload), http.StatusBadGateway},
+		{"payload too large", fmt.Errorf("%w: more than 10 books", domain.ErrPayloadTooLarge), http.StatusBadGateway},
+		{"context canceled", fmt.Errorf("%w: context canceled", domain.ErrContextCanceled), StatusClientClosedRequest},
+		{"invalid query", fmt.Errorf("%w: negative limit -1", domain.ErrInvalidQuery), http.StatusBadRequest},
+		{"unknown error", errors.New("boom"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Configurar el mock para que devuelva el error del repositorio
+			mockService := new(MockMetricsService)
+			mockService.On("GetBooks", mock.Anything).Return(nil, tt.err)
+
+			handler := NewGetMetrics(mockService)
+
+			r := gin.New()
+			r.GET("/", handler.Handle())
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, req)
+
+			assert.Equal(t, tt.expectedStatus, res.Code)
+
+			var resBody map[string]interface{}
+			json.Unmarshal(res.Body.Bytes(), &resBody)
+			assert.Contains(t, resBody, "error")
+
+			// Las métricas no deben calcularse si falló la obtención de libros
+			mockService.AssertExpectations(t)
+			mockService.AssertNotCalled(t, "GetMeanUnitsSold", mock.Anything)
+		})
+	}
+}
+
+func TestGetMetrics_ReportsCacheAge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMetricsService)
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	}
+
+	// Simular un repositorio que sirve datos vencidos desde la caché
+	mockService.On("GetBooks", mock.Anything).Run(func(args mock.Arguments) {
+		domain.RecordFetchInfo(args.Get(0).(context.Context), func(info *domain.FetchInfo) {
+			info.Cached = true
+			info.CacheAge = 42 * time.Second
+			info.Stale = true
+		})
+	}).Return(testBooks, nil)
+	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(15000))
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
+	mockService.On("GetBooksWrittenByAuthor", testBooks, "").Return(uint(0))
+
+	handler := NewGetMetrics(mockService)
+
+	r := gin.New()
+	r.GET("/", handler.Handle())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "42", res.Header().Get("Age"))
+
+	var resBody map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &resBody)
+	cache := resBody["cache"].(map[string]interface{})
+	assert.Equal(t, 42, int(cache["age_seconds"].(float64)))
+	assert.Equal(t, true, cache["stale"])
+}
+
+func TestGetMetrics_ReportsPartialCatalog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMetricsService)
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	}
+
+	// Simular un repositorio que sólo pudo obtener parte del catálogo
+	mockService.On("GetBooks", mock.Anything).Run(func(args mock.Arguments) {
+		domain.RecordFetchInfo(args.Get(0).(context.Context), func(info *domain.FetchInfo) {
+			info.Partial = true
+			info.Warnings = append(info.Warnings, "partial catalog (1 books): page 1: upstream unavailable")
+		})
+	}).Return(testBooks, nil)
+	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(15000))
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
+	mockService.On("GetBooksWrittenByAuthor", testBooks, "").Return(uint(0))
+
+	handler := NewGetMetrics(mockService)
+
+	r := gin.New()
+	r.GET("/", handler.Handle())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var resBody map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &resBody)
+	assert.Equal(t, true, resBody["partial"])
+	assert.Len(t, resBody["warnings"], 1)
+	assert.NotContains(t, resBody, "cache")
+}
+
+func TestGetMetrics_ReportsSnapshot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMetricsService)
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	}
+	takenAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+
+	// Simular un repositorio que sirve un snapshot porque el proveedor está caído
+	mockService.On("GetBooks", mock.Anything).Run(func(args mock.Arguments) {
+		domain.RecordFetchInfo(args.Get(0).(context.Context), func(info *domain.FetchInfo) {
+			info.FromSnapshot = true
+			info.SnapshotAt = takenAt
+		})
+	}).Return(testBooks, nil)
+	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(15000))
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
+	mockService.On("GetBooksWrittenByAuthor", testBooks, "").Return(uint(0))
+
+	handler := NewGetMetrics(mockService)
+
+	r := gin.New()
+	r.GET("/", handler.Handle())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var resBody map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &resBody)
+	snapshot := resBody["snapshot"].(map[string]interface{})
+	assert.Equal(t, "2024-05-01T10:30:00Z", snapshot["taken_at"])
+}
+
+func TestGetMetrics_ReportsFallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMetricsService)
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	}
+
+	// Simular un circuit breaker abierto que sirve el último catálogo válido
+	mockService.On("GetBooks", mock.Anything).Run(func(args mock.Arguments) {
+		domain.RecordFetchInfo(args.Get(0).(context.Context), func(info *domain.FetchInfo) {
+			info.Fallback = true
+			info.Warnings = append(info.Warnings, "circuit open: serving last good catalog")
+		})
+	}).Return(testBooks, nil)
+	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(15000))
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
+	mockService.On("GetBooksWrittenByAuthor", testBooks, "").Return(uint(0))
+
+	r := gin.New()
+	r.GET("/", NewGetMetrics(mockService).Handle())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	var resBody map[string]interface{}
+	json.Unmarshal(res.Body.Bytes(), &resBody)
+	assert.Equal(t, true, resBody["fallback"])
+	assert.Equal(t, []interface{}{"circuit open: serving last good catalog"}, resBody["warnings"])
+}
+
+func TestGetMetrics_CountsAuthorOnFetchedCatalog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockMetricsService)
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	}
+
+	// El conteo por autor sale del catálogo ya obtenido: una segunda consulta al proveedor
+	// podría fallar o responder con otra versión del catálogo
+	mockService.On("GetBooks", mock.Anything).Return(testBooks, nil)
+	mockService.On("GetMeanUnitsSold", testBooks).Return(uint(15000))
+	mockService.On("GetCheapestBook", testBooks).Return(testBooks[0])
+	mockService.On("GetCheapestBookByCurrency", testBooks).Return(map[domain.Currency]domain.Book{domain.DefaultCurrency: testBooks[0]})
+	mockService.On("GetBooksWrittenByAuthor", testBooks, "Robert C. Martin").Return(uint(1))
+
+	r := gin.New()
+	r.GET("/", NewGetMetrics(mockService).Handle())
+
+	req := httptest.NewRequest(http.MethodGet, "/?author=Robert+C.+Martin", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	var resBody map[string]interface{}
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &resBody))
+	assert.Equal(t, float64(1), resBody["books_written_by_author"])
+	mockService.AssertNotCalled(t, "CountBooksWrittenByAuthor", mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "QueryBooks", mock.Anything, mock.Anything)
+}
@@ -11,24 +11,32 @@ import (
 	"time"
 
 	"educabot.com/bookshop/internal/adapters/handlers"
-	"educabot.com/bookshop/internal/adapters/repositories/http"
 	"educabot.com/bookshop/internal/core/services"
+	booksrepo "educabot.com/bookshop/internal/repositories/http"
+	"educabot.com/bookshop/internal/repositories/http/fixture"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
-// TestHandlerIntegration prueba el handler con datos reales
+// mockapiFixture tiene las respuestas grabadas de la API real; se comparte con el paquete del repositorio
+const mockapiFixture = "../../repositories/http/testdata/fixtures/mockapi_books.json"
+
+// TestHandlerIntegration prueba el handler con datos reales grabados
 // Para ejecutar esta prueba: go test -tags=integration ./internal/adapters/handlers
+// Para volver a grabar contra la API real: BOOKS_FIXTURES_MODE=record go test -tags=integration ./internal/adapters/handlers
 func TestHandlerIntegration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	mode, err := fixture.ModeFromEnv()
+	assert.NoError(t, err)
+	transport, err := fixture.New(fixture.Config{Path: mockapiFixture, Mode: mode})
+	assert.NoError(t, err, "El fixture de la API debería poder leerse")
+
 	// Crear un cliente HTTP con timeout
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := transport.Client(5 * time.Second)
 
 	// Crear el repositorio real de libros
-	booksRepository := http.NewHTTPBooksRepositoryWithConfig(
+	booksRepository := booksrepo.NewHTTPBooksRepositoryWithConfig(
 		client,
 		"https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books",
 	)
@@ -59,7 +67,7 @@ func TestHandlerIntegration(t *testing.T) {
 
 	// Verificar que el cuerpo tiene el formato esperado
 	var resBody map[string]interface{}
-	err := json.Unmarshal(res.Body.Bytes(), &resBody)
+	err = json.Unmarshal(res.Body.Bytes(), &resBody)
 	assert.NoError(t, err, "El cuerpo debería ser JSON válido")
 
 	// Verificar que los campos esperados están presentes
@@ -72,13 +80,15 @@ func TestHandlerIntegration(t *testing.T) {
 func TestHandlerTimeout(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
+	// Reproducir la respuesta con la latencia grabada para que el timeout no dependa de la red
+	transport, err := fixture.New(fixture.Config{Path: mockapiFixture, ReplayLatency: true})
+	assert.NoError(t, err, "El fixture de la API debería poder leerse")
+
 	// Crear un cliente HTTP con un timeout muy corto
-	client := &http.Client{
-		Timeout: 1 * time.Millisecond, // Timeout demasiado corto para completar
-	}
+	client := transport.Client(1 * time.Millisecond) // Timeout demasiado corto para completar
 
 	// Crear el repositorio real de libros con un timeout que causará fallo
-	booksRepository := http.NewHTTPBooksRepositoryWithConfig(
+	booksRepository := booksrepo.NewHTTPBooksRepositoryWithConfig(
 		client,
 		"https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books",
 	)
@@ -99,7 +109,7 @@ func TestHandlerTimeout(t *testing.T) {
 	r.ServeHTTP(res, req)
 
 	// Verificar que la respuesta indica un error
-	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, http.StatusGatewayTimeout, res.Code)
 
 	// Verificar que el mensaje de error es el esperado
 	var resBody map[string]interface{}
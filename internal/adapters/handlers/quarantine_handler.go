@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// GetQuarantine es el handler que expone los libros rechazados por la validación
+type GetQuarantine struct {
+	source ports.QuarantineSource
+}
+
+// NewGetQuarantine crea una nueva instancia del handler de cuarentena
+// Si la fuente de libros no valida sus datos, source puede ser nil y la cuarentena siempre está vacía
+func NewGetQuarantine(source ports.QuarantineSource) GetQuarantine {
+	return GetQuarantine{source}
+}
+
+// Handle devuelve la función de controlador para Gin
+func (h GetQuarantine) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		books := []domain.QuarantinedBook{}
+		if h.source != nil {
+			if quarantined := h.source.Quarantined(); quarantined != nil {
+				books = quarantined
+			}
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{
+			"count": len(books),
+			"books": books,
+		})
+	}
+}
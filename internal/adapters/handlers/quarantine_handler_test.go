@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubQuarantineSource devuelve siempre la misma cuarentena
+type stubQuarantineSource struct {
+	books []domain.QuarantinedBook
+}
+
+func (s stubQuarantineSource) Quarantined() []domain.QuarantinedBook {
+	return s.books
+}
+
+func serveQuarantine(t *testing.T, handler GetQuarantine) map[string]interface{} {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/quarantine", handler.Handle())
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/quarantine", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var resBody map[string]interface{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &resBody))
+	return resBody
+}
+
+func TestGetQuarantine_ListsBooksWithReasons(t *testing.T) {
+	source := stubQuarantineSource{books: []domain.QuarantinedBook{{
+		Book:          domain.Book{ID: 2, Author: "Author", Price: domain.Price(10)},
+		Index:         1,
+		Reasons:       []string{"required_fields: missing name"},
+		QuarantinedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}}
+
+	resBody := serveQuarantine(t, NewGetQuarantine(source))
+
+	assert.Equal(t, 1, int(resBody["count"].(float64)))
+	books := resBody["books"].([]interface{})
+	require.Len(t, books, 1)
+	entry := books[0].(map[string]interface{})
+	assert.Equal(t, 2, int(entry["book"].(map[string]interface{})["id"].(float64)))
+	assert.Equal(t, []interface{}{"required_fields: missing name"}, entry["reasons"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", entry["quarantined_at"])
+}
+
+func TestGetQuarantine_WithoutSource(t *testing.T) {
+	resBody := serveQuarantine(t, NewGetQuarantine(nil))
+
+	assert.Equal(t, 0, int(resBody["count"].(float64)))
+	assert.Equal(t, []interface{}{}, resBody["books"])
+}
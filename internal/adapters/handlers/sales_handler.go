@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// PublishSale es el handler que publica una venta en el broker de eventos
+// Sirve para registrar ventas cuando el broker es el embebido, sin un sistema de ventas que publique
+type PublishSale struct {
+	publisher ports.SalesPublisher
+	now       func() time.Time
+}
+
+// NewPublishSale crea una nueva instancia del handler de publicación de ventas
+func NewPublishSale(publisher ports.SalesPublisher) PublishSale {
+	return PublishSale{publisher: publisher, now: time.Now}
+}
+
+// Handle devuelve la función de controlador para Gin
+// Sin "ts" se usa la fecha actual; la venta se aplica a las métricas cuando el consumidor la procesa
+func (h PublishSale) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var event domain.BookSold
+		if err := ctx.ShouldBindJSON(&event); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sale event", "detail": err.Error()})
+			return
+		}
+		if event.Timestamp.IsZero() {
+			event.Timestamp = h.now().UTC()
+		}
+
+		if err := h.publisher.PublishSale(ctx.Request.Context(), event); err != nil {
+			if errors.Is(err, domain.ErrInvalidSale) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sale event", "detail": err.Error()})
+				return
+			}
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "Could not publish sale event", "detail": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusAccepted, event)
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSalesPublisher guarda los eventos publicados y valida como el publicador real
+type stubSalesPublisher struct {
+	events []domain.BookSold
+	err    error
+}
+
+func (s *stubSalesPublisher) PublishSale(_ context.Context, event domain.BookSold) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func salesRouter(publisher *stubSalesPublisher, now time.Time) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewPublishSale(publisher)
+	handler.now = func() time.Time { return now }
+	r.POST("/sales/events", handler.Handle())
+	return r
+}
+
+func TestPublishSale_Publishes(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	publisher := &stubSalesPublisher{}
+	r := salesRouter(publisher, now)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/sales/events", strings.NewReader(`{"event_id":"sale-1","book_id":2,"qty":3}`)))
+	require.Equal(t, http.StatusAccepted, res.Code)
+
+	// Sin ts se completa con la fecha actual
+	expected := domain.BookSold{EventID: "sale-1", BookID: 2, Qty: 3, Timestamp: now}
+	assert.Equal(t, []domain.BookSold{expected}, publisher.events)
+	var body domain.BookSold
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, expected, body)
+}
+
+func TestPublishSale_InvalidEvent(t *testing.T) {
+	publisher := &stubSalesPublisher{}
+	r := salesRouter(publisher, time.Now())
+
+	for _, body := range []string{`{"event_id":"sale-1","book_id":2}`, `{"book_id":"two"}`} {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/sales/events", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, res.Code, body)
+	}
+	assert.Empty(t, publisher.events)
+}
+
+func TestPublishSale_BrokerUnavailable(t *testing.T) {
+	publisher := &stubSalesPublisher{err: errors.New("broker closed")}
+	r := salesRouter(publisher, time.Now())
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/sales/events", strings.NewReader(`{"event_id":"sale-1","book_id":2,"qty":1}`)))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUpstreamInfo es el handler de diagnóstico que muestra cómo se conecta el servicio con el proveedor
+type GetUpstreamInfo struct {
+	inspector ports.UpstreamInspector
+}
+
+// NewGetUpstreamInfo crea una nueva instancia del handler de diagnóstico
+func NewGetUpstreamInfo(inspector ports.UpstreamInspector) GetUpstreamInfo {
+	return GetUpstreamInfo{inspector}
+}
+
+// Handle devuelve la función de controlador para Gin
+func (h GetUpstreamInfo) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, h.inspector.UpstreamInfo())
+	}
+}
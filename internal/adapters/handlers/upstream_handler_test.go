@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubUpstreamInspector devuelve siempre la misma descripción
+type stubUpstreamInspector map[string]any
+
+func (s stubUpstreamInspector) UpstreamInfo() map[string]any {
+	return s
+}
+
+func TestGetUpstreamInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/debug/upstream", NewGetUpstreamInfo(stubUpstreamInspector{
+		"url":       "https://books.example/api",
+		"transport": map[string]any{"http2": true, "timeout": "10s"},
+	}).Handle())
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/debug/upstream", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	var resBody map[string]interface{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &resBody))
+	assert.Equal(t, "https://books.example/api", resBody["url"])
+	assert.Equal(t, map[string]interface{}{"http2": true, "timeout": "10s"}, resBody["transport"])
+}
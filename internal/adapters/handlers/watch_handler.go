@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"educabot.com/bookshop/internal/core/ports"
+	"github.com/gin-gonic/gin"
+)
+
+// keepAliveInterval es el tiempo entre comentarios enviados para que los proxies no corten el stream
+const keepAliveInterval = 15 * time.Second
+
+// WatchBooks es el handler que transmite los cambios del catálogo como Server-Sent Events
+type WatchBooks struct {
+	repository ports.WatchableBooksRepository
+}
+
+// NewWatchBooks crea una nueva instancia del handler de cambios del catálogo
+func NewWatchBooks(repository ports.WatchableBooksRepository) WatchBooks {
+	return WatchBooks{repository}
+}
+
+// Handle devuelve la función de controlador para Gin
+// Cada evento se envía con el tipo (added, updated, removed) como nombre y el BookEvent en JSON como dato
+// Si la suscripción se corta por lentitud, el stream termina y el cliente debe reconectarse
+func (h WatchBooks) Handle() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		events := h.repository.Watch(ctx.Request.Context())
+
+		ctx.Header("Content-Type", "text/event-stream")
+		ctx.Header("Cache-Control", "no-cache")
+		ctx.Header("Connection", "keep-alive")
+		ctx.Status(http.StatusOK)
+		ctx.Writer.Flush()
+
+		keepAlive := time.NewTicker(keepAliveInterval)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-ctx.Request.Context().Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				ctx.SSEvent(string(event.Type), event)
+			case <-keepAlive.C:
+				io.WriteString(ctx.Writer, ": keep-alive\n\n")
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
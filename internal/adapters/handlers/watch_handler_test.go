@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/memory"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchBooks_StreamsEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repository := memory.NewMemoryBooksRepository()
+
+	r := gin.New()
+	r.GET("/books/events", NewWatchBooks(repository).Handle())
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/books/events", nil)
+	require.NoError(t, err)
+	res, err := server.Client().Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	// La suscripción ya está activa cuando llegan los headers
+	_, err = repository.CreateBook(context.Background(), domain.Book{Name: "New Book", Author: "Jane Doe", Price: domain.Price(10)})
+	require.NoError(t, err)
+
+	reader := bufio.NewReader(res.Body)
+	var name, data string
+	for name == "" || data == "" {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if value, ok := strings.CutPrefix(line, "event:"); ok {
+			name = strings.TrimSpace(value)
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = strings.TrimSpace(value)
+		}
+	}
+
+	assert.Equal(t, "added", name)
+	var event domain.BookEvent
+	require.NoError(t, json.Unmarshal([]byte(data), &event))
+	assert.Equal(t, domain.BookAdded, event.Type)
+	assert.Equal(t, "New Book", event.After.Name)
+}
@@ -6,5 +6,9 @@ type Book struct {
 	Name      string `json:"name"`
 	Author    string `json:"author"`
 	UnitsSold uint   `json:"units_sold"`
-	Price     uint   `json:"price"`
+	// Price acepta en JSON tanto el objeto de Money como un número en unidades de DefaultCurrency
+	Price Money `json:"price"`
+	// Version se incrementa en cada modificación para el control de concurrencia optimista
+	// Los libros de fuentes de sólo lectura no tienen versión (0)
+	Version uint64 `json:"version,omitempty"`
 }
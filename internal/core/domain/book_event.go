@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// BookEventType es el tipo de cambio que describe un BookEvent
+type BookEventType string
+
+const (
+	BookAdded   BookEventType = "added"
+	BookUpdated BookEventType = "updated"
+	BookRemoved BookEventType = "removed"
+)
+
+// BookEvent describe un cambio en el catálogo
+// Before es nil en los altas y After es nil en las bajas, de modo que quien consume
+// los eventos puede ajustar sus agregados sin volver a leer el catálogo
+type BookEvent struct {
+	Type   BookEventType `json:"type"`
+	Before *Book         `json:"before,omitempty"`
+	After  *Book         `json:"after,omitempty"`
+	At     time.Time     `json:"at"`
+}
+
+// BookID devuelve el ID del libro afectado por el evento
+func (e BookEvent) BookID() uint {
+	if e.After != nil {
+		return e.After.ID
+	}
+	if e.Before != nil {
+		return e.Before.ID
+	}
+	return 0
+}
+
+// DiffBooks compara dos versiones del catálogo y devuelve los eventos que llevan de una a otra,
+// ordenados por ID. Los libros se identifican por ID; si un ID se repite, cuenta la última aparición
+func DiffBooks(before, after []Book, at time.Time) []BookEvent {
+	previous := make(map[uint]Book, len(before))
+	for _, book := range before {
+		previous[book.ID] = book
+	}
+	current := make(map[uint]Book, len(after))
+	for _, book := range after {
+		current[book.ID] = book
+	}
+
+	var events []BookEvent
+	for id, book := range current {
+		book := book
+		old, existed := previous[id]
+		switch {
+		case !existed:
+			events = append(events, BookEvent{Type: BookAdded, After: &book, At: at})
+		case old != book:
+			events = append(events, BookEvent{Type: BookUpdated, Before: &old, After: &book, At: at})
+		}
+	}
+	for id, book := range previous {
+		book := book
+		if _, exists := current[id]; !exists {
+			events = append(events, BookEvent{Type: BookRemoved, Before: &book, At: at})
+		}
+	}
+
+	slices.SortFunc(events, func(a, b BookEvent) int {
+		return cmp.Compare(a.BookID(), b.BookID())
+	})
+	return events
+}
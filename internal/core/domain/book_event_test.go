@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiffBooks(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	before := []Book{
+		{ID: 1, Name: "Book 1", Author: "A", Price: Price(10)},
+		{ID: 2, Name: "Book 2", Author: "B", Price: Price(20)},
+		{ID: 3, Name: "Book 3", Author: "C", Price: Price(30)},
+	}
+	after := []Book{
+		{ID: 4, Name: "Book 4", Author: "D", Price: Price(40)},
+		{ID: 2, Name: "Book 2", Author: "B", Price: Price(25)},
+		{ID: 1, Name: "Book 1", Author: "A", Price: Price(10)},
+	}
+
+	events := DiffBooks(before, after, at)
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %d: %+v", len(events), events)
+	}
+	if e := events[0]; e.Type != BookUpdated || e.Before.Price != Price(20) || e.After.Price != Price(25) || !e.At.Equal(at) {
+		t.Errorf("Expected book 2 updated from 20 to 25, got %+v", e)
+	}
+	if e := events[1]; e.Type != BookRemoved || e.BookID() != 3 || e.After != nil {
+		t.Errorf("Expected book 3 removed, got %+v", e)
+	}
+	if e := events[2]; e.Type != BookAdded || e.BookID() != 4 || e.Before != nil {
+		t.Errorf("Expected book 4 added, got %+v", e)
+	}
+
+	if events := DiffBooks(before, before, at); len(events) != 0 {
+		t.Errorf("Expected no events for an unchanged catalog, got %+v", events)
+	}
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LatencyDistribution indica cómo se sortea la demora inyectada
+type LatencyDistribution string
+
+const (
+	// LatencyNone no agrega demora
+	LatencyNone LatencyDistribution = ""
+	// LatencyFixed demora siempre BaseMS
+	LatencyFixed LatencyDistribution = "fixed"
+	// LatencyUniform demora entre BaseMS y BaseMS+JitterMS con probabilidad uniforme
+	LatencyUniform LatencyDistribution = "uniform"
+	// LatencyNormal demora BaseMS más una desviación normal con desvío estándar JitterMS (nunca menos de cero)
+	LatencyNormal LatencyDistribution = "normal"
+	// LatencyExponential demora BaseMS más una cola exponencial de media JitterMS, como los picos de un proveedor lento
+	LatencyExponential LatencyDistribution = "exponential"
+)
+
+// Latency describe la demora inyectada en cada llamada, en milisegundos
+type Latency struct {
+	Distribution LatencyDistribution `json:"distribution,omitempty"`
+	BaseMS       int64               `json:"base_ms,omitempty"`
+	JitterMS     int64               `json:"jitter_ms,omitempty"`
+}
+
+// Base devuelve la demora mínima
+func (l Latency) Base() time.Duration {
+	return time.Duration(l.BaseMS) * time.Millisecond
+}
+
+// Jitter devuelve la escala de la parte aleatoria de la demora
+func (l Latency) Jitter() time.Duration {
+	return time.Duration(l.JitterMS) * time.Millisecond
+}
+
+// ChaosError indica qué error se inyecta cuando una llamada falla
+type ChaosError string
+
+const (
+	// ChaosUnavailable simula un proveedor caído (ErrUpstreamUnavailable, o un 503 en el transporte)
+	ChaosUnavailable ChaosError = "unavailable"
+	// ChaosTimeout simula un proveedor que no responde a tiempo (ErrUpstreamTimeout, o un 504 en el transporte)
+	ChaosTimeout ChaosError = "timeout"
+	// ChaosMalformed simula una respuesta ilegible (ErrMalformedPayload, o un cuerpo que no es JSON en el transporte)
+	ChaosMalformed ChaosError = "malformed"
+)
+
+// ChaosConfig define las fallas que se inyectan para probar la resiliencia del servicio
+// Las tasas son probabilidades entre 0 y 1; ErrorRate y TruncateRate se sortean por llamada,
+// DuplicateRate y CorruptRate por libro
+type ChaosConfig struct {
+	Enabled bool `json:"enabled"`
+	// Seed inicializa el generador aleatorio para que una sesión pueda repetirse (0 usa la hora actual)
+	Seed    int64   `json:"seed,omitempty"`
+	Latency Latency `json:"latency"`
+	// ErrorRate es la probabilidad de que la llamada falle con Error (ChaosUnavailable si está vacío)
+	ErrorRate float64    `json:"error_rate,omitempty"`
+	Error     ChaosError `json:"error,omitempty"`
+	// TruncateRate es la probabilidad de devolver sólo una parte del catálogo
+	TruncateRate float64 `json:"truncate_rate,omitempty"`
+	// DuplicateRate es la probabilidad de que un libro aparezca dos veces
+	DuplicateRate float64 `json:"duplicate_rate,omitempty"`
+	// CorruptRate es la probabilidad de que un campo del libro se altere
+	CorruptRate float64 `json:"corrupt_rate,omitempty"`
+}
+
+// Validate verifica que las tasas sean probabilidades y que la latencia y el error sean conocidos
+func (c ChaosConfig) Validate() error {
+	var errs []error
+	rates := []struct {
+		name string
+		rate float64
+	}{
+		{"error_rate", c.ErrorRate},
+		{"truncate_rate", c.TruncateRate},
+		{"duplicate_rate", c.DuplicateRate},
+		{"corrupt_rate", c.CorruptRate},
+	}
+	for _, r := range rates {
+		if !(r.rate >= 0 && r.rate <= 1) {
+			errs = append(errs, fmt.Errorf("%s must be between 0 and 1, got %v", r.name, r.rate))
+		}
+	}
+	switch c.Latency.Distribution {
+	case LatencyNone, LatencyFixed, LatencyUniform, LatencyNormal, LatencyExponential:
+	default:
+		errs = append(errs, fmt.Errorf("unknown latency distribution %q", c.Latency.Distribution))
+	}
+	if c.Latency.BaseMS < 0 || c.Latency.JitterMS < 0 {
+		errs = append(errs, errors.New("latency base and jitter must not be negative"))
+	}
+	switch c.Error {
+	case "", ChaosUnavailable, ChaosTimeout, ChaosMalformed:
+	default:
+		errs = append(errs, fmt.Errorf("unknown chaos error %q", c.Error))
+	}
+	return errors.Join(errs...)
+}
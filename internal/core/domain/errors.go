@@ -0,0 +1,28 @@
+package domain
+
+import "errors"
+
+// Errores tipados que los repositorios de libros devuelven (envueltos con %w)
+// para que las capas superiores puedan distinguir la causa con errors.Is
+var (
+	// ErrUpstreamUnavailable indica que la fuente de datos no respondió o respondió con un estado de error
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	// ErrUpstreamTimeout indica que la fuente de datos no respondió dentro del plazo
+	ErrUpstreamTimeout = errors.New("upstream timeout")
+	// ErrMalformedPayload indica que la respuesta de la fuente de datos no pudo interpretarse
+	ErrMalformedPayload = errors.New("malformed upstream payload")
+	// ErrPayloadTooLarge indica que la respuesta de la fuente de datos supera el tamaño o la cantidad de libros permitidos
+	ErrPayloadTooLarge = errors.New("upstream payload too large")
+	// ErrContextCanceled indica que quien hizo la petición la canceló antes de completarse
+	ErrContextCanceled = errors.New("context canceled")
+	// ErrBookNotFound indica que no existe un libro con el ID indicado
+	ErrBookNotFound = errors.New("book not found")
+	// ErrBookExists indica que ya existe un libro con el ID indicado
+	ErrBookExists = errors.New("book already exists")
+	// ErrVersionConflict indica que el libro cambió desde la versión que quien escribe leyó
+	ErrVersionConflict = errors.New("book version conflict")
+	// ErrInvalidBook indica que los datos de un libro a escribir no son válidos
+	ErrInvalidBook = errors.New("invalid book")
+	// ErrInvalidQuery indica que una consulta sobre el catálogo no es válida
+	ErrInvalidQuery = errors.New("invalid books query")
+)
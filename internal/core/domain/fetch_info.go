@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// FetchInfo describe cómo se obtuvo el catálogo de libros para una petición
+// Los repositorios lo completan a través del contexto para que el handler pueda informarlo
+type FetchInfo struct {
+	// Cached indica que el catálogo se sirvió desde una caché
+	Cached bool
+	// CacheAge es la antigüedad de los datos servidos desde la caché
+	CacheAge time.Duration
+	// Stale indica que los datos servidos superaron su tiempo de vida
+	Stale bool
+	// FromSnapshot indica que el catálogo se sirvió desde un snapshot en disco porque la fuente falló
+	FromSnapshot bool
+	// SnapshotAt es el momento en que se tomó el snapshot servido
+	SnapshotAt time.Time
+	// NotModified indica que la fuente confirmó que el catálogo no cambió desde la obtención anterior
+	NotModified bool
+	// Partial indica que el catálogo está incompleto porque falló parte de la obtención
+	Partial bool
+	// Fallback indica que se sirvió un catálogo alternativo porque la fuente no estaba disponible
+	Fallback bool
+	// Warnings describe problemas no fatales ocurridos al obtener el catálogo
+	Warnings []string
+	// Provenance indica, para cada libro (por ID), de qué fuente proviene cada campo
+	// Sólo lo completan los repositorios que combinan varias fuentes
+	Provenance map[uint]FieldSources
+}
+
+// FieldSources asocia el nombre de cada campo de un libro (tag JSON) con la fuente de la que proviene
+type FieldSources map[string]string
+
+type fetchInfoKey struct{}
+
+// fetchInfoRecorder guarda el FetchInfo de una petición de forma segura para concurrencia
+type fetchInfoRecorder struct {
+	mu   sync.Mutex
+	info FetchInfo
+}
+
+// WithFetchInfo devuelve un contexto en el que los repositorios pueden registrar cómo obtuvieron los datos
+func WithFetchInfo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, fetchInfoKey{}, &fetchInfoRecorder{})
+}
+
+// RecordFetchInfo aplica update al FetchInfo del contexto; no hace nada si el contexto no lo tiene
+func RecordFetchInfo(ctx context.Context, update func(info *FetchInfo)) {
+	recorder, ok := ctx.Value(fetchInfoKey{}).(*fetchInfoRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	update(&recorder.info)
+}
+
+// FetchInfoFromContext devuelve una copia del FetchInfo registrado en el contexto
+func FetchInfoFromContext(ctx context.Context) (FetchInfo, bool) {
+	recorder, ok := ctx.Value(fetchInfoKey{}).(*fetchInfoRecorder)
+	if !ok {
+		return FetchInfo{}, false
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	info := recorder.info
+	info.Warnings = slices.Clone(info.Warnings)
+	info.Provenance = maps.Clone(info.Provenance)
+	return info, true
+}
+
+// Merge suma a i lo que registró otra obtención de la misma petición: las marcas se combinan,
+// las advertencias se acumulan y se conservan la antigüedad de caché y el snapshot más viejos
+func (i *FetchInfo) Merge(other FetchInfo) {
+	i.Cached = i.Cached || other.Cached
+	i.CacheAge = max(i.CacheAge, other.CacheAge)
+	i.Stale = i.Stale || other.Stale
+	i.FromSnapshot = i.FromSnapshot || other.FromSnapshot
+	if !other.SnapshotAt.IsZero() && (i.SnapshotAt.IsZero() || other.SnapshotAt.Before(i.SnapshotAt)) {
+		i.SnapshotAt = other.SnapshotAt
+	}
+	i.NotModified = i.NotModified || other.NotModified
+	i.Partial = i.Partial || other.Partial
+	i.Fallback = i.Fallback || other.Fallback
+	i.Warnings = append(i.Warnings, other.Warnings...)
+	for id, sources := range other.Provenance {
+		if i.Provenance == nil {
+			i.Provenance = make(map[uint]FieldSources, len(other.Provenance))
+		}
+		i.Provenance[id] = sources
+	}
+}
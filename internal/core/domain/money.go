@@ -0,0 +1,294 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// DefaultCurrency es la moneda de los precios expresados como un número sin moneda,
+// el formato que usaban los catálogos antes de Money
+const DefaultCurrency Currency = "USD"
+
+var (
+	// ErrInvalidMoney indica un importe o una moneda que no pueden interpretarse o que desbordan int64
+	ErrInvalidMoney = errors.New("invalid money")
+	// ErrCurrencyMismatch indica una operación entre importes de distintas monedas
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Currency es un código de moneda ISO 4217, por ejemplo "USD" o "ARS"
+type Currency string
+
+// currencyExponents son las monedas cuya cantidad de decimales no es 2
+var currencyExponents = map[Currency]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// Exponent devuelve la cantidad de decimales de la moneda (2 para la mayoría, 0 para JPY, 3 para KWD)
+func (c Currency) Exponent() int {
+	if exponent, ok := currencyExponents[c]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// Validate verifica que el código tenga la forma de ISO 4217: tres letras mayúsculas
+func (c Currency) Validate() error {
+	if len(c) != 3 {
+		return fmt.Errorf("%w: currency %q is not an ISO 4217 code", ErrInvalidMoney, string(c))
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return fmt.Errorf("%w: currency %q is not an ISO 4217 code", ErrInvalidMoney, string(c))
+		}
+	}
+	return nil
+}
+
+// ParseCurrency interpreta un código de moneda sin distinguir mayúsculas
+func ParseCurrency(text string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(text)))
+	if err := currency.Validate(); err != nil {
+		return "", err
+	}
+	return currency, nil
+}
+
+// Money es un importe en unidades menores de su moneda (centavos para USD): 12.99 USD es {1299, "USD"}
+// Los importes se guardan como enteros para que las sumas y comparaciones sean exactas
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// NewMoney crea un importe a partir de unidades menores
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Price crea un precio de units unidades enteras de DefaultCurrency, como se expresaban los precios
+// antes de Money; sirve para catálogos fijos, los valores externos se convierten con ParseMoney
+func Price(units uint) Money {
+	return Money{Amount: int64(units) * pow10(DefaultCurrency.Exponent()), Currency: DefaultCurrency}
+}
+
+// decimalPattern acepta números decimales con exponente opcional, como los de JSON
+var decimalPattern = regexp.MustCompile(`^[+-]?[0-9]+(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// ParseMoney interpreta un importe en unidades enteras con la moneda opcional antes o después:
+// "12.99", "12.99 USD" o "ARS 1500"; sin moneda se usa DefaultCurrency
+// Es un error que el importe tenga más decimales de los que admite la moneda
+func ParseMoney(text string) (Money, error) {
+	fields := strings.Fields(text)
+	currency := DefaultCurrency
+	var amount string
+	switch len(fields) {
+	case 1:
+		amount = fields[0]
+	case 2:
+		amount = fields[0]
+		code := fields[1]
+		if !decimalPattern.MatchString(amount) {
+			amount, code = fields[1], fields[0]
+		}
+		parsed, err := ParseCurrency(code)
+		if err != nil {
+			return Money{}, err
+		}
+		currency = parsed
+	default:
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, text)
+	}
+	return parseAmount(amount, currency)
+}
+
+// parseAmount convierte un número en unidades enteras a unidades menores de la moneda
+func parseAmount(text string, currency Currency) (Money, error) {
+	if !decimalPattern.MatchString(text) {
+		return Money{}, fmt.Errorf("%w: %q is not a number", ErrInvalidMoney, text)
+	}
+	value, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q is not a number", ErrInvalidMoney, text)
+	}
+	value.Mul(value, new(big.Rat).SetInt64(pow10(currency.Exponent())))
+	if !value.IsInt() {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimals for %s", ErrInvalidMoney, text, currency.Exponent(), currency)
+	}
+	if !value.Num().IsInt64() {
+		return Money{}, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, text)
+	}
+	return Money{Amount: value.Num().Int64(), Currency: currency}, nil
+}
+
+// Validate verifica que la moneda sea un código válido
+func (m Money) Validate() error {
+	return m.Currency.Validate()
+}
+
+// IsZero indica si el importe es el valor cero de Money, sin moneda
+func (m Money) IsZero() bool {
+	return m == Money{}
+}
+
+// Add suma dos importes de la misma moneda
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s overflows", ErrInvalidMoney, m, other)
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub resta dos importes de la misma moneda
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s - %s overflows", ErrInvalidMoney, m, other)
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Mul multiplica el importe por una cantidad, por ejemplo para el total de varias unidades
+func (m Money) Mul(quantity int64) (Money, error) {
+	if m.Amount == 0 || quantity == 0 {
+		return Money{Amount: 0, Currency: m.Currency}, nil
+	}
+	product := m.Amount * quantity
+	if product/quantity != m.Amount || (m.Amount == -1 && quantity == math.MinInt64) || (quantity == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s * %d overflows", ErrInvalidMoney, m, quantity)
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Compare devuelve -1, 0 o 1 según m sea menor, igual o mayor que other
+// Importes de distintas monedas no se comparan: devuelve ErrCurrencyMismatch
+func (m Money) Compare(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// CompareMoney ordena importes de cualquier moneda: primero por moneda y luego por importe
+// Sirve para ordenar de forma determinista; no dice nada sobre el valor relativo entre monedas
+func CompareMoney(a, b Money) int {
+	switch {
+	case a.Currency != b.Currency:
+		return strings.Compare(string(a.Currency), string(b.Currency))
+	case a.Amount < b.Amount:
+		return -1
+	case a.Amount > b.Amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// Decimal devuelve el importe en unidades enteras con los decimales de la moneda, por ejemplo "12.99"
+func (m Money) Decimal() string {
+	exponent := m.Currency.Exponent()
+	sign := ""
+	// Trabajar con el valor absoluto en uint64 para no desbordar con math.MinInt64
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if exponent == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+	scale := uint64(pow10(exponent))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/scale, exponent, amount%scale)
+}
+
+// String formatea el importe con su código de moneda, por ejemplo "12.99 USD"
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+// UnmarshalJSON acepta el objeto {"amount": 1299, "currency": "USD"} (sin moneda se usa DefaultCurrency)
+// y, por compatibilidad, un número en unidades enteras de DefaultCurrency (12.99) o un texto como "12.99 USD"
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '{':
+		// moneyFields evita la recursión a este método
+		type moneyFields Money
+		var fields moneyFields
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		money := Money(fields)
+		// El valor cero se conserva tal cual para que codificar y decodificar devuelva lo mismo
+		if money.IsZero() {
+			*m = money
+			return nil
+		}
+		if money.Currency == "" {
+			money.Currency = DefaultCurrency
+		}
+		if err := money.Validate(); err != nil {
+			return err
+		}
+		*m = money
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		money, err := ParseMoney(text)
+		if err != nil {
+			return err
+		}
+		*m = money
+		return nil
+	default:
+		money, err := parseAmount(string(data), DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		*m = money
+		return nil
+	}
+}
+
+// pow10 devuelve 10^exponent para los exponentes de las monedas (0 a 4)
+func pow10(exponent int) int64 {
+	result := int64(1)
+	for i := 0; i < exponent; i++ {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected Money
+	}{
+		{"12.99", NewMoney(1299, "USD")},
+		{"12.99 USD", NewMoney(1299, "USD")},
+		{"ARS 1500", NewMoney(150000, "ARS")},
+		{"8.5 eur", NewMoney(850, "EUR")},
+		{"1500 JPY", NewMoney(1500, "JPY")},
+		{"1.234 KWD", NewMoney(1234, "KWD")},
+		{"1e2", NewMoney(10000, "USD")},
+	}
+	for _, tt := range tests {
+		money, err := ParseMoney(tt.text)
+		if err != nil {
+			t.Errorf("%q: expected no error, got %v", tt.text, err)
+			continue
+		}
+		if money != tt.expected {
+			t.Errorf("%q: expected %+v, got %+v", tt.text, tt.expected, money)
+		}
+	}
+}
+
+func TestParseMoney_Invalid(t *testing.T) {
+	for _, text := range []string{"", "abc", "12.999", "10.5 JPY", "12 US", "12 USD extra", "99999999999999999999"} {
+		if _, err := ParseMoney(text); !errors.Is(err, ErrInvalidMoney) {
+			t.Errorf("%q: expected ErrInvalidMoney, got %v", text, err)
+		}
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a, b := NewMoney(1299, "USD"), NewMoney(701, "USD")
+
+	if sum, err := a.Add(b); err != nil || sum != NewMoney(2000, "USD") {
+		t.Errorf("Expected 20.00 USD, got %v (%v)", sum, err)
+	}
+	if diff, err := b.Sub(a); err != nil || diff != NewMoney(-598, "USD") {
+		t.Errorf("Expected -5.98 USD, got %v (%v)", diff, err)
+	}
+	if total, err := a.Mul(3); err != nil || total != NewMoney(3897, "USD") {
+		t.Errorf("Expected 38.97 USD, got %v (%v)", total, err)
+	}
+	if _, err := a.Add(NewMoney(100, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := NewMoney(math.MaxInt64, "USD").Add(NewMoney(1, "USD")); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("Expected an overflow error, got %v", err)
+	}
+	if _, err := NewMoney(math.MaxInt64/2+1, "USD").Mul(2); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("Expected an overflow error, got %v", err)
+	}
+}
+
+func TestMoney_Compare(t *testing.T) {
+	if c, err := Price(10).Compare(Price(20)); err != nil || c != -1 {
+		t.Errorf("Expected -1, got %d (%v)", c, err)
+	}
+	if _, err := Price(10).Compare(NewMoney(1000, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+	}
+	// Entre monedas distintas el orden es por código de moneda
+	if c := CompareMoney(NewMoney(100000, "ARS"), Price(1)); c != -1 {
+		t.Errorf("Expected ARS before USD, got %d", c)
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := map[string]Money{
+		"12.99 USD": NewMoney(1299, "USD"),
+		"0.05 USD":  NewMoney(5, "USD"),
+		"-1.50 EUR": NewMoney(-150, "EUR"),
+		"1500 JPY":  NewMoney(1500, "JPY"),
+		"1.234 KWD": NewMoney(1234, "KWD"),
+		"0.00":      {},
+	}
+	for expected, money := range tests {
+		if got := money.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	tests := map[string]Money{
+		`12.99`:                               NewMoney(1299, "USD"),
+		`15`:                                  Price(15),
+		`"1500 ARS"`:                          NewMoney(150000, "ARS"),
+		`{"amount": 1299, "currency": "EUR"}`: NewMoney(1299, "EUR"),
+		`{"amount": 1299}`:                    NewMoney(1299, "USD"),
+		`{"amount": 0, "currency": ""}`:       {},
+	}
+	for data, expected := range tests {
+		var money Money
+		if err := json.Unmarshal([]byte(data), &money); err != nil {
+			t.Errorf("%s: expected no error, got %v", data, err)
+			continue
+		}
+		if money != expected {
+			t.Errorf("%s: expected %+v, got %+v", data, expected, money)
+		}
+	}
+
+	for _, data := range []string{`"free"`, `12.999`, `{"amount": 1, "currency": "usd"}`, `true`} {
+		var money Money
+		if err := json.Unmarshal([]byte(data), &money); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestMoney_JSONRoundTrip(t *testing.T) {
+	book := Book{ID: 1, Name: "Book", Author: "Author", Price: NewMoney(1500, "JPY")}
+
+	data, err := json.Marshal(book)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var decoded Book
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded != book {
+		t.Errorf("Expected %+v, got %+v", book, decoded)
+	}
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// QuarantinedBook es un libro rechazado por la validación junto con los motivos
+type QuarantinedBook struct {
+	Book Book `json:"book"`
+	// Index es la posición del libro en la respuesta de la fuente
+	Index int `json:"index"`
+	// Reasons describe cada regla incumplida, con el formato "regla: motivo"
+	Reasons       []string  `json:"reasons"`
+	QuarantinedAt time.Time `json:"quarantined_at"`
+}
@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// SortField es el campo por el que se ordena el resultado de una consulta
+type SortField string
+
+const (
+	// SortByID ordena por ID (el orden por defecto)
+	SortByID        SortField = "id"
+	SortByName      SortField = "name"
+	SortByAuthor    SortField = "author"
+	SortByUnitsSold SortField = "units_sold"
+	SortByPrice     SortField = "price"
+)
+
+// BooksQuery describe un filtro sobre el catálogo
+// Los valores cero no filtran: un máximo en 0 significa sin límite superior
+type BooksQuery struct {
+	// Author filtra por coincidencia exacta con el autor
+	Author string
+	// MinPrice y MaxPrice filtran por precio; si alguno está definido sólo se incluyen
+	// los libros en su moneda, porque precios de monedas distintas no son comparables
+	MinPrice     Money
+	MaxPrice     Money
+	MinUnitsSold uint
+	MaxUnitsSold uint
+	// IDs restringe el resultado a los libros con esos IDs
+	IDs []uint
+	// SortBy es el campo de orden; vacío equivale a SortByID
+	SortBy     SortField
+	Descending bool
+	// Limit es la cantidad máxima de libros devueltos (0 sin límite)
+	Limit int
+}
+
+// Validate verifica que la consulta sea coherente
+func (q BooksQuery) Validate() error {
+	switch q.SortBy {
+	case "", SortByID, SortByName, SortByAuthor, SortByUnitsSold, SortByPrice:
+	default:
+		return fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, q.SortBy)
+	}
+	for _, price := range []Money{q.MinPrice, q.MaxPrice} {
+		if !price.IsZero() {
+			if err := price.Validate(); err != nil {
+				return fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+			}
+		}
+	}
+	if !q.MinPrice.IsZero() && !q.MaxPrice.IsZero() {
+		c, err := q.MinPrice.Compare(q.MaxPrice)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+		}
+		if c > 0 {
+			return fmt.Errorf("%w: min price %s is above max price %s", ErrInvalidQuery, q.MinPrice, q.MaxPrice)
+		}
+	}
+	if q.MaxUnitsSold > 0 && q.MinUnitsSold > q.MaxUnitsSold {
+		return fmt.Errorf("%w: min units sold %d is above max units sold %d", ErrInvalidQuery, q.MinUnitsSold, q.MaxUnitsSold)
+	}
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: negative limit %d", ErrInvalidQuery, q.Limit)
+	}
+	return nil
+}
+
+// Matches indica si el libro cumple los filtros de la consulta (sin considerar orden ni límite)
+func (q BooksQuery) Matches(book Book) bool {
+	switch {
+	case q.Author != "" && book.Author != q.Author:
+		return false
+	case !q.MinPrice.IsZero() && !priceAtLeast(book.Price, q.MinPrice):
+		return false
+	case !q.MaxPrice.IsZero() && !priceAtLeast(q.MaxPrice, book.Price):
+		return false
+	case book.UnitsSold < q.MinUnitsSold || (q.MaxUnitsSold > 0 && book.UnitsSold > q.MaxUnitsSold):
+		return false
+	case len(q.IDs) > 0 && !slices.Contains(q.IDs, book.ID):
+		return false
+	}
+	return true
+}
+
+// Apply filtra, ordena y limita los libros en memoria; no modifica el slice recibido
+// Los empates se desempatan por ID para que el resultado sea determinista
+func (q BooksQuery) Apply(books []Book) []Book {
+	result := []Book{}
+	for _, book := range books {
+		if q.Matches(book) {
+			result = append(result, book)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Book) int {
+		c := q.compare(a, b)
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if q.Descending {
+			return -c
+		}
+		return c
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+func (q BooksQuery) compare(a, b Book) int {
+	switch q.SortBy {
+	case SortByName:
+		return cmp.Compare(a.Name, b.Name)
+	case SortByAuthor:
+		return cmp.Compare(a.Author, b.Author)
+	case SortByUnitsSold:
+		return cmp.Compare(a.UnitsSold, b.UnitsSold)
+	case SortByPrice:
+		return CompareMoney(a.Price, b.Price)
+	default:
+		return 0
+	}
+}
+
+// priceAtLeast indica si a es mayor o igual que b; en distintas monedas es falso
+func priceAtLeast(a, b Money) bool {
+	c, err := a.Compare(b)
+	return err == nil && c >= 0
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidSale indica que un evento de venta no puede aplicarse
+var ErrInvalidSale = errors.New("invalid sale event")
+
+// BookSold es el evento que publica el sistema de ventas por cada venta de un libro
+// EventID identifica el evento para descartar las entregas repetidas del broker
+type BookSold struct {
+	EventID   string    `json:"event_id"`
+	BookID    uint      `json:"book_id"`
+	Qty       uint      `json:"qty"`
+	Timestamp time.Time `json:"ts"`
+}
+
+// Validate comprueba que el evento tenga identificador, libro y cantidad
+func (e BookSold) Validate() error {
+	var errs []error
+	if e.EventID == "" {
+		errs = append(errs, errors.New("event_id is required"))
+	}
+	if e.BookID == 0 {
+		errs = append(errs, errors.New("book_id is required"))
+	}
+	if e.Qty == 0 {
+		errs = append(errs, errors.New("qty must be positive"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSale, err)
+	}
+	return nil
+}
@@ -9,5 +9,86 @@ import (
 // BooksRepository define el puerto para acceder a los datos de libros
 type BooksRepository interface {
 	// GetBooks recupera todos los libros disponibles
-	GetBooks(ctx context.Context) []domain.Book
+	// Los errores se devuelven envueltos en los errores tipados de domain
+	// (ErrUpstreamUnavailable, ErrUpstreamTimeout, ErrMalformedPayload, ErrPayloadTooLarge, ErrContextCanceled)
+	GetBooks(ctx context.Context) ([]domain.Book, error)
+}
+
+// QueryableBooksRepository es implementado por los repositorios que pueden filtrar en la fuente
+// (por ejemplo con parámetros de la API o una consulta SQL) en lugar de devolver el catálogo completo
+type QueryableBooksRepository interface {
+	BooksRepository
+	// QueryBooks devuelve los libros que cumplen la consulta, ordenados y limitados según indica
+	// Devuelve los mismos errores tipados que GetBooks
+	QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error)
+}
+
+// QueryBooks ejecuta la consulta en la fuente si el repositorio implementa QueryableBooksRepository;
+// si no, obtiene el catálogo completo y lo filtra en memoria
+func QueryBooks(ctx context.Context, repository BooksRepository, query domain.BooksQuery) ([]domain.Book, error) {
+	if queryable, ok := repository.(QueryableBooksRepository); ok {
+		return queryable.QueryBooks(ctx, query)
+	}
+	books, err := repository.GetBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(books), nil
+}
+
+// WatchableBooksRepository es implementado por los repositorios que informan los cambios del catálogo
+type WatchableBooksRepository interface {
+	BooksRepository
+	// Watch devuelve un canal con los cambios posteriores a la suscripción
+	// El canal se cierra al cancelar ctx o si quien consume no da abasto; en ese caso
+	// debe volver a leer el catálogo con GetBooks y suscribirse de nuevo
+	Watch(ctx context.Context) <-chan domain.BookEvent
+}
+
+// BooksWriter es implementado por los repositorios que permiten modificar el catálogo
+type BooksWriter interface {
+	// CreateBook agrega un libro; con ID 0 se le asigna el siguiente ID libre
+	// Devuelve el libro guardado con versión 1, o ErrBookExists si el ID ya está en uso
+	CreateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// UpdateBook reemplaza el libro con el mismo ID si su versión actual es book.Version
+	// Devuelve el libro guardado con la versión incrementada, ErrBookNotFound o ErrVersionConflict
+	UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// DeleteBook elimina el libro si su versión actual es version (0 elimina sin comprobarla)
+	// Devuelve ErrBookNotFound o ErrVersionConflict
+	DeleteBook(ctx context.Context, id uint, version uint64) error
+}
+
+// CacheInvalidator es implementado por las capas que guardan copias del catálogo
+type CacheInvalidator interface {
+	// Invalidate descarta la copia para que la próxima lectura consulte la fuente
+	Invalidate()
+}
+
+// QuarantineSource es implementado por los repositorios que validan los libros de su fuente
+// y apartan los inválidos
+type QuarantineSource interface {
+	// Quarantined devuelve los libros rechazados en la última obtención
+	Quarantined() []domain.QuarantinedBook
+}
+
+// FaultInjector es implementado por los componentes que inyectan fallas para pruebas de resiliencia
+type FaultInjector interface {
+	// ChaosConfig devuelve la configuración vigente
+	ChaosConfig() domain.ChaosConfig
+	// SetChaosConfig reemplaza la configuración y reinicia el generador aleatorio con su semilla
+	// Devuelve un error si la configuración no es válida
+	SetChaosConfig(config domain.ChaosConfig) error
+}
+
+// UpstreamInspector es implementado por los repositorios que consultan a un proveedor externo
+type UpstreamInspector interface {
+	// UpstreamInfo describe la conexión con el proveedor (transporte, reintentos y límites) sin credenciales
+	UpstreamInfo() map[string]any
+}
+
+// SalesPublisher es implementado por los componentes que publican eventos de venta
+type SalesPublisher interface {
+	// PublishSale publica la venta para que la consuman las proyecciones
+	// Devuelve ErrInvalidSale si el evento no es válido
+	PublishSale(ctx context.Context, event domain.BookSold) error
 }
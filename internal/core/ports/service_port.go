@@ -8,12 +8,29 @@ import (
 
 // MetricsService define el puerto para los servicios de métricas
 type MetricsService interface {
-	// GetBooks recupera todos los libros disponibles
-	GetBooks(ctx context.Context) []domain.Book
+	// GetBooks recupera todos los libros disponibles o el error tipado del repositorio
+	GetBooks(ctx context.Context) ([]domain.Book, error)
+	// QueryBooks recupera los libros que cumplen la consulta, filtrando en la fuente si el repositorio lo permite
+	QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error)
+	// CountBooksWrittenByAuthor cuenta los libros de un autor sin traer el catálogo completo cuando la fuente lo permite;
+	// quien ya tiene el catálogo debe usar GetBooksWrittenByAuthor para no consultar de nuevo al proveedor
+	CountBooksWrittenByAuthor(ctx context.Context, author string) (uint, error)
 	// GetMeanUnitsSold calcula el promedio de unidades vendidas
 	GetMeanUnitsSold(books []domain.Book) uint
-	// GetCheapestBook encuentra el libro más barato
+	// GetCheapestBook encuentra el libro más barato entre los de la moneda principal del catálogo
 	GetCheapestBook(books []domain.Book) domain.Book
+	// GetCheapestBookByCurrency encuentra el libro más barato de cada moneda
+	GetCheapestBookByCurrency(books []domain.Book) map[domain.Currency]domain.Book
 	// GetBooksWrittenByAuthor cuenta los libros escritos por un autor
 	GetBooksWrittenByAuthor(books []domain.Book, author string) uint
 }
+
+// CatalogService define el puerto para modificar el catálogo de libros
+type CatalogService interface {
+	// CreateBook valida y agrega un libro
+	CreateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// UpdateBook valida y reemplaza un libro según su versión
+	UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// DeleteBook elimina un libro según su versión (0 elimina sin comprobarla)
+	DeleteBook(ctx context.Context, id uint, version uint64) error
+}
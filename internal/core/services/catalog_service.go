@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+	"educabot.com/bookshop/internal/core/validation"
+)
+
+// catalogService implementa el puerto CatalogService
+type catalogService struct {
+	writer      ports.BooksWriter
+	validator   *validation.Pipeline
+	invalidates []ports.CacheInvalidator
+}
+
+// NewCatalogService crea el servicio de escritura del catálogo
+// Después de cada escritura exitosa se invalidan las cachés indicadas para que las
+// métricas no se calculen sobre el catálogo anterior
+func NewCatalogService(writer ports.BooksWriter, invalidates ...ports.CacheInvalidator) ports.CatalogService {
+	return &catalogService{
+		writer: writer,
+		validator: validation.NewPipeline(validation.PolicyFail,
+			validation.NormalizeAuthor(),
+			validation.RequiredText(),
+			validation.PriceRange(1, 0),
+		),
+		invalidates: invalidates,
+	}
+}
+
+// CreateBook valida y agrega un libro
+func (s *catalogService) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	book, err := s.validator.Check(book)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	created, err := s.writer.CreateBook(ctx, book)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	s.invalidate()
+	return created, nil
+}
+
+// UpdateBook valida y reemplaza un libro; book.Version debe ser la versión leída por quien escribe
+func (s *catalogService) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	book, err := s.validator.Check(book)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	updated, err := s.writer.UpdateBook(ctx, book)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	s.invalidate()
+	return updated, nil
+}
+
+// DeleteBook elimina un libro
+func (s *catalogService) DeleteBook(ctx context.Context, id uint, version uint64) error {
+	if err := s.writer.DeleteBook(ctx, id, version); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *catalogService) invalidate() {
+	for _, cache := range s.invalidates {
+		cache.Invalidate()
+	}
+}
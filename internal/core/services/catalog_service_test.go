@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockBooksWriter es un mock del repositorio de escritura
+type MockBooksWriter struct {
+	mock.Mock
+}
+
+func (m *MockBooksWriter) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	args := m.Called(ctx, book)
+	return args.Get(0).(domain.Book), args.Error(1)
+}
+
+func (m *MockBooksWriter) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	args := m.Called(ctx, book)
+	return args.Get(0).(domain.Book), args.Error(1)
+}
+
+func (m *MockBooksWriter) DeleteBook(ctx context.Context, id uint, version uint64) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+// countingInvalidator cuenta las invalidaciones recibidas
+type countingInvalidator struct {
+	calls int
+}
+
+func (c *countingInvalidator) Invalidate() {
+	c.calls++
+}
+
+func TestCatalogService_CreateBook(t *testing.T) {
+	writer := new(MockBooksWriter)
+	cache := &countingInvalidator{}
+
+	// El autor se normaliza antes de llegar al repositorio
+	normalized := domain.Book{Name: "New Book", Author: "Jane Doe", Price: domain.Price(10)}
+	writer.On("CreateBook", mock.Anything, normalized).Return(domain.Book{ID: 4, Name: "New Book", Author: "Jane Doe", Price: domain.Price(10), Version: 1}, nil)
+
+	service := NewCatalogService(writer, cache)
+	created, err := service.CreateBook(context.Background(), domain.Book{Name: "New Book", Author: "  Jane Doe", Price: domain.Price(10)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+	assert.Equal(t, 1, cache.calls)
+	writer.AssertExpectations(t)
+}
+
+func TestCatalogService_RejectsInvalidBook(t *testing.T) {
+	writer := new(MockBooksWriter)
+	cache := &countingInvalidator{}
+
+	service := NewCatalogService(writer, cache)
+	_, err := service.CreateBook(context.Background(), domain.Book{Name: "", Author: "Jane Doe", Price: domain.Price(10)})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidBook)
+	writer.AssertNotCalled(t, "CreateBook", mock.Anything, mock.Anything)
+	assert.Equal(t, 0, cache.calls)
+}
+
+func TestCatalogService_FailedWriteKeepsCache(t *testing.T) {
+	writer := new(MockBooksWriter)
+	cache := &countingInvalidator{}
+	book := domain.Book{ID: 1, Name: "Book", Author: "Author", Price: domain.Price(10), Version: 2}
+	writer.On("UpdateBook", mock.Anything, book).Return(domain.Book{}, fmt.Errorf("%w: current version 3", domain.ErrVersionConflict))
+	writer.On("DeleteBook", mock.Anything, uint(1), uint64(2)).Return(nil)
+
+	service := NewCatalogService(writer, cache)
+
+	_, err := service.UpdateBook(context.Background(), book)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	assert.Equal(t, 0, cache.calls)
+
+	assert.NoError(t, service.DeleteBook(context.Background(), 1, 2))
+	assert.Equal(t, 1, cache.calls)
+	writer.AssertExpectations(t)
+}
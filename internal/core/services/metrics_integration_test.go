@@ -4,26 +4,33 @@ package services_test
 
 import (
 	"context"
-	"net/http"
 	"testing"
 	"time"
 
-	"educabot.com/bookshop/internal/adapters/repositories/http"
 	"educabot.com/bookshop/internal/core/services"
+	booksrepo "educabot.com/bookshop/internal/repositories/http"
+	"educabot.com/bookshop/internal/repositories/http/fixture"
 	"github.com/stretchr/testify/assert"
 )
 
+// mockapiFixture tiene las respuestas grabadas de la API real; se comparte con el paquete del repositorio
+const mockapiFixture = "../../repositories/http/testdata/fixtures/mockapi_books.json"
+
 // TestMetricsServiceIntegration realiza una prueba de integración
-// con el servicio HTTP real de libros
+// con las respuestas grabadas del servicio HTTP real de libros
 // Para ejecutar esta prueba: go test -tags=integration ./internal/core/services
+// Para volver a grabar contra la API real: BOOKS_FIXTURES_MODE=record go test -tags=integration ./internal/core/services
 func TestMetricsServiceIntegration(t *testing.T) {
+	mode, err := fixture.ModeFromEnv()
+	assert.NoError(t, err)
+	transport, err := fixture.New(fixture.Config{Path: mockapiFixture, Mode: mode})
+	assert.NoError(t, err, "El fixture de la API debería poder leerse")
+
 	// Crear un cliente HTTP con timeout bajo para la prueba
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
+	client := transport.Client(5 * time.Second)
 
-	// Usar la API real para esta prueba de integración
-	booksRepository := http.NewHTTPBooksRepositoryWithConfig(
+	// Usar la API real (grabada) para esta prueba de integración
+	booksRepository := booksrepo.NewHTTPBooksRepositoryWithConfig(
 		client,
 		"https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books",
 	)
@@ -36,7 +43,8 @@ func TestMetricsServiceIntegration(t *testing.T) {
 	defer cancel()
 
 	// Obtener los libros
-	books := service.GetBooks(ctx)
+	books, err := service.GetBooks(ctx)
+	assert.NoError(t, err, "No debería haber error al obtener libros de la API real")
 
 	// Verificar que se obtuvieron libros
 	assert.NotEmpty(t, books, "Se deberían obtener libros de la API real")
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"slices"
 
 	"educabot.com/bookshop/internal/core/domain"
 	"educabot.com/bookshop/internal/core/ports"
@@ -21,10 +20,32 @@ func NewMetricsService(booksRepository ports.BooksRepository) ports.MetricsServi
 }
 
 // GetBooks recupera los libros usando el contexto para la operación de red
-func (s *metricsService) GetBooks(ctx context.Context) []domain.Book {
+func (s *metricsService) GetBooks(ctx context.Context) ([]domain.Book, error) {
 	return s.booksRepository.GetBooks(ctx)
 }
 
+// QueryBooks recupera los libros que cumplen la consulta
+// Si el repositorio implementa QueryableBooksRepository el filtro se ejecuta en la fuente;
+// si no, se obtiene el catálogo completo y se filtra en memoria
+func (s *metricsService) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	return ports.QueryBooks(ctx, s.booksRepository, query)
+}
+
+// CountBooksWrittenByAuthor cuenta los libros de un autor consultando sólo los de ese autor
+func (s *metricsService) CountBooksWrittenByAuthor(ctx context.Context, author string) (uint, error) {
+	if author == "" {
+		return 0, nil
+	}
+	books, err := s.QueryBooks(ctx, domain.BooksQuery{Author: author})
+	if err != nil {
+		return 0, err
+	}
+	return uint(len(books)), nil
+}
+
 // GetMeanUnitsSold calcula el promedio de unidades vendidas (no requiere contexto)
 func (s *metricsService) GetMeanUnitsSold(books []domain.Book) uint {
 	if len(books) == 0 {
@@ -39,14 +60,49 @@ func (s *metricsService) GetMeanUnitsSold(books []domain.Book) uint {
 }
 
 // GetCheapestBook encuentra el libro más barato (no requiere contexto)
+// Los precios en distintas monedas no son comparables, por lo que se elige entre los libros
+// de la moneda principal del catálogo; ante un empate gana el primero
 func (s *metricsService) GetCheapestBook(books []domain.Book) domain.Book {
 	if len(books) == 0 {
 		return domain.Book{}
 	}
+	return s.GetCheapestBookByCurrency(books)[mainCurrency(books)]
+}
 
-	return slices.MinFunc(books, func(a, b domain.Book) int {
-		return int(a.Price - b.Price)
-	})
+// GetCheapestBookByCurrency encuentra el libro más barato de cada moneda (no requiere contexto)
+func (s *metricsService) GetCheapestBookByCurrency(books []domain.Book) map[domain.Currency]domain.Book {
+	cheapest := make(map[domain.Currency]domain.Book)
+	for _, book := range books {
+		current, ok := cheapest[book.Price.Currency]
+		if !ok || book.Price.Amount < current.Price.Amount {
+			cheapest[book.Price.Currency] = book
+		}
+	}
+	return cheapest
+}
+
+// mainCurrency devuelve la moneda de la mayoría de los libros
+// Los empates se resuelven a favor de domain.DefaultCurrency y luego por orden alfabético
+func mainCurrency(books []domain.Book) domain.Currency {
+	counts := make(map[domain.Currency]int)
+	for _, book := range books {
+		counts[book.Price.Currency]++
+	}
+	var main domain.Currency
+	best := 0
+	for currency, count := range counts {
+		switch {
+		case count > best:
+		case count < best:
+			continue
+		case main == domain.DefaultCurrency:
+			continue
+		case currency != domain.DefaultCurrency && currency > main:
+			continue
+		}
+		main, best = currency, count
+	}
+	return main
 }
 
 // GetBooksWrittenByAuthor cuenta los libros escritos por un autor (no requiere contexto)
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"educabot.com/bookshop/internal/core/domain"
@@ -14,9 +15,10 @@ type MockBooksRepository struct {
 	mock.Mock
 }
 
-func (m *MockBooksRepository) GetBooks(ctx context.Context) []domain.Book {
+func (m *MockBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
 	args := m.Called(ctx)
-	return args.Get(0).([]domain.Book)
+	books, _ := args.Get(0).([]domain.Book)
+	return books, args.Error(1)
 }
 
 func TestGetBooks(t *testing.T) {
@@ -25,34 +27,51 @@ func TestGetBooks(t *testing.T) {
 
 	// Configurar los datos de prueba
 	testBooks := []domain.Book{
-		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: 10},
-		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: 20},
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: domain.Price(20)},
 	}
 
 	// Configurar expectativas - el contexto es necesario para la llamada a GetBooks
-	mockRepo.On("GetBooks", mock.Anything).Return(testBooks)
+	mockRepo.On("GetBooks", mock.Anything).Return(testBooks, nil)
 
 	// Crear el servicio con el mock
 	service := NewMetricsService(mockRepo)
 
 	// Ejecutar la función a probar
-	result := service.GetBooks(context.Background())
+	result, err := service.GetBooks(context.Background())
 
 	// Verificar el resultado
+	assert.NoError(t, err)
 	assert.Equal(t, testBooks, result)
 
 	// Verificar que se llamaron los métodos esperados
 	mockRepo.AssertExpectations(t)
 }
 
+func TestGetBooks_PropagatesRepositoryError(t *testing.T) {
+	mockRepo := new(MockBooksRepository)
+
+	// El servicio debe devolver el error tipado sin alterarlo
+	repoErr := fmt.Errorf("%w: unexpected status code 503", domain.ErrUpstreamUnavailable)
+	mockRepo.On("GetBooks", mock.Anything).Return(nil, repoErr)
+
+	service := NewMetricsService(mockRepo)
+
+	result, err := service.GetBooks(context.Background())
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetMeanUnitsSold(t *testing.T) {
 	// Crear el servicio con un mock (no importa para esta prueba)
 	service := NewMetricsService(new(MockBooksRepository))
 
 	// Crear datos de prueba
 	testBooks := []domain.Book{
-		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: 10},
-		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: 20},
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: domain.Price(20)},
 	}
 
 	// Probar directamente la función sin contexto
@@ -68,15 +87,38 @@ func TestGetCheapestBook(t *testing.T) {
 
 	// Crear datos de prueba
 	testBooks := []domain.Book{
-		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: 10},
-		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: 20},
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 2000, Price: domain.Price(20)},
 	}
 
 	// Probar directamente la función sin contexto
 	result := service.GetCheapestBook(testBooks)
 
 	assert.Equal(t, "Book 1", result.Name)
-	assert.Equal(t, uint(10), result.Price)
+	assert.Equal(t, domain.Price(10), result.Price)
+}
+
+// Los precios de distintas monedas no se comparan: el más barato se busca en la moneda más usada
+func TestGetCheapestBook_MultipleCurrencies(t *testing.T) {
+	service := NewMetricsService(new(MockBooksRepository))
+
+	testBooks := []domain.Book{
+		{ID: 1, Name: "Book 1", Price: domain.NewMoney(150000, "ARS")},
+		{ID: 2, Name: "Book 2", Price: domain.Price(20)},
+		{ID: 3, Name: "Book 3", Price: domain.NewMoney(90000, "ARS")},
+		{ID: 4, Name: "Book 4", Price: domain.NewMoney(500, "EUR")},
+	}
+
+	assert.Equal(t, "Book 3", service.GetCheapestBook(testBooks).Name)
+
+	byCurrency := service.GetCheapestBookByCurrency(testBooks)
+	assert.Len(t, byCurrency, 3)
+	assert.Equal(t, "Book 3", byCurrency["ARS"].Name)
+	assert.Equal(t, "Book 2", byCurrency["USD"].Name)
+	assert.Equal(t, "Book 4", byCurrency["EUR"].Name)
+
+	// Ante un empate en cantidad se prefiere DefaultCurrency
+	assert.Equal(t, "Book 2", service.GetCheapestBook(testBooks[1:3]).Name)
 }
 
 func TestGetBooksWrittenByAuthor(t *testing.T) {
@@ -85,9 +127,9 @@ func TestGetBooksWrittenByAuthor(t *testing.T) {
 
 	// Crear datos de prueba
 	testBooks := []domain.Book{
-		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: 10},
-		{ID: 2, Name: "Book 2", Author: "Author 1", UnitsSold: 2000, Price: 20},
-		{ID: 3, Name: "Book 3", Author: "Author 2", UnitsSold: 3000, Price: 30},
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author 1", UnitsSold: 2000, Price: domain.Price(20)},
+		{ID: 3, Name: "Book 3", Author: "Author 2", UnitsSold: 3000, Price: domain.Price(30)},
 	}
 
 	// Probar directamente la función sin contexto
@@ -109,3 +151,72 @@ func TestGetCheapestBook_EmptySlice(t *testing.T) {
 	result := service.GetCheapestBook([]domain.Book{})
 	assert.Equal(t, domain.Book{}, result)
 }
+
+// MockQueryableBooksRepository es un mock de un repositorio que filtra en la fuente
+type MockQueryableBooksRepository struct {
+	MockBooksRepository
+}
+
+func (m *MockQueryableBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	args := m.Called(ctx, query)
+	books, _ := args.Get(0).([]domain.Book)
+	return books, args.Error(1)
+}
+
+func TestQueryBooks_PushesDownToQueryableRepository(t *testing.T) {
+	mockRepo := new(MockQueryableBooksRepository)
+	query := domain.BooksQuery{Author: "Author 1", SortBy: domain.SortByPrice, Limit: 1}
+	mockRepo.On("QueryBooks", mock.Anything, query).Return([]domain.Book{{ID: 1, Author: "Author 1"}}, nil)
+
+	service := NewMetricsService(mockRepo)
+	result, err := service.QueryBooks(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.Book{{ID: 1, Author: "Author 1"}}, result)
+	// El catálogo completo no debe pedirse
+	mockRepo.AssertNotCalled(t, "GetBooks", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestQueryBooks_FallsBackToInMemoryFiltering(t *testing.T) {
+	mockRepo := new(MockBooksRepository)
+	mockRepo.On("GetBooks", mock.Anything).Return([]domain.Book{
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 1000, Price: domain.Price(30)},
+		{ID: 2, Name: "Book 2", Author: "Author 1", UnitsSold: 2000, Price: domain.Price(20)},
+		{ID: 3, Name: "Book 3", Author: "Author 2", UnitsSold: 3000, Price: domain.Price(10)},
+		{ID: 4, Name: "Book 4", Author: "Author 1", UnitsSold: 4000, Price: domain.Price(99)},
+	}, nil)
+
+	service := NewMetricsService(mockRepo)
+	result, err := service.QueryBooks(context.Background(), domain.BooksQuery{
+		Author:   "Author 1",
+		MaxPrice: domain.Price(50),
+		SortBy:   domain.SortByPrice,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 1}, []uint{result[0].ID, result[1].ID})
+	assert.Len(t, result, 2)
+}
+
+func TestQueryBooks_InvalidQuery(t *testing.T) {
+	mockRepo := new(MockBooksRepository)
+	service := NewMetricsService(mockRepo)
+
+	_, err := service.QueryBooks(context.Background(), domain.BooksQuery{MinPrice: domain.Price(20), MaxPrice: domain.Price(10)})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidQuery)
+	mockRepo.AssertNotCalled(t, "GetBooks", mock.Anything)
+}
+
+func TestCountBooksWrittenByAuthor(t *testing.T) {
+	mockRepo := new(MockQueryableBooksRepository)
+	mockRepo.On("QueryBooks", mock.Anything, domain.BooksQuery{Author: "Author 1"}).
+		Return([]domain.Book{{ID: 1}, {ID: 2}}, nil)
+
+	service := NewMetricsService(mockRepo)
+	count, err := service.CountBooksWrittenByAuthor(context.Background(), "Author 1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), count)
+}
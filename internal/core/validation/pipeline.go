@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Policy define qué se hace con los libros que no pasan la validación
+type Policy int
+
+const (
+	// PolicyKeep conserva los libros inválidos en el resultado (sólo se registran en la cuarentena)
+	PolicyKeep Policy = iota
+	// PolicyDrop quita los libros inválidos del resultado
+	PolicyDrop
+	// PolicyFail hace fallar la obtención completa si algún libro es inválido
+	PolicyFail
+)
+
+// ParsePolicy interpreta el nombre de una política ("keep", "drop" o "fail")
+func ParsePolicy(name string) (Policy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "keep":
+		return PolicyKeep, nil
+	case "drop":
+		return PolicyDrop, nil
+	case "fail":
+		return PolicyFail, nil
+	default:
+		return PolicyKeep, fmt.Errorf("unknown validation policy %q", name)
+	}
+}
+
+// State es el estado compartido por las reglas durante la validación de un catálogo
+type State struct {
+	// index es la posición en el catálogo del libro que se está validando
+	index   int
+	seenIDs map[uint]int
+}
+
+// Rule es una regla de validación; Check puede normalizar el libro y devuelve un error si es inválido
+type Rule struct {
+	Name  string
+	Check func(book *domain.Book, state *State) error
+}
+
+// Pipeline aplica las reglas en orden a cada libro del catálogo
+type Pipeline struct {
+	Rules  []Rule
+	Policy Policy
+	now    func() time.Time
+}
+
+// NewPipeline crea un pipeline con las reglas y la política indicadas
+func NewPipeline(policy Policy, rules ...Rule) *Pipeline {
+	return &Pipeline{Rules: rules, Policy: policy, now: time.Now}
+}
+
+// DefaultRules devuelve las reglas recomendadas: campos requeridos, precio positivo,
+// IDs únicos y normalización del autor
+func DefaultRules() []Rule {
+	return []Rule{
+		NormalizeAuthor(),
+		RequiredFields(),
+		PriceRange(1, 0),
+		UniqueIDs(),
+	}
+}
+
+// Check valida un único libro y devuelve su versión normalizada
+// Si incumple alguna regla devuelve un error envuelto en domain.ErrInvalidBook con todos los motivos
+func (p *Pipeline) Check(book domain.Book) (domain.Book, error) {
+	state := &State{seenIDs: map[uint]int{}}
+	var reasons []string
+	for _, rule := range p.Rules {
+		if err := rule.Check(&book, state); err != nil {
+			reasons = append(reasons, fmt.Sprintf("%s: %v", rule.Name, err))
+		}
+	}
+	if len(reasons) > 0 {
+		return book, fmt.Errorf("%w: %s", domain.ErrInvalidBook, strings.Join(reasons, "; "))
+	}
+	return book, nil
+}
+
+// Result es el resultado de validar un catálogo
+type Result struct {
+	// Books son los libros que se deben usar según la política
+	Books []domain.Book
+	// Quarantined son los libros que incumplieron alguna regla
+	Quarantined []domain.QuarantinedBook
+}
+
+// Apply valida el catálogo. Con PolicyFail devuelve un error envuelto en domain.ErrMalformedPayload
+// si hay libros inválidos; la cuarentena se devuelve igualmente para poder exponerla
+func (p *Pipeline) Apply(books []domain.Book) (Result, error) {
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	quarantinedAt := now()
+
+	state := &State{seenIDs: make(map[uint]int, len(books))}
+	result := Result{Books: make([]domain.Book, 0, len(books))}
+	for i, book := range books {
+		state.index = i
+		var reasons []string
+		for _, rule := range p.Rules {
+			if err := rule.Check(&book, state); err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %v", rule.Name, err))
+			}
+		}
+
+		if len(reasons) == 0 || p.Policy == PolicyKeep {
+			result.Books = append(result.Books, book)
+		}
+		if len(reasons) > 0 {
+			result.Quarantined = append(result.Quarantined, domain.QuarantinedBook{
+				Book:          book,
+				Index:         i,
+				Reasons:       reasons,
+				QuarantinedAt: quarantinedAt,
+			})
+		}
+	}
+
+	if p.Policy == PolicyFail && len(result.Quarantined) > 0 {
+		first := result.Quarantined[0]
+		return result, fmt.Errorf("%w: %d invalid books (first at index %d: %s)",
+			domain.ErrMalformedPayload, len(result.Quarantined), first.Index, strings.Join(first.Reasons, "; "))
+	}
+	return result, nil
+}
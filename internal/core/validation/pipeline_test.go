@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+func testCatalog() []domain.Book {
+	return []domain.Book{
+		{ID: 1, Name: "The Go Programming Language", Author: "  Alan   Donovan ", UnitsSold: 5000, Price: domain.Price(40)},
+		{ID: 2, Name: "", Author: "Unknown", UnitsSold: 10, Price: domain.Price(10)},
+		{ID: 3, Name: "Free Book", Author: "Someone", UnitsSold: 10, Price: domain.Price(0)},
+		{ID: 1, Name: "Duplicate", Author: "Someone", UnitsSold: 10, Price: domain.Price(15)},
+	}
+}
+
+func TestPipeline_KeepPolicy(t *testing.T) {
+	pipeline := NewPipeline(PolicyKeep, DefaultRules()...)
+
+	result, err := pipeline.Apply(testCatalog())
+	require.NoError(t, err)
+
+	// Se conservan todos los libros, pero con el autor normalizado
+	require.Len(t, result.Books, 4)
+	assert.Equal(t, "Alan Donovan", result.Books[0].Author)
+	require.Len(t, result.Quarantined, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{result.Quarantined[0].Index, result.Quarantined[1].Index, result.Quarantined[2].Index})
+}
+
+func TestPipeline_DropPolicy(t *testing.T) {
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	pipeline := NewPipeline(PolicyDrop, DefaultRules()...)
+	pipeline.now = func() time.Time { return at }
+
+	result, err := pipeline.Apply(testCatalog())
+	require.NoError(t, err)
+
+	require.Len(t, result.Books, 1)
+	assert.Equal(t, uint(1), result.Books[0].ID)
+
+	require.Len(t, result.Quarantined, 3)
+	assert.Equal(t, []string{"required_fields: missing name"}, result.Quarantined[0].Reasons)
+	assert.Equal(t, []string{"price_range: 0.00 USD is below the minimum 0.01 USD"}, result.Quarantined[1].Reasons)
+	assert.Equal(t, []string{"unique_id: duplicate id 1 (first seen at index 0)"}, result.Quarantined[2].Reasons)
+	assert.Equal(t, at, result.Quarantined[0].QuarantinedAt)
+}
+
+func TestPipeline_FailPolicy(t *testing.T) {
+	pipeline := NewPipeline(PolicyFail, DefaultRules()...)
+
+	result, err := pipeline.Apply(testCatalog())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+	// La cuarentena se devuelve igualmente para poder exponerla
+	assert.Len(t, result.Quarantined, 3)
+
+	_, err = pipeline.Apply(testCatalog()[:1])
+	assert.NoError(t, err)
+}
+
+func TestPipeline_ReportsEveryFailedRule(t *testing.T) {
+	pipeline := NewPipeline(PolicyDrop, RequiredFields(), PriceRange(1000, 10000), UnitsRange(1, 0))
+
+	result, err := pipeline.Apply([]domain.Book{{ID: 0, Name: "", Author: "A", Price: domain.Price(500)}})
+	require.NoError(t, err)
+
+	require.Len(t, result.Quarantined, 1)
+	assert.Equal(t, []string{
+		"required_fields: missing id, name",
+		"price_range: 500.00 USD is above the maximum 100.00 USD",
+		"units_range: 0 is below the minimum 1",
+	}, result.Quarantined[0].Reasons)
+	assert.Empty(t, result.Books)
+}
+
+func TestPipeline_DuplicateReportsIndexOfFirstOccurrence(t *testing.T) {
+	pipeline := NewPipeline(PolicyDrop, UniqueIDs())
+
+	result, err := pipeline.Apply([]domain.Book{{ID: 0}, {ID: 1}, {ID: 1}, {ID: 2}, {ID: 2}})
+	require.NoError(t, err)
+
+	// Los libros sin ID y los duplicados no desplazan el índice informado
+	require.Len(t, result.Quarantined, 2)
+	assert.Equal(t, []string{"unique_id: duplicate id 1 (first seen at index 1)"}, result.Quarantined[0].Reasons)
+	assert.Equal(t, []string{"unique_id: duplicate id 2 (first seen at index 3)"}, result.Quarantined[1].Reasons)
+}
+
+func TestParsePolicy(t *testing.T) {
+	for name, expected := range map[string]Policy{"keep": PolicyKeep, "DROP": PolicyDrop, " fail ": PolicyFail} {
+		policy, err := ParsePolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, expected, policy)
+	}
+
+	_, err := ParsePolicy("ignore")
+	assert.Error(t, err)
+}
+
+func TestPipeline_Check(t *testing.T) {
+	pipeline := NewPipeline(PolicyFail, NormalizeAuthor(), RequiredText(), PriceRange(1, 0))
+
+	book, err := pipeline.Check(domain.Book{Name: "New Book", Author: " Jane  Doe ", Price: domain.Price(10)})
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe", book.Author)
+
+	_, err = pipeline.Check(domain.Book{Author: "Jane Doe"})
+	assert.ErrorIs(t, err, domain.ErrInvalidBook)
+	assert.ErrorContains(t, err, "required_fields: missing name; price_range: missing price")
+}
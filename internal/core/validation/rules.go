@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// RequiredFields exige un ID distinto de cero y un nombre y un autor no vacíos
+func RequiredFields() Rule {
+	return Rule{
+		Name: "required_fields",
+		Check: func(book *domain.Book, _ *State) error {
+			var missing []string
+			if book.ID == 0 {
+				missing = append(missing, "id")
+			}
+			if strings.TrimSpace(book.Name) == "" {
+				missing = append(missing, "name")
+			}
+			if strings.TrimSpace(book.Author) == "" {
+				missing = append(missing, "author")
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// RequiredText exige un nombre y un autor no vacíos; a diferencia de RequiredFields
+// admite ID 0, para libros nuevos cuyo ID asigna el repositorio
+func RequiredText() Rule {
+	return Rule{
+		Name: "required_fields",
+		Check: func(book *domain.Book, _ *State) error {
+			var missing []string
+			if strings.TrimSpace(book.Name) == "" {
+				missing = append(missing, "name")
+			}
+			if strings.TrimSpace(book.Author) == "" {
+				missing = append(missing, "author")
+			}
+			if len(missing) > 0 {
+				return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// PriceRange exige una moneda válida y un precio entre min y max unidades menores de esa moneda
+// (max 0 sin límite superior); PriceRange(1, 0) exige un precio positivo
+func PriceRange(min, max uint) Rule {
+	return Rule{
+		Name: "price_range",
+		Check: func(book *domain.Book, _ *State) error {
+			price := book.Price
+			if price.IsZero() {
+				if min > 0 {
+					return errors.New("missing price")
+				}
+				return nil
+			}
+			if err := price.Validate(); err != nil {
+				return err
+			}
+			if price.Amount < 0 || uint64(price.Amount) < uint64(min) {
+				return fmt.Errorf("%s is below the minimum %s", price, domain.NewMoney(int64(min), price.Currency))
+			}
+			if max > 0 && uint64(price.Amount) > uint64(max) {
+				return fmt.Errorf("%s is above the maximum %s", price, domain.NewMoney(int64(max), price.Currency))
+			}
+			return nil
+		},
+	}
+}
+
+// UnitsRange exige que las unidades vendidas estén entre min y max (max 0 sin límite superior)
+func UnitsRange(min, max uint) Rule {
+	return Rule{
+		Name: "units_range",
+		Check: func(book *domain.Book, _ *State) error {
+			return checkRange(book.UnitsSold, min, max)
+		},
+	}
+}
+
+func checkRange(value, min, max uint) error {
+	if value < min {
+		return fmt.Errorf("%d is below the minimum %d", value, min)
+	}
+	if max > 0 && value > max {
+		return fmt.Errorf("%d is above the maximum %d", value, max)
+	}
+	return nil
+}
+
+// UniqueIDs rechaza los libros cuyo ID ya apareció antes en el catálogo
+func UniqueIDs() Rule {
+	return Rule{
+		Name: "unique_id",
+		Check: func(book *domain.Book, state *State) error {
+			if book.ID == 0 {
+				return nil
+			}
+			if first, seen := state.seenIDs[book.ID]; seen {
+				return fmt.Errorf("duplicate id %d (first seen at index %d)", book.ID, first)
+			}
+			state.seenIDs[book.ID] = state.index
+			return nil
+		},
+	}
+}
+
+// NormalizeAuthor quita espacios sobrantes del autor; nunca rechaza un libro
+func NormalizeAuthor() Rule {
+	return Rule{
+		Name: "normalize_author",
+		Check: func(book *domain.Book, _ *State) error {
+			book.Author = strings.Join(strings.Fields(book.Author), " ")
+			return nil
+		},
+	}
+}
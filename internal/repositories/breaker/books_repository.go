@@ -0,0 +1,323 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// ErrCircuitOpen se devuelve cuando el circuito está abierto y no hay fallback disponible
+// Envuelve domain.ErrUpstreamUnavailable para que el handler lo trate como una caída del proveedor
+var ErrCircuitOpen = fmt.Errorf("%w: circuit breaker is open", domain.ErrUpstreamUnavailable)
+
+// State representa el estado del circuit breaker
+type State int
+
+const (
+	// StateClosed deja pasar todas las llamadas al repositorio envuelto
+	StateClosed State = iota
+	// StateOpen rechaza las llamadas sin contactar al repositorio envuelto
+	StateOpen
+	// StateHalfOpen deja pasar una llamada de prueba para decidir si se cierra el circuito
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// Config define los umbrales y el comportamiento del circuit breaker
+type Config struct {
+	// FailureThreshold es la cantidad de fallos consecutivos que abre el circuito
+	FailureThreshold int
+	// CoolDown es el tiempo que el circuito permanece abierto antes de pasar a half-open
+	CoolDown time.Duration
+	// HalfOpenSuccesses es la cantidad de llamadas de prueba exitosas necesarias para cerrar el circuito
+	HalfOpenSuccesses int
+	// ServeLastGood devuelve el último catálogo obtenido con éxito mientras el circuito está abierto
+	ServeLastGood bool
+	// Fallback es el repositorio que se consulta mientras el circuito está abierto (opcional)
+	// Si ServeLastGood está activo y hay un catálogo guardado, éste tiene prioridad
+	Fallback ports.BooksRepository
+	// OnStateChange se invoca (fuera del lock) cada vez que el circuito cambia de estado
+	OnStateChange func(from, to State)
+	// IsFailure decide si un error cuenta como fallo; por defecto se ignoran las cancelaciones del cliente
+	IsFailure func(err error) bool
+}
+
+// DefaultConfig abre el circuito tras 5 fallas consecutivas, lo mantiene abierto 30s, lo cierra
+// con un único sondeo exitoso y mientras está abierto sirve el último catálogo obtenido
+func DefaultConfig() Config {
+	return Config{
+		FailureThreshold:  5,
+		CoolDown:          30 * time.Second,
+		HalfOpenSuccesses: 1,
+		ServeLastGood:     true,
+	}
+}
+
+// Stats resume el estado actual del circuit breaker
+type Stats struct {
+	State               State
+	ConsecutiveFailures int
+	// OpenedAt es el momento en que el circuito se abrió por última vez
+	OpenedAt time.Time
+	// Rejected es la cantidad de llamadas que no llegaron al repositorio envuelto
+	Rejected uint64
+	// Trips es la cantidad de veces que el circuito se abrió
+	Trips uint64
+}
+
+// CircuitBreakerBooksRepository decora un BooksRepository con un circuit breaker
+type CircuitBreakerBooksRepository struct {
+	inner  ports.BooksRepository
+	config Config
+	now    func() time.Time
+
+	mu                  sync.Mutex
+	state               State
+	consecutiveFailures int
+	halfOpenSuccesses   int
+	probeInFlight       bool
+	openedAt            time.Time
+	rejected            uint64
+	trips               uint64
+	lastGood            []domain.Book
+}
+
+// NewCircuitBreakerBooksRepository crea un circuit breaker alrededor del repositorio indicado
+func NewCircuitBreakerBooksRepository(inner ports.BooksRepository, config Config) *CircuitBreakerBooksRepository {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	if config.HalfOpenSuccesses <= 0 {
+		config.HalfOpenSuccesses = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool {
+			return !errors.Is(err, domain.ErrContextCanceled)
+		}
+	}
+	return &CircuitBreakerBooksRepository{
+		inner:  inner,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// State devuelve el estado actual del circuito
+func (b *CircuitBreakerBooksRepository) State() State {
+	return b.Stats().State
+}
+
+// Stats devuelve una copia del estado interno del circuito
+func (b *CircuitBreakerBooksRepository) Stats() Stats {
+	b.mu.Lock()
+	t := b.advanceLocked()
+	stats := Stats{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		OpenedAt:            b.openedAt,
+		Rejected:            b.rejected,
+		Trips:               b.trips,
+	}
+	b.mu.Unlock()
+
+	b.notify(t)
+	return stats
+}
+
+// GetBooks delega en el repositorio envuelto si el circuito lo permite
+func (b *CircuitBreakerBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	allowed, probe, transition := b.acquire()
+	b.notify(transition)
+	if !allowed {
+		return b.fallback(ctx)
+	}
+
+	// El repositorio envuelto registra en un FetchInfo propio para saber si el catálogo está degradado
+	fetchCtx := domain.WithFetchInfo(ctx)
+	books, err := b.inner.GetBooks(fetchCtx)
+	info, _ := domain.FetchInfoFromContext(fetchCtx)
+	domain.RecordFetchInfo(ctx, func(recorded *domain.FetchInfo) {
+		recorded.Merge(info)
+	})
+	// Un snapshot o un catálogo incompleto no se guardan: luego se servirían como si vinieran de la fuente
+	if err == nil && !info.FromSnapshot && !info.Partial {
+		b.storeLastGood(books)
+	}
+	b.notify(b.record(probe, err))
+	return books, err
+}
+
+// QueryBooks delega la consulta en el repositorio envuelto si el circuito lo permite
+// Con el circuito abierto filtra en memoria el catálogo de fallback; el resultado de una
+// consulta cuenta para el estado del circuito pero no reemplaza el último catálogo completo
+func (b *CircuitBreakerBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if _, ok := b.inner.(ports.QueryableBooksRepository); !ok {
+		books, err := b.GetBooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(books), nil
+	}
+
+	allowed, probe, transition := b.acquire()
+	b.notify(transition)
+	if !allowed {
+		books, err := b.fallback(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(books), nil
+	}
+
+	books, err := ports.QueryBooks(ctx, b.inner, query)
+	b.notify(b.record(probe, err))
+	return books, err
+}
+
+// storeLastGood guarda una copia del catálogo: el llamador puede modificar el arreglo que recibe
+func (b *CircuitBreakerBooksRepository) storeLastGood(books []domain.Book) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastGood = slices.Clone(books)
+}
+
+// transition describe un cambio de estado pendiente de notificar
+type transition struct {
+	from, to State
+	changed  bool
+}
+
+// acquire decide si la llamada puede llegar al repositorio envuelto y si es una llamada de prueba
+func (b *CircuitBreakerBooksRepository) acquire() (allowed, probe bool, t transition) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t = b.advanceLocked()
+	switch b.state {
+	case StateClosed:
+		return true, false, t
+	case StateHalfOpen:
+		// Sólo se permite una llamada de prueba a la vez
+		if !b.probeInFlight {
+			b.probeInFlight = true
+			return true, true, t
+		}
+	}
+	b.rejected++
+	return false, false, t
+}
+
+// record actualiza el estado según el resultado de una llamada permitida
+// Las llamadas que terminan después de un cambio de estado sólo afectan al estado en el que empezaron
+func (b *CircuitBreakerBooksRepository) record(probe bool, err error) transition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	inHalfOpen := probe && b.state == StateHalfOpen
+	if inHalfOpen {
+		b.probeInFlight = false
+	}
+
+	if err == nil {
+		switch {
+		case inHalfOpen:
+			b.halfOpenSuccesses++
+			if b.halfOpenSuccesses >= b.config.HalfOpenSuccesses {
+				return b.setStateLocked(StateClosed)
+			}
+		case b.state == StateClosed:
+			b.consecutiveFailures = 0
+		}
+		return transition{}
+	}
+
+	if !b.config.IsFailure(err) {
+		return transition{}
+	}
+
+	switch {
+	case inHalfOpen:
+		return b.setStateLocked(StateOpen)
+	case b.state == StateClosed:
+		b.consecutiveFailures++
+		if b.consecutiveFailures >= b.config.FailureThreshold {
+			return b.setStateLocked(StateOpen)
+		}
+	}
+	return transition{}
+}
+
+// advanceLocked pasa de open a half-open cuando terminó el período de enfriamiento
+func (b *CircuitBreakerBooksRepository) advanceLocked() transition {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.CoolDown {
+		return b.setStateLocked(StateHalfOpen)
+	}
+	return transition{}
+}
+
+func (b *CircuitBreakerBooksRepository) setStateLocked(to State) transition {
+	from := b.state
+	if from == to {
+		return transition{}
+	}
+	b.state = to
+	switch to {
+	case StateOpen:
+		b.openedAt = b.now()
+		b.trips++
+	case StateHalfOpen:
+		b.halfOpenSuccesses = 0
+		b.probeInFlight = false
+	case StateClosed:
+		b.consecutiveFailures = 0
+	}
+	return transition{from: from, to: to, changed: true}
+}
+
+func (b *CircuitBreakerBooksRepository) notify(t transition) {
+	if t.changed && b.config.OnStateChange != nil {
+		b.config.OnStateChange(t.from, t.to)
+	}
+}
+
+// fallback responde mientras el circuito está abierto, sin contactar al repositorio envuelto
+func (b *CircuitBreakerBooksRepository) fallback(ctx context.Context) ([]domain.Book, error) {
+	if b.config.ServeLastGood {
+		b.mu.Lock()
+		lastGood := b.lastGood
+		b.mu.Unlock()
+		if lastGood != nil {
+			domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+				info.Fallback = true
+				info.Warnings = append(info.Warnings, "circuit open: serving last good catalog")
+			})
+			return slices.Clone(lastGood), nil
+		}
+	}
+	if b.config.Fallback != nil {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Fallback = true
+			info.Warnings = append(info.Warnings, "circuit open: serving fallback catalog")
+		})
+		return b.config.Fallback.GetBooks(ctx)
+	}
+	return nil, ErrCircuitOpen
+}
@@ -0,0 +1,321 @@
+package breaker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBooksRepository devuelve el resultado configurado y cuenta las llamadas
+type stubBooksRepository struct {
+	mu    sync.Mutex
+	books []domain.Book
+	err   error
+	calls int
+}
+
+func (s *stubBooksRepository) GetBooks(_ context.Context) ([]domain.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.books, s.err
+}
+
+func (s *stubBooksRepository) set(books []domain.Book, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books, s.err = books, err
+}
+
+func (s *stubBooksRepository) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// fakeClock permite avanzar el tiempo del circuit breaker de forma determinista
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+var (
+	testBooks   = []domain.Book{{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 10, Price: domain.Price(5)}}
+	upstreamErr = fmt.Errorf("%w: unexpected status code 503", domain.ErrUpstreamUnavailable)
+)
+
+func newTestBreaker(inner *stubBooksRepository, config Config) (*CircuitBreakerBooksRepository, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewCircuitBreakerBooksRepository(inner, config)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestCircuitBreaker_OpensAfterThresholdAndFailsFast(t *testing.T) {
+	inner := &stubBooksRepository{err: upstreamErr}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 3, CoolDown: time.Minute})
+
+	for i := 0; i < 3; i++ {
+		_, err := b.GetBooks(context.Background())
+		assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	}
+	assert.Equal(t, StateOpen, b.State())
+
+	// Con el circuito abierto no se contacta al repositorio envuelto
+	_, err := b.GetBooks(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.Equal(t, 3, inner.callCount())
+
+	stats := b.Stats()
+	assert.Equal(t, uint64(1), stats.Rejected)
+	assert.Equal(t, uint64(1), stats.Trips)
+}
+
+func TestCircuitBreaker_SuccessResetsConsecutiveFailures(t *testing.T) {
+	inner := &stubBooksRepository{err: upstreamErr}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 2, CoolDown: time.Minute})
+
+	b.GetBooks(context.Background())
+	inner.set(testBooks, nil)
+	b.GetBooks(context.Background())
+	inner.set(nil, upstreamErr)
+	b.GetBooks(context.Background())
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, 1, b.Stats().ConsecutiveFailures)
+}
+
+func TestCircuitBreaker_HalfOpenProbeClosesCircuit(t *testing.T) {
+	inner := &stubBooksRepository{err: upstreamErr}
+	var transitions []string
+	b, clock := newTestBreaker(inner, Config{
+		FailureThreshold: 1,
+		CoolDown:         10 * time.Second,
+		OnStateChange: func(from, to State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	b.GetBooks(context.Background())
+	require.Equal(t, StateOpen, b.State())
+
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	inner.set(testBooks, nil)
+	books, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestCircuitBreaker_HalfOpenProbeFailureReopens(t *testing.T) {
+	inner := &stubBooksRepository{err: upstreamErr}
+	b, clock := newTestBreaker(inner, Config{FailureThreshold: 1, CoolDown: 10 * time.Second})
+
+	b.GetBooks(context.Background())
+	clock.Advance(10 * time.Second)
+
+	_, err := b.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.Equal(t, StateOpen, b.State())
+	assert.Equal(t, uint64(2), b.Stats().Trips)
+}
+
+func TestCircuitBreaker_ServesLastGoodWhileOpen(t *testing.T) {
+	inner := &stubBooksRepository{books: testBooks}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 1, CoolDown: time.Minute, ServeLastGood: true})
+
+	_, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+
+	inner.set(nil, upstreamErr)
+	_, err = b.GetBooks(context.Background())
+	require.Error(t, err)
+
+	// El catálogo guardado se informa igual que el repositorio de fallback
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := b.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Fallback)
+	assert.Equal(t, []string{"circuit open: serving last good catalog"}, info.Warnings)
+}
+
+func TestCircuitBreaker_LastGoodIsNotAliased(t *testing.T) {
+	books := []domain.Book{{ID: 1, Name: "Book 1", Author: "Author", UnitsSold: 10, Price: domain.Price(5)}}
+	inner := &stubBooksRepository{books: books}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 1, CoolDown: time.Minute, ServeLastGood: true})
+
+	result, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+	// Modificar el catálogo recibido no debe alterar el que se sirve con el circuito abierto
+	result[0].Name = "Modified"
+
+	inner.set(nil, upstreamErr)
+	b.GetBooks(context.Background())
+	served, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Book 1", served[0].Name)
+}
+
+// snapshotBooksRepository simula un repositorio que, con la fuente caída, responde con un snapshot
+type snapshotBooksRepository struct {
+	stubBooksRepository
+	fromSnapshot bool
+}
+
+func (s *snapshotBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	books, err := s.stubBooksRepository.GetBooks(ctx)
+	if s.fromSnapshot {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) { info.FromSnapshot = true })
+	}
+	return books, err
+}
+
+func TestCircuitBreaker_DoesNotStoreSnapshotAsLastGood(t *testing.T) {
+	snapshotBooks := []domain.Book{{ID: 2, Name: "Old Book", Author: "Author", UnitsSold: 1, Price: domain.Price(1)}}
+	inner := &snapshotBooksRepository{stubBooksRepository: stubBooksRepository{books: testBooks}}
+	b := NewCircuitBreakerBooksRepository(inner, Config{FailureThreshold: 1, CoolDown: time.Minute, ServeLastGood: true})
+
+	_, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+
+	// El snapshot llega al llamador con su marca pero no reemplaza al último catálogo de la fuente
+	inner.set(snapshotBooks, nil)
+	inner.fromSnapshot = true
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := b.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, snapshotBooks, books)
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.FromSnapshot)
+
+	inner.set(nil, upstreamErr)
+	inner.fromSnapshot = false
+	b.GetBooks(context.Background())
+	books, err = b.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+}
+
+func TestCircuitBreaker_UsesFallbackRepositoryWhileOpen(t *testing.T) {
+	fallbackBooks := []domain.Book{{ID: 99, Name: "Fallback", Author: "Fallback", UnitsSold: 1, Price: domain.Price(1)}}
+	inner := &stubBooksRepository{err: upstreamErr}
+	fallback := &stubBooksRepository{books: fallbackBooks}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 1, CoolDown: time.Minute, ServeLastGood: true, Fallback: fallback})
+
+	b.GetBooks(context.Background())
+
+	// Sin un catálogo previo exitoso se usa el repositorio de fallback, y se informa
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := b.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, fallbackBooks, books)
+	assert.Equal(t, 1, fallback.callCount())
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Fallback)
+}
+
+func TestCircuitBreaker_IgnoresClientCancellation(t *testing.T) {
+	inner := &stubBooksRepository{err: fmt.Errorf("%w: context canceled", domain.ErrContextCanceled)}
+	b, _ := newTestBreaker(inner, Config{FailureThreshold: 1, CoolDown: time.Minute})
+
+	b.GetBooks(context.Background())
+	b.GetBooks(context.Background())
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, 2, inner.callCount())
+}
+
+func TestCircuitBreaker_SingleProbeInHalfOpen(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	inner := &blockingBooksRepository{release: release, started: started}
+	b := NewCircuitBreakerBooksRepository(inner, Config{FailureThreshold: 1, CoolDown: time.Minute})
+	clock := &fakeClock{now: time.Now()}
+	b.now = clock.Now
+
+	// Forzar el estado half-open
+	b.mu.Lock()
+	b.setStateLocked(StateOpen)
+	b.mu.Unlock()
+	clock.Advance(time.Minute)
+
+	done := make(chan error)
+	go func() {
+		_, err := b.GetBooks(context.Background())
+		done <- err
+	}()
+	<-started
+
+	// Mientras la llamada de prueba está en curso, el resto falla rápido
+	_, err := b.GetBooks(context.Background())
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	close(release)
+	require.NoError(t, <-done)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+// blockingBooksRepository bloquea hasta que se cierra release
+type blockingBooksRepository struct {
+	release <-chan struct{}
+	started chan<- struct{}
+}
+
+func (r *blockingBooksRepository) GetBooks(_ context.Context) ([]domain.Book, error) {
+	r.started <- struct{}{}
+	<-r.release
+	return testBooks, nil
+}
+
+// queryableBooksRepository filtra en la fuente el catálogo del stub
+type queryableBooksRepository struct {
+	stubBooksRepository
+}
+
+func (q *queryableBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	books, err := q.GetBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(books), nil
+}
+
+func TestCircuitBreaker_QueryBooks(t *testing.T) {
+	inner := &queryableBooksRepository{stubBooksRepository{books: testBooks}}
+	b := NewCircuitBreakerBooksRepository(inner, Config{FailureThreshold: 1, CoolDown: time.Minute, ServeLastGood: true})
+
+	_, err := b.GetBooks(context.Background())
+	require.NoError(t, err)
+
+	// El resultado de una consulta no reemplaza el último catálogo completo
+	books, err := b.QueryBooks(context.Background(), domain.BooksQuery{Author: "Nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, books)
+
+	// Los fallos de las consultas abren el circuito y, abierto, se filtra el último catálogo válido
+	inner.set(nil, upstreamErr)
+	_, err = b.QueryBooks(context.Background(), domain.BooksQuery{})
+	require.Error(t, err)
+	assert.Equal(t, StateOpen, b.State())
+
+	books, err = b.QueryBooks(context.Background(), domain.BooksQuery{Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, testBooks[:1], books)
+	assert.Equal(t, 3, inner.callCount())
+}
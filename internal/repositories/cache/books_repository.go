@@ -0,0 +1,238 @@
+package cache
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// Config define los tiempos de vida de la caché de libros
+type Config struct {
+	// TTL es el tiempo durante el cual el catálogo se considera fresco
+	TTL time.Duration
+	// StaleWhileRevalidate es el tiempo, posterior al TTL, durante el cual se sirven datos
+	// vencidos mientras se refrescan en segundo plano
+	StaleWhileRevalidate time.Duration
+	// MaxStaleness es la antigüedad máxima con la que se sirven datos vencidos si el proveedor falla
+	MaxStaleness time.Duration
+	// RefreshTimeout limita la duración de los refrescos en segundo plano
+	RefreshTimeout time.Duration
+}
+
+// DefaultConfig considera fresco el catálogo durante 30s, lo sirve vencido otros 30s mientras
+// lo refresca y hasta 10m si el proveedor falla; cada refresco tiene 15s para completarse
+func DefaultConfig() Config {
+	return Config{
+		TTL:                  30 * time.Second,
+		StaleWhileRevalidate: 30 * time.Second,
+		MaxStaleness:         10 * time.Minute,
+		RefreshTimeout:       15 * time.Second,
+	}
+}
+
+// CachedBooksRepository decora un BooksRepository con una caché en memoria
+type CachedBooksRepository struct {
+	inner  ports.BooksRepository
+	config Config
+	now    func() time.Time
+
+	mu         sync.Mutex
+	books      []domain.Book
+	fetchedAt  time.Time
+	hasEntry   bool
+	refreshing bool
+	// generation se incrementa al invalidar para descartar resultados obtenidos antes de la invalidación
+	generation uint64
+}
+
+// NewCachedBooksRepository crea una caché alrededor del repositorio indicado
+func NewCachedBooksRepository(inner ports.BooksRepository, config Config) *CachedBooksRepository {
+	return &CachedBooksRepository{
+		inner:  inner,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// GetBooks devuelve el catálogo en caché si sigue vigente o lo obtiene del repositorio envuelto
+// La antigüedad de los datos servidos se registra en el domain.FetchInfo del contexto
+func (c *CachedBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	c.mu.Lock()
+	if c.hasEntry {
+		age := c.now().Sub(c.fetchedAt)
+		switch {
+		case age < c.config.TTL:
+			books := slices.Clone(c.books)
+			c.mu.Unlock()
+			recordCacheHit(ctx, age, false)
+			return books, nil
+		case age < c.config.TTL+c.config.StaleWhileRevalidate:
+			books := slices.Clone(c.books)
+			if !c.refreshing {
+				c.refreshing = true
+				go c.refresh(c.generation)
+			}
+			c.mu.Unlock()
+			recordCacheHit(ctx, age, true)
+			return books, nil
+		}
+	}
+	generation := c.generation
+	c.mu.Unlock()
+
+	books, info, err := c.fetch(ctx)
+	if err == nil && !degraded(info) {
+		c.store(generation, books)
+		recordFetchInfo(ctx, info)
+		return books, nil
+	}
+
+	// Si el proveedor falla o responde con un catálogo degradado (el alternativo del breaker
+	// o un snapshot) se sirven datos vencidos mientras no superen MaxStaleness
+	if stale, ok := c.staleEntry(ctx); ok {
+		return stale, nil
+	}
+
+	// Un catálogo degradado se devuelve con sus marcas pero no se guarda: si se guardara
+	// se serviría como fresco y MaxStaleness no se aplicaría nunca
+	recordFetchInfo(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// QueryBooks filtra el catálogo en caché mientras esté fresco; si no, delega la consulta
+// en el repositorio envuelto para que se ejecute en la fuente
+// El resultado de una consulta no se guarda porque no es el catálogo completo
+func (c *CachedBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if _, ok := c.inner.(ports.QueryableBooksRepository); !ok {
+		books, err := c.GetBooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(books), nil
+	}
+
+	c.mu.Lock()
+	if c.hasEntry {
+		if age := c.now().Sub(c.fetchedAt); age < c.config.TTL {
+			books := query.Apply(c.books)
+			c.mu.Unlock()
+			recordCacheHit(ctx, age, false)
+			return books, nil
+		}
+	}
+	c.mu.Unlock()
+
+	fetchCtx := domain.WithFetchInfo(ctx)
+	books, err := ports.QueryBooks(fetchCtx, c.inner, query)
+	info, _ := domain.FetchInfoFromContext(fetchCtx)
+	if err == nil && !degraded(info) {
+		recordFetchInfo(ctx, info)
+		return books, nil
+	}
+	if stale, ok := c.staleEntry(ctx); ok {
+		return query.Apply(stale), nil
+	}
+	recordFetchInfo(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	return books, nil
+}
+
+// staleEntry devuelve una copia del catálogo en caché si no supera MaxStaleness
+func (c *CachedBooksRepository) staleEntry(ctx context.Context) ([]domain.Book, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.hasEntry {
+		return nil, false
+	}
+	age := c.now().Sub(c.fetchedAt)
+	if age >= c.config.TTL+c.config.MaxStaleness {
+		return nil, false
+	}
+	recordCacheHit(ctx, age, true)
+	return slices.Clone(c.books), true
+}
+
+// fetch consulta al repositorio envuelto con un FetchInfo propio para saber si el catálogo está degradado
+func (c *CachedBooksRepository) fetch(ctx context.Context) ([]domain.Book, domain.FetchInfo, error) {
+	fetchCtx := domain.WithFetchInfo(ctx)
+	books, err := c.inner.GetBooks(fetchCtx)
+	info, _ := domain.FetchInfoFromContext(fetchCtx)
+	return books, info, err
+}
+
+// degraded indica si el catálogo no proviene de la fuente sino de un reemplazo ante una falla
+func degraded(info domain.FetchInfo) bool {
+	return info.Fallback || info.FromSnapshot
+}
+
+// Invalidate descarta el catálogo en caché; la próxima llamada consultará al repositorio envuelto
+func (c *CachedBooksRepository) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.books = nil
+	c.hasEntry = false
+	c.generation++
+}
+
+// Age devuelve la antigüedad del catálogo en caché, o false si no hay ninguno
+func (c *CachedBooksRepository) Age() (time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.hasEntry {
+		return 0, false
+	}
+	return c.now().Sub(c.fetchedAt), true
+}
+
+// refresh obtiene el catálogo en segundo plano; si falla o está degradado se conservan los datos anteriores
+func (c *CachedBooksRepository) refresh(generation uint64) {
+	ctx := context.Background()
+	if c.config.RefreshTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.RefreshTimeout)
+		defer cancel()
+	}
+
+	books, info, err := c.fetch(ctx)
+
+	c.mu.Lock()
+	c.refreshing = false
+	c.mu.Unlock()
+
+	if err == nil && !degraded(info) {
+		c.store(generation, books)
+	}
+}
+
+// store guarda el catálogo salvo que la caché se haya invalidado mientras se obtenía
+func (c *CachedBooksRepository) store(generation uint64, books []domain.Book) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.books = slices.Clone(books)
+	c.fetchedAt = c.now()
+	c.hasEntry = true
+}
+
+// recordFetchInfo suma al FetchInfo del llamador lo que registró el repositorio envuelto, sin
+// descartar lo que registraron llamadas anteriores con el mismo contexto
+func recordFetchInfo(ctx context.Context, fetched domain.FetchInfo) {
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.Merge(fetched)
+	})
+}
+
+func recordCacheHit(ctx context.Context, age time.Duration, stale bool) {
+	recordFetchInfo(ctx, domain.FetchInfo{Cached: true, CacheAge: age, Stale: stale})
+}
@@ -0,0 +1,317 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBooksRepository devuelve el resultado configurado y cuenta las llamadas
+type stubBooksRepository struct {
+	mu    sync.Mutex
+	books []domain.Book
+	err   error
+	calls int
+}
+
+func (s *stubBooksRepository) GetBooks(_ context.Context) ([]domain.Book, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.books, s.err
+}
+
+func (s *stubBooksRepository) set(books []domain.Book, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books, s.err = books, err
+}
+
+func (s *stubBooksRepository) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// fakeClock permite avanzar el tiempo de la caché de forma determinista
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+var (
+	booksV1     = []domain.Book{{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 10, Price: domain.Price(5)}}
+	booksV2     = []domain.Book{{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 20, Price: domain.Price(6)}}
+	upstreamErr = fmt.Errorf("%w: unexpected status code 503", domain.ErrUpstreamUnavailable)
+	testConfig  = Config{TTL: 10 * time.Second, StaleWhileRevalidate: 5 * time.Second, MaxStaleness: time.Minute}
+)
+
+func newTestCache(inner *stubBooksRepository) (*CachedBooksRepository, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedBooksRepository(inner, testConfig)
+	c.now = clock.Now
+	return c, clock
+}
+
+func TestCachedBooksRepository_ServesFreshEntry(t *testing.T) {
+	inner := &stubBooksRepository{books: booksV1}
+	c, clock := newTestCache(inner)
+
+	books, err := c.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, booksV1, books)
+
+	clock.Advance(3 * time.Second)
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err = c.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, booksV1, books)
+	assert.Equal(t, 1, inner.callCount())
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Cached)
+	assert.False(t, info.Stale)
+	assert.Equal(t, 3*time.Second, info.CacheAge)
+}
+
+func TestCachedBooksRepository_StaleWhileRevalidate(t *testing.T) {
+	inner := &stubBooksRepository{books: booksV1}
+	c, clock := newTestCache(inner)
+
+	c.GetBooks(context.Background())
+	inner.set(booksV2, nil)
+	clock.Advance(12 * time.Second)
+
+	// Se sirven los datos vencidos de inmediato y se refresca en segundo plano
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := c.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, booksV1, books)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Stale)
+
+	assert.Eventually(t, func() bool {
+		age, ok := c.Age()
+		return ok && age == 0
+	}, time.Second, time.Millisecond)
+
+	books, err = c.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	assert.Equal(t, 2, inner.callCount())
+}
+
+func TestCachedBooksRepository_ServesStaleOnError(t *testing.T) {
+	inner := &stubBooksRepository{books: booksV1}
+	c, clock := newTestCache(inner)
+
+	c.GetBooks(context.Background())
+	inner.set(nil, upstreamErr)
+	clock.Advance(30 * time.Second)
+
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := c.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, booksV1, books)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Stale)
+	assert.Equal(t, 30*time.Second, info.CacheAge)
+}
+
+func TestCachedBooksRepository_ErrorBeyondMaxStaleness(t *testing.T) {
+	inner := &stubBooksRepository{books: booksV1}
+	c, clock := newTestCache(inner)
+
+	c.GetBooks(context.Background())
+	inner.set(nil, upstreamErr)
+	clock.Advance(2 * time.Minute)
+
+	_, err := c.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+}
+
+func TestCachedBooksRepository_Invalidate(t *testing.T) {
+	inner := &stubBooksRepository{books: booksV1}
+	c, _ := newTestCache(inner)
+
+	c.GetBooks(context.Background())
+	inner.set(booksV2, nil)
+	c.Invalidate()
+
+	_, ok := c.Age()
+	assert.False(t, ok)
+
+	books, err := c.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	assert.Equal(t, 2, inner.callCount())
+}
+
+func TestCachedBooksRepository_ErrorWithoutEntry(t *testing.T) {
+	inner := &stubBooksRepository{err: upstreamErr}
+	c, _ := newTestCache(inner)
+
+	_, err := c.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+}
+
+// degradedBooksRepository simula un breaker abierto: responde sin error con un catálogo alternativo
+type degradedBooksRepository struct {
+	stubBooksRepository
+	degraded bool
+}
+
+func (d *degradedBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	books, err := d.stubBooksRepository.GetBooks(ctx)
+	if d.degraded {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) { info.Fallback = true })
+	}
+	return books, err
+}
+
+func TestCachedBooksRepository_DoesNotCacheDegradedResults(t *testing.T) {
+	inner := &degradedBooksRepository{stubBooksRepository: stubBooksRepository{books: booksV2}, degraded: true}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedBooksRepository(inner, testConfig)
+	c.now = clock.Now
+
+	for i := 0; i < 2; i++ {
+		ctx := domain.WithFetchInfo(context.Background())
+		books, err := c.GetBooks(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, booksV2, books)
+
+		// Las marcas del catálogo alternativo llegan al llamador en cada consulta
+		info, _ := domain.FetchInfoFromContext(ctx)
+		assert.True(t, info.Fallback)
+		assert.False(t, info.Cached)
+	}
+	assert.Equal(t, 2, inner.callCount())
+	_, ok := c.Age()
+	assert.False(t, ok)
+}
+
+func TestCachedBooksRepository_PrefersStaleEntryOverDegradedResult(t *testing.T) {
+	inner := &degradedBooksRepository{stubBooksRepository: stubBooksRepository{books: booksV1}}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedBooksRepository(inner, testConfig)
+	c.now = clock.Now
+
+	c.GetBooks(context.Background())
+	inner.set(booksV2, nil)
+	inner.degraded = true
+
+	// Dentro de MaxStaleness se sirve el catálogo real vencido en lugar del alternativo
+	clock.Advance(30 * time.Second)
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := c.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, booksV1, books)
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Stale)
+	assert.Equal(t, 30*time.Second, info.CacheAge)
+
+	// Superado MaxStaleness se sirve el alternativo con su marca y la antigüedad no se reinicia
+	clock.Advance(2 * time.Minute)
+	ctx = domain.WithFetchInfo(context.Background())
+	books, err = c.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	info, _ = domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Fallback)
+	age, _ := c.Age()
+	assert.Equal(t, 150*time.Second, age)
+}
+
+// queryableBooksRepository filtra en la fuente y registra las consultas recibidas
+type queryableBooksRepository struct {
+	stubBooksRepository
+	queries []domain.BooksQuery
+}
+
+func (q *queryableBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	books, err := q.GetBooks(ctx)
+	q.queries = append(q.queries, query)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(books), nil
+}
+
+func TestCachedBooksRepository_QueryBooks(t *testing.T) {
+	inner := &queryableBooksRepository{stubBooksRepository: stubBooksRepository{books: append(slices.Clone(booksV1), booksV2...)}}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedBooksRepository(inner, testConfig)
+	c.now = clock.Now
+	query := domain.BooksQuery{Author: "Author 2"}
+
+	// Sin catálogo en caché la consulta se ejecuta en la fuente
+	books, err := c.QueryBooks(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	assert.Equal(t, []domain.BooksQuery{query}, inner.queries)
+
+	// Con el catálogo fresco se filtra en memoria sin consultar a la fuente
+	_, err = c.GetBooks(context.Background())
+	require.NoError(t, err)
+	books, err = c.QueryBooks(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	assert.Len(t, inner.queries, 1)
+
+	// Si la fuente falla se filtra el catálogo vencido
+	inner.set(nil, upstreamErr)
+	clock.Advance(30 * time.Second)
+	books, err = c.QueryBooks(context.Background(), query)
+	require.NoError(t, err)
+	assert.Equal(t, booksV2, books)
+	assert.Len(t, inner.queries, 2)
+}
+
+func TestCachedBooksRepository_KeepsFetchInfoAcrossCalls(t *testing.T) {
+	inner := &queryableBooksRepository{stubBooksRepository: stubBooksRepository{books: booksV1}}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := NewCachedBooksRepository(inner, testConfig)
+	c.now = clock.Now
+
+	c.GetBooks(context.Background())
+	clock.Advance(12 * time.Second)
+
+	// Dos llamadas con el mismo contexto, como hace un handler: la segunda no borra lo que
+	// registró la primera
+	ctx := domain.WithFetchInfo(context.Background())
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.Warnings = append(info.Warnings, "could not save books snapshot")
+	})
+	_, err := c.GetBooks(ctx)
+	require.NoError(t, err)
+	_, err = c.QueryBooks(ctx, domain.BooksQuery{Author: "Author 1"})
+	require.NoError(t, err)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Cached)
+	assert.True(t, info.Stale)
+	assert.Equal(t, 12*time.Second, info.CacheAge)
+	assert.Equal(t, []string{"could not save books snapshot"}, info.Warnings)
+}
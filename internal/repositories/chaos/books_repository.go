@@ -0,0 +1,147 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// ChaosBooksRepository decora un BooksRepository inyectando las fallas que decide el Injector
+// Sirve para fuentes que no pasan por HTTP; para el proveedor externo conviene Transport,
+// que ejercita los reintentos, la decodificación y la validación del repositorio HTTP
+type ChaosBooksRepository struct {
+	inner    ports.BooksRepository
+	injector *Injector
+}
+
+// NewChaosBooksRepository crea el decorador alrededor del repositorio indicado
+func NewChaosBooksRepository(inner ports.BooksRepository, injector *Injector) *ChaosBooksRepository {
+	return &ChaosBooksRepository{
+		inner:    inner,
+		injector: injector,
+	}
+}
+
+// GetBooks demora, falla o altera el catálogo del repositorio envuelto según la configuración
+// Los errores inyectados son los errores tipados de domain, como los de un proveedor real
+func (r *ChaosBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	return r.inject(ctx, r.inner.GetBooks)
+}
+
+// QueryBooks aplica las mismas fallas que GetBooks a la consulta, que se ejecuta en la fuente
+// si el repositorio envuelto lo permite
+func (r *ChaosBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	return r.inject(ctx, func(ctx context.Context) ([]domain.Book, error) {
+		return ports.QueryBooks(ctx, r.inner, query)
+	})
+}
+
+// inject demora, falla o altera el resultado de fetch según la falla que decida el Injector
+func (r *ChaosBooksRepository) inject(ctx context.Context, fetch func(ctx context.Context) ([]domain.Book, error)) ([]domain.Book, error) {
+	f, ok := r.injector.decide()
+	if !ok {
+		return fetch(ctx)
+	}
+
+	if err := wait(ctx, f.delay); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	}
+	if f.fail {
+		return nil, injectedError(f.config.Error)
+	}
+
+	books, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Trabajar sobre una copia: el repositorio envuelto puede compartir su slice (por ejemplo una caché)
+	books = slices.Clone(books)
+
+	var warnings []string
+	if f.truncate && len(books) > 0 {
+		keep := r.injector.intn(len(books))
+		warnings = append(warnings, fmt.Sprintf("chaos: catalog truncated to %d of %d books", keep, len(books)))
+		books = books[:keep]
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Partial = true
+		})
+	}
+	books, duplicated := r.duplicate(books, f.config.DuplicateRate)
+	if duplicated > 0 {
+		warnings = append(warnings, fmt.Sprintf("chaos: %d books duplicated", duplicated))
+	}
+	if corrupted := r.corrupt(books, f.config.CorruptRate); corrupted > 0 {
+		warnings = append(warnings, fmt.Sprintf("chaos: %d books corrupted", corrupted))
+	}
+	if len(warnings) > 0 {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Warnings = append(info.Warnings, warnings...)
+		})
+	}
+	return books, nil
+}
+
+// duplicate repite cada libro con la probabilidad indicada, a continuación del original
+func (r *ChaosBooksRepository) duplicate(books []domain.Book, rate float64) ([]domain.Book, int) {
+	if rate <= 0 {
+		return books, 0
+	}
+	result := make([]domain.Book, 0, len(books))
+	duplicated := 0
+	for _, book := range books {
+		result = append(result, book)
+		if r.injector.chance(rate) {
+			result = append(result, book)
+			duplicated++
+		}
+	}
+	return result, duplicated
+}
+
+// corrupt altera un campo elegido al azar en cada libro con la probabilidad indicada
+// Los valores elegidos son los que un proveedor roto suele devolver: textos vacíos, ceros y cifras absurdas
+func (r *ChaosBooksRepository) corrupt(books []domain.Book, rate float64) int {
+	if rate <= 0 {
+		return 0
+	}
+	corrupted := 0
+	for i := range books {
+		if !r.injector.chance(rate) {
+			continue
+		}
+		corrupted++
+		switch r.injector.intn(5) {
+		case 0:
+			books[i].ID = 0
+		case 1:
+			books[i].Name = ""
+		case 2:
+			books[i].Author = ""
+		case 3:
+			books[i].UnitsSold = math.MaxUint32
+		case 4:
+			books[i].Price = domain.Money{}
+		}
+	}
+	return corrupted
+}
+
+// injectedError devuelve el error tipado correspondiente al tipo de falla configurado
+func injectedError(kind domain.ChaosError) error {
+	switch kind {
+	case domain.ChaosTimeout:
+		return fmt.Errorf("%w: chaos: injected timeout", domain.ErrUpstreamTimeout)
+	case domain.ChaosMalformed:
+		return fmt.Errorf("%w: chaos: injected malformed payload", domain.ErrMalformedPayload)
+	default:
+		return fmt.Errorf("%w: chaos: injected failure", domain.ErrUpstreamUnavailable)
+	}
+}
@@ -0,0 +1,157 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRepository devuelve siempre el mismo catálogo
+type stubRepository struct {
+	books []domain.Book
+	calls int
+}
+
+func (s *stubRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	s.calls++
+	return s.books, nil
+}
+
+func catalog(n int) []domain.Book {
+	books := make([]domain.Book, n)
+	for i := range books {
+		books[i] = domain.Book{ID: uint(i + 1), Name: "Book", Author: "Author", UnitsSold: 10, Price: domain.Price(20)}
+	}
+	return books
+}
+
+func newInjector(t *testing.T, config domain.ChaosConfig) *Injector {
+	t.Helper()
+	injector, err := NewInjector(config)
+	require.NoError(t, err)
+	return injector
+}
+
+func TestChaosBooksRepository_DisabledPassesThrough(t *testing.T) {
+	inner := &stubRepository{books: catalog(3)}
+	repository := NewChaosBooksRepository(inner, newInjector(t, domain.ChaosConfig{ErrorRate: 1}))
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, catalog(3), books)
+}
+
+func TestChaosBooksRepository_InjectsTypedErrors(t *testing.T) {
+	tests := []struct {
+		kind     domain.ChaosError
+		expected error
+	}{
+		{"", domain.ErrUpstreamUnavailable},
+		{domain.ChaosTimeout, domain.ErrUpstreamTimeout},
+		{domain.ChaosMalformed, domain.ErrMalformedPayload},
+	}
+	for _, tt := range tests {
+		inner := &stubRepository{books: catalog(3)}
+		injector := newInjector(t, domain.ChaosConfig{Enabled: true, Seed: 1, ErrorRate: 1, Error: tt.kind})
+
+		_, err := NewChaosBooksRepository(inner, injector).GetBooks(context.Background())
+		assert.ErrorIs(t, err, tt.expected)
+		// La falla se inyecta antes de consultar la fuente
+		assert.Zero(t, inner.calls)
+	}
+}
+
+func TestChaosBooksRepository_LatencyRespectsDeadline(t *testing.T) {
+	injector := newInjector(t, domain.ChaosConfig{
+		Enabled: true,
+		Latency: domain.Latency{Distribution: domain.LatencyFixed, BaseMS: 200},
+	})
+	repository := NewChaosBooksRepository(&stubRepository{books: catalog(1)}, injector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := repository.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrUpstreamTimeout)
+	assert.Less(t, time.Since(started), 150*time.Millisecond)
+}
+
+func TestChaosBooksRepository_SameSeedSameFaults(t *testing.T) {
+	config := domain.ChaosConfig{Enabled: true, Seed: 7, ErrorRate: 0.3, TruncateRate: 0.3, DuplicateRate: 0.2, CorruptRate: 0.2}
+
+	run := func() ([][]domain.Book, []error) {
+		repository := NewChaosBooksRepository(&stubRepository{books: catalog(20)}, newInjector(t, config))
+		var results [][]domain.Book
+		var errs []error
+		for i := 0; i < 10; i++ {
+			books, err := repository.GetBooks(context.Background())
+			results = append(results, books)
+			errs = append(errs, err)
+		}
+		return results, errs
+	}
+
+	firstBooks, firstErrs := run()
+	secondBooks, secondErrs := run()
+	assert.Equal(t, firstBooks, secondBooks)
+	assert.Equal(t, firstErrs, secondErrs)
+}
+
+func TestChaosBooksRepository_AltersCatalog(t *testing.T) {
+	inner := &stubRepository{books: catalog(50)}
+	injector := newInjector(t, domain.ChaosConfig{Enabled: true, Seed: 3, TruncateRate: 1, DuplicateRate: 0.5, CorruptRate: 0.5})
+	ctx := domain.WithFetchInfo(context.Background())
+
+	books, err := NewChaosBooksRepository(inner, injector).GetBooks(ctx)
+	require.NoError(t, err)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Partial)
+	assert.NotEmpty(t, info.Warnings)
+	assert.NotEqual(t, catalog(50), books)
+	// El catálogo del repositorio envuelto no se modifica
+	assert.Equal(t, catalog(50), inner.books)
+}
+
+func TestInjector_RejectsInvalidConfig(t *testing.T) {
+	_, err := NewInjector(domain.ChaosConfig{CorruptRate: -0.1})
+	assert.Error(t, err)
+
+	injector := newInjector(t, domain.ChaosConfig{Enabled: true})
+	assert.Error(t, injector.SetChaosConfig(domain.ChaosConfig{Error: "explode"}))
+	assert.True(t, injector.ChaosConfig().Enabled)
+}
+
+// queryableRepository filtra en la fuente y cuenta las consultas recibidas
+type queryableRepository struct {
+	stubRepository
+	queries int
+}
+
+func (q *queryableRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	q.queries++
+	return query.Apply(q.books), nil
+}
+
+func TestChaosBooksRepository_QueryBooks(t *testing.T) {
+	inner := &queryableRepository{stubRepository: stubRepository{books: catalog(3)}}
+	injector := newInjector(t, domain.ChaosConfig{ErrorRate: 1})
+	repository := NewChaosBooksRepository(inner, injector)
+
+	// Desactivado, la consulta llega a la fuente
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{IDs: []uint{2}})
+	require.NoError(t, err)
+	assert.Equal(t, catalog(3)[1:2], books)
+	assert.Equal(t, 1, inner.queries)
+	assert.Zero(t, inner.calls)
+
+	// Activado, las consultas fallan igual que GetBooks
+	require.NoError(t, injector.SetChaosConfig(domain.ChaosConfig{Enabled: true, ErrorRate: 1}))
+	_, err = repository.QueryBooks(context.Background(), domain.BooksQuery{})
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.Equal(t, 1, inner.queries)
+}
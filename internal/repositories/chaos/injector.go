@@ -0,0 +1,128 @@
+// Package chaos inyecta fallas configurables (demoras, errores, catálogos truncados, libros duplicados
+// o corruptos) para probar cómo responde el servicio cuando el proveedor se comporta mal
+package chaos
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Injector guarda la configuración de fallas y el generador aleatorio que deciden cada inyección
+// Se comparte entre ChaosBooksRepository y Transport y puede reconfigurarse en caliente
+// Con la misma semilla y la misma secuencia de llamadas las decisiones se repiten
+type Injector struct {
+	mu     sync.Mutex
+	config domain.ChaosConfig
+	rng    *rand.Rand
+}
+
+// NewInjector crea el inyector con la configuración indicada
+func NewInjector(config domain.ChaosConfig) (*Injector, error) {
+	i := &Injector{}
+	if err := i.SetChaosConfig(config); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// ChaosConfig devuelve la configuración vigente
+func (i *Injector) ChaosConfig() domain.ChaosConfig {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.config
+}
+
+// SetChaosConfig reemplaza la configuración y reinicia el generador aleatorio con su semilla
+func (i *Injector) SetChaosConfig(config domain.ChaosConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.config = config
+	i.rng = rand.New(rand.NewSource(seed))
+	return nil
+}
+
+// fault es lo que se decidió inyectar en una llamada
+type fault struct {
+	config   domain.ChaosConfig
+	delay    time.Duration
+	fail     bool
+	truncate bool
+}
+
+// decide sortea la demora, el error y el truncado de una llamada
+// ok es falso si la inyección está desactivada
+func (i *Injector) decide() (fault, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.config.Enabled {
+		return fault{}, false
+	}
+	f := fault{config: i.config, delay: i.delay(i.config.Latency)}
+	f.fail = i.roll(i.config.ErrorRate)
+	f.truncate = !f.fail && i.roll(i.config.TruncateRate)
+	return f, true
+}
+
+// delay sortea la demora según la distribución; se llama con el lock tomado
+func (i *Injector) delay(latency domain.Latency) time.Duration {
+	base, jitter := float64(latency.Base()), float64(latency.Jitter())
+	var d float64
+	switch latency.Distribution {
+	case domain.LatencyFixed:
+		d = base
+	case domain.LatencyUniform:
+		d = base + jitter*i.rng.Float64()
+	case domain.LatencyNormal:
+		d = base + jitter*i.rng.NormFloat64()
+	case domain.LatencyExponential:
+		d = base + jitter*i.rng.ExpFloat64()
+	}
+	return time.Duration(math.Max(d, 0))
+}
+
+// roll devuelve verdadero con la probabilidad indicada; se llama con el lock tomado
+func (i *Injector) roll(rate float64) bool {
+	return rate > 0 && i.rng.Float64() < rate
+}
+
+// chance es roll para quien no tiene el lock
+func (i *Injector) chance(rate float64) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.roll(rate)
+}
+
+// intn devuelve un entero en [0, n)
+func (i *Injector) intn(n int) int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Intn(n)
+}
+
+// wait espera la demora sorteada o hasta que se cancele el contexto
+func wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
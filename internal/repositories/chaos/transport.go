@@ -0,0 +1,153 @@
+package chaos
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Transport es un http.RoundTripper que inyecta las fallas que decide el Injector en las respuestas
+// del proveedor; los errores se simulan con respuestas 503 o 504 o con un cuerpo ilegible, y el
+// truncado corta el cuerpo, para que el repositorio HTTP los trate como fallas reales
+type Transport struct {
+	next     http.RoundTripper
+	injector *Injector
+}
+
+// NewTransport crea el transporte alrededor de next (http.DefaultTransport si es nil)
+func NewTransport(next http.RoundTripper, injector *Injector) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{next: next, injector: injector}
+}
+
+// Middleware adapta NewTransport a la firma de los middlewares de transporte
+func Middleware(injector *Injector) func(next http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return NewTransport(next, injector)
+	}
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f, ok := t.injector.decide()
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	if err := wait(req.Context(), f.delay); err != nil {
+		return nil, err
+	}
+	if f.fail {
+		return injectedResponse(req, f.config.Error), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	if !f.truncate && f.config.DuplicateRate <= 0 && f.config.CorruptRate <= 0 {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	body = t.mangle(body, f)
+	if f.truncate && len(body) > 0 {
+		body = body[:t.injector.intn(len(body))]
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header = resp.Header.Clone()
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	// Sin validadores, para que las peticiones condicionales no fijen un cuerpo alterado
+	resp.Header.Del("ETag")
+	resp.Header.Del("Last-Modified")
+	return resp, nil
+}
+
+// mangle duplica y corrompe los elementos de un arreglo JSON; otros cuerpos se devuelven sin cambios
+func (t *Transport) mangle(body []byte, f fault) []byte {
+	if f.config.DuplicateRate <= 0 && f.config.CorruptRate <= 0 {
+		return body
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return body
+	}
+
+	mangled := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		if t.injector.chance(f.config.CorruptRate) {
+			item = t.corrupt(item)
+		}
+		mangled = append(mangled, item)
+		if t.injector.chance(f.config.DuplicateRate) {
+			mangled = append(mangled, item)
+		}
+	}
+	data, err := json.Marshal(mangled)
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+// corrupt cambia el tipo de un campo elegido al azar: los números pasan a ser texto y el resto null
+func (t *Transport) corrupt(item json.RawMessage) json.RawMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(item, &fields); err != nil || len(fields) == 0 {
+		return item
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	// Ordenar para que la elección dependa sólo de la semilla
+	slices.Sort(keys)
+	key := keys[t.injector.intn(len(keys))]
+
+	value := bytes.TrimSpace(fields[key])
+	if len(value) > 0 && (value[0] == '-' || (value[0] >= '0' && value[0] <= '9')) {
+		fields[key] = json.RawMessage(`"chaos"`)
+	} else {
+		fields[key] = json.RawMessage(`null`)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return item
+	}
+	return data
+}
+
+// injectedResponse simula la respuesta de un proveedor que falla de la forma configurada
+func injectedResponse(req *http.Request, kind domain.ChaosError) *http.Response {
+	status, contentType, body := http.StatusServiceUnavailable, "text/plain", "chaos: injected failure"
+	switch kind {
+	case domain.ChaosTimeout:
+		status, body = http.StatusGatewayTimeout, "chaos: injected timeout"
+	case domain.ChaosMalformed:
+		status, contentType, body = http.StatusOK, "application/json", "<html>chaos: injected malformed payload"
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,90 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	booksrepo "educabot.com/bookshop/internal/repositories/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const catalogJSON = `[{"id":1,"name":"A","author":"X","units_sold":10,"price":20},{"id":2,"name":"B","author":"Y","units_sold":30,"price":40}]`
+
+func catalogServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(catalogJSON))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func fetch(t *testing.T, transport http.RoundTripper, url string) (*http.Response, string) {
+	t.Helper()
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, string(body)
+}
+
+func TestTransport_InjectsStatusErrors(t *testing.T) {
+	server := catalogServer(t)
+
+	resp, _ := fetch(t, NewTransport(nil, newInjector(t, domain.ChaosConfig{Enabled: true, ErrorRate: 1})), server.URL)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	resp, _ = fetch(t, NewTransport(nil, newInjector(t, domain.ChaosConfig{Enabled: true, ErrorRate: 1, Error: domain.ChaosTimeout})), server.URL)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+}
+
+func TestTransport_TruncatesBody(t *testing.T) {
+	server := catalogServer(t)
+	injector := newInjector(t, domain.ChaosConfig{Enabled: true, Seed: 5, TruncateRate: 1})
+
+	resp, body := fetch(t, NewTransport(nil, injector), server.URL)
+	assert.Less(t, len(body), len(catalogJSON))
+	assert.Equal(t, catalogJSON[:len(body)], body)
+	// No se conserva el ETag para que una petición condicional no fije el cuerpo truncado
+	assert.Empty(t, resp.Header.Get("ETag"))
+}
+
+func TestTransport_DuplicatesAndCorruptsRecords(t *testing.T) {
+	server := catalogServer(t)
+
+	_, body := fetch(t, NewTransport(nil, newInjector(t, domain.ChaosConfig{Enabled: true, Seed: 1, DuplicateRate: 1})), server.URL)
+	assert.JSONEq(t, `[
+		{"id":1,"name":"A","author":"X","units_sold":10,"price":20},{"id":1,"name":"A","author":"X","units_sold":10,"price":20},
+		{"id":2,"name":"B","author":"Y","units_sold":30,"price":40},{"id":2,"name":"B","author":"Y","units_sold":30,"price":40}
+	]`, body)
+
+	_, body = fetch(t, NewTransport(nil, newInjector(t, domain.ChaosConfig{Enabled: true, Seed: 1, CorruptRate: 1})), server.URL)
+	assert.NotEqual(t, catalogJSON, body)
+	assert.Regexp(t, `"chaos"|null`, body)
+}
+
+func TestTransport_ExercisesHTTPRepository(t *testing.T) {
+	server := catalogServer(t)
+	injector := newInjector(t, domain.ChaosConfig{Enabled: true, ErrorRate: 1, Error: domain.ChaosMalformed})
+	repository := booksrepo.NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		booksrepo.WithRetryPolicy(booksrepo.RetryPolicy{MaxAttempts: 1}),
+		booksrepo.WithTransportMiddleware(Middleware(injector)),
+	)
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+
+	// Al desactivar la inyección el proveedor vuelve a responder normalmente
+	require.NoError(t, injector.SetChaosConfig(domain.ChaosConfig{}))
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, books, 2)
+}
@@ -0,0 +1,184 @@
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// Config define el comportamiento de la llamada compartida
+type Config struct {
+	// FetchTimeout limita la duración de la llamada compartida, que no hereda el deadline de ningún llamador
+	// Un valor cero no impone límite
+	FetchTimeout time.Duration
+}
+
+// DefaultConfig limita a 30s la llamada compartida; como no hereda el deadline de ningún llamador,
+// sin ese límite un proveedor colgado la dejaría abierta indefinidamente
+func DefaultConfig() Config {
+	return Config{FetchTimeout: 30 * time.Second}
+}
+
+// Stats resume las llamadas atendidas por el repositorio
+type Stats struct {
+	// Fetches es la cantidad de llamadas realizadas al repositorio envuelto
+	Fetches uint64
+	// Coalesced es la cantidad de llamadas que se unieron a una llamada ya en curso
+	Coalesced uint64
+}
+
+// CoalescingBooksRepository decora un BooksRepository para que las llamadas concurrentes
+// compartan una única llamada al repositorio envuelto
+type CoalescingBooksRepository struct {
+	inner  ports.BooksRepository
+	config Config
+
+	mu       sync.Mutex
+	inflight *call
+
+	fetches   atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// call es una llamada en curso al repositorio envuelto
+type call struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	// Se escriben antes de cerrar done y sólo se leen después
+	books []domain.Book
+	info  domain.FetchInfo
+	err   error
+}
+
+// NewCoalescingBooksRepository crea el decorador alrededor del repositorio indicado
+func NewCoalescingBooksRepository(inner ports.BooksRepository, config Config) *CoalescingBooksRepository {
+	return &CoalescingBooksRepository{
+		inner:  inner,
+		config: config,
+	}
+}
+
+// Stats devuelve los contadores acumulados del repositorio
+func (r *CoalescingBooksRepository) Stats() Stats {
+	return Stats{
+		Fetches:   r.fetches.Load(),
+		Coalesced: r.coalesced.Load(),
+	}
+}
+
+// GetBooks se une a la llamada en curso o inicia una nueva
+// Cada llamador respeta su propio contexto: si se cancela, deja de esperar sin abortar la
+// llamada compartida, que sólo se cancela cuando ya no queda nadie esperándola
+func (r *CoalescingBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(err)
+	}
+
+	r.mu.Lock()
+	c := r.inflight
+	if c == nil {
+		c = r.startLocked(ctx)
+	} else {
+		r.coalesced.Add(1)
+	}
+	c.waiters++
+	r.mu.Unlock()
+
+	select {
+	case <-c.done:
+		r.leave(c)
+		if c.err != nil {
+			return nil, c.err
+		}
+		// Se suma a lo que el llamador ya registró con el mismo contexto en lugar de reemplazarlo
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Merge(c.info)
+		})
+		// Cada llamador recibe su propia copia para que no compartan el arreglo subyacente
+		return slices.Clone(c.books), nil
+	case <-ctx.Done():
+		r.leave(c)
+		return nil, contextError(ctx.Err())
+	}
+}
+
+// QueryBooks delega la consulta en la fuente si el repositorio envuelto lo permite, sin compartirla:
+// las consultas varían entre llamadores; si no, filtra en memoria el catálogo de la llamada compartida
+func (r *CoalescingBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if queryable, ok := r.inner.(ports.QueryableBooksRepository); ok {
+		return queryable.QueryBooks(ctx, query)
+	}
+	books, err := r.GetBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(books), nil
+}
+
+// startLocked inicia la llamada compartida con un contexto desacoplado del llamador
+func (r *CoalescingBooksRepository) startLocked(ctx context.Context) *call {
+	// Se conservan los valores del contexto pero no su cancelación ni su deadline,
+	// con un FetchInfo propio que luego se copia a cada llamador
+	shared := domain.WithFetchInfo(context.WithoutCancel(ctx))
+	var cancel context.CancelFunc
+	if r.config.FetchTimeout > 0 {
+		shared, cancel = context.WithTimeout(shared, r.config.FetchTimeout)
+	} else {
+		shared, cancel = context.WithCancel(shared)
+	}
+
+	c := &call{done: make(chan struct{}), cancel: cancel}
+	r.inflight = c
+	r.fetches.Add(1)
+
+	go func() {
+		defer cancel()
+		books, err := r.inner.GetBooks(shared)
+		c.books, c.err = books, err
+		c.info, _ = domain.FetchInfoFromContext(shared)
+
+		r.mu.Lock()
+		if r.inflight == c {
+			r.inflight = nil
+		}
+		r.mu.Unlock()
+		close(c.done)
+	}()
+	return c
+}
+
+// leave registra que un llamador dejó de esperar; si era el último y la llamada sigue
+// en curso, se cancela y se libera para que los próximos llamadores inicien una nueva
+func (r *CoalescingBooksRepository) leave(c *call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+	select {
+	case <-c.done:
+	default:
+		if r.inflight == c {
+			r.inflight = nil
+		}
+		c.cancel()
+	}
+}
+
+// contextError traduce el error del contexto de un llamador al error tipado correspondiente
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+	}
+	return fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+}
@@ -0,0 +1,289 @@
+package coalesce
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testBooks   = []domain.Book{{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 10, Price: domain.Price(5)}}
+	upstreamErr = fmt.Errorf("%w: unexpected status code 503", domain.ErrUpstreamUnavailable)
+)
+
+// gatedBooksRepository bloquea cada llamada hasta que se abre la compuerta
+type gatedBooksRepository struct {
+	gate     chan struct{}
+	started  chan struct{}
+	calls    atomic.Int32
+	canceled atomic.Int32
+	books    []domain.Book
+	err      error
+}
+
+func newGatedBooksRepository(books []domain.Book, err error) *gatedBooksRepository {
+	return &gatedBooksRepository{
+		gate:    make(chan struct{}),
+		started: make(chan struct{}, 100),
+		books:   books,
+		err:     err,
+	}
+}
+
+func (g *gatedBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	g.calls.Add(1)
+	g.started <- struct{}{}
+	select {
+	case <-g.gate:
+		return g.books, g.err
+	case <-ctx.Done():
+		g.canceled.Add(1)
+		return nil, fmt.Errorf("%w: %w", domain.ErrContextCanceled, ctx.Err())
+	}
+}
+
+// waitForWaiters espera hasta que la llamada en curso tenga n llamadores esperando
+func waitForWaiters(t *testing.T, r *CoalescingBooksRepository, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.inflight != nil && r.inflight.waiters == n
+	}, time.Second, time.Millisecond)
+}
+
+func TestCoalescingBooksRepository_SharesSingleUpstreamCall(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	const callers = 50
+	var wg sync.WaitGroup
+	results := make([][]domain.Book, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = r.GetBooks(context.Background())
+		}(i)
+	}
+
+	waitForWaiters(t, r, callers)
+	close(inner.gate)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inner.calls.Load())
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, testBooks, results[i])
+	}
+
+	stats := r.Stats()
+	assert.Equal(t, uint64(1), stats.Fetches)
+	assert.Equal(t, uint64(callers-1), stats.Coalesced)
+}
+
+func TestCoalescingBooksRepository_CallersGetIndependentCopies(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	var wg sync.WaitGroup
+	results := make([][]domain.Book, 2)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = r.GetBooks(context.Background())
+		}(i)
+	}
+	waitForWaiters(t, r, 2)
+	close(inner.gate)
+	wg.Wait()
+
+	results[0][0].Name = "mutated"
+	assert.Equal(t, "Book 1", results[1][0].Name)
+}
+
+func TestCoalescingBooksRepository_SharesErrors(t *testing.T) {
+	inner := newGatedBooksRepository(nil, upstreamErr)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = r.GetBooks(context.Background())
+		}(i)
+	}
+	waitForWaiters(t, r, len(errs))
+	close(inner.gate)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), inner.calls.Load())
+	for _, err := range errs {
+		assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	}
+}
+
+func TestCoalescingBooksRepository_CanceledWaiterDoesNotAbortOthers(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	// El primer llamador inicia la llamada compartida y luego se cancela
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := r.GetBooks(leaderCtx)
+		leaderErr <- err
+	}()
+	<-inner.started
+
+	followerResult := make(chan []domain.Book, 1)
+	go func() {
+		books, err := r.GetBooks(context.Background())
+		assert.NoError(t, err)
+		followerResult <- books
+	}()
+	waitForWaiters(t, r, 2)
+
+	cancelLeader()
+	assert.ErrorIs(t, <-leaderErr, domain.ErrContextCanceled)
+
+	close(inner.gate)
+	assert.Equal(t, testBooks, <-followerResult)
+	assert.Equal(t, int32(1), inner.calls.Load())
+	assert.Equal(t, int32(0), inner.canceled.Load())
+}
+
+func TestCoalescingBooksRepository_WaiterDeadlineMapsToTimeout(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+	defer close(inner.gate)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := r.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrUpstreamTimeout)
+}
+
+func TestCoalescingBooksRepository_AllWaitersGoneCancelsSharedCall(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+	defer close(inner.gate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.GetBooks(ctx)
+		done <- err
+	}()
+	<-inner.started
+
+	cancel()
+	assert.ErrorIs(t, <-done, domain.ErrContextCanceled)
+	assert.Eventually(t, func() bool { return inner.canceled.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Un llamador nuevo no se une a la llamada abandonada sino que inicia otra
+	go r.GetBooks(context.Background())
+	<-inner.started
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
+
+func TestCoalescingBooksRepository_SequentialCallsFetchAgain(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	close(inner.gate)
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	for i := 0; i < 3; i++ {
+		_, err := r.GetBooks(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), inner.calls.Load())
+}
+
+func TestCoalescingBooksRepository_FetchTimeout(t *testing.T) {
+	inner := newGatedBooksRepository(testBooks, nil)
+	r := NewCoalescingBooksRepository(inner, Config{FetchTimeout: 10 * time.Millisecond})
+	defer close(inner.gate)
+
+	_, err := r.GetBooks(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), inner.canceled.Load())
+}
+
+func TestCoalescingBooksRepository_PropagatesFetchInfo(t *testing.T) {
+	inner := recordingBooksRepository{}
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	ctx := domain.WithFetchInfo(context.Background())
+	_, err := r.GetBooks(ctx)
+	require.NoError(t, err)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Cached)
+}
+
+func TestCoalescingBooksRepository_KeepsCallerFetchInfo(t *testing.T) {
+	inner := recordingBooksRepository{}
+	r := NewCoalescingBooksRepository(inner, DefaultConfig())
+
+	// Lo registrado antes con el mismo contexto se conserva junto con lo de la llamada compartida
+	ctx := domain.WithFetchInfo(context.Background())
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.Partial = true
+		info.Warnings = append(info.Warnings, "page 3 failed")
+	})
+	_, err := r.GetBooks(ctx)
+	require.NoError(t, err)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Cached)
+	assert.True(t, info.Partial)
+	assert.Equal(t, []string{"page 3 failed"}, info.Warnings)
+}
+
+// recordingBooksRepository registra un FetchInfo en el contexto recibido
+type recordingBooksRepository struct{}
+
+func (recordingBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.Cached = true
+	})
+	return testBooks, nil
+}
+
+// queryableBooksRepository filtra en la fuente y cuenta las consultas recibidas
+type queryableBooksRepository struct {
+	recordingBooksRepository
+	queries atomic.Int32
+}
+
+func (q *queryableBooksRepository) QueryBooks(_ context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	q.queries.Add(1)
+	return query.Apply(testBooks), nil
+}
+
+func TestCoalescingBooksRepository_QueryBooks(t *testing.T) {
+	queryable := &queryableBooksRepository{}
+	books, err := NewCoalescingBooksRepository(queryable, DefaultConfig()).QueryBooks(context.Background(), domain.BooksQuery{Author: "Author 1"})
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+	assert.Equal(t, int32(1), queryable.queries.Load())
+
+	// Sin soporte de consultas se filtra el catálogo de la llamada compartida
+	r := NewCoalescingBooksRepository(recordingBooksRepository{}, DefaultConfig())
+	books, err = r.QueryBooks(context.Background(), domain.BooksQuery{Author: "Nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, books)
+	assert.Equal(t, uint64(1), r.Stats().Fetches)
+}
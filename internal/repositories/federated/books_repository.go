@@ -0,0 +1,188 @@
+package federated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// Field identifica un campo combinable de domain.Book (por su tag JSON)
+type Field string
+
+const (
+	FieldName      Field = "name"
+	FieldAuthor    Field = "author"
+	FieldUnitsSold Field = "units_sold"
+	FieldPrice     Field = "price"
+)
+
+// fields son los campos que se resuelven al combinar libros (el ID es la clave de combinación)
+var fields = []Field{FieldName, FieldAuthor, FieldUnitsSold, FieldPrice}
+
+// Strategy define cómo se resuelve un conflicto entre fuentes para un campo
+type Strategy int
+
+const (
+	// PreferOrder toma el valor de la primera fuente (según Sources o FieldPolicy.Sources) que tiene el libro;
+	// en los campos de texto se saltean los valores vacíos
+	PreferOrder Strategy = iota
+	// Max toma el mayor valor (sólo campos numéricos; en texto equivale a PreferOrder)
+	Max
+	// Min toma el menor valor (sólo campos numéricos; en texto equivale a PreferOrder)
+	Min
+	// Latest toma el valor de la fuente con los datos más recientes, según la antigüedad
+	// de caché que informa en domain.FetchInfo (una fuente sin caché se considera actual)
+	Latest
+)
+
+// FieldPolicy es la política de resolución de un campo
+type FieldPolicy struct {
+	Strategy Strategy
+	// Sources es el orden de preferencia para PreferOrder y para desempatar; vacío usa el orden de Config.Sources
+	Sources []string
+}
+
+// Source es una fuente de libros con nombre
+type Source struct {
+	Name       string
+	Repository ports.BooksRepository
+}
+
+// Config define las fuentes y la resolución de conflictos
+type Config struct {
+	Sources []Source
+	// Fields define la política por campo; los campos ausentes usan PreferOrder
+	Fields map[Field]FieldPolicy
+	// Deadline limita cuánto se espera a cada fuente; las que no responden a tiempo se omiten (0 sin límite)
+	Deadline time.Duration
+}
+
+// MergedBook es un libro combinado junto con la fuente de la que proviene cada campo
+type MergedBook struct {
+	domain.Book
+	Sources domain.FieldSources
+}
+
+// FederatedBooksRepository combina por ID los libros de varias fuentes consultadas en paralelo
+type FederatedBooksRepository struct {
+	config Config
+}
+
+// NewFederatedBooksRepository crea el repositorio combinado
+func NewFederatedBooksRepository(config Config) *FederatedBooksRepository {
+	return &FederatedBooksRepository{config: config}
+}
+
+// GetBooks implementa la interfaz BooksRepository
+// La procedencia de cada campo se registra en domain.FetchInfo.Provenance
+func (r *FederatedBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	merged, err := r.GetMergedBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	books := make([]domain.Book, len(merged))
+	provenance := make(map[uint]domain.FieldSources, len(merged))
+	for i, book := range merged {
+		books[i] = book.Book
+		provenance[book.ID] = book.Sources
+	}
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.Provenance = provenance
+	})
+	return books, nil
+}
+
+// sourceResult es la respuesta de una fuente
+type sourceResult struct {
+	index int
+	books []domain.Book
+	age   time.Duration
+	err   error
+}
+
+// GetMergedBooks consulta todas las fuentes y combina sus libros por ID
+// Sólo falla si ninguna fuente respondió; las fuentes fallidas se informan como advertencias
+func (r *FederatedBooksRepository) GetMergedBooks(ctx context.Context) ([]MergedBook, error) {
+	sources := r.config.Sources
+	if len(sources) == 0 {
+		return []MergedBook{}, nil
+	}
+
+	fetchCtx := ctx
+	if r.config.Deadline > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, r.config.Deadline)
+		defer cancel()
+	}
+
+	// El canal tiene capacidad para todas las fuentes: una fuente lenta nunca bloquea
+	results := make(chan sourceResult, len(sources))
+	for i, source := range sources {
+		go func(i int, repository ports.BooksRepository) {
+			sourceCtx := domain.WithFetchInfo(fetchCtx)
+			books, err := repository.GetBooks(sourceCtx)
+			info, _ := domain.FetchInfoFromContext(sourceCtx)
+			var age time.Duration
+			if info.Cached {
+				age = info.CacheAge
+			}
+			results <- sourceResult{index: i, books: books, age: age, err: err}
+		}(i, source.Repository)
+	}
+
+	responses := make([]*sourceResult, len(sources))
+	finished := make([]bool, len(sources))
+	var warnings []string
+	var errs []error
+	for pending := len(sources); pending > 0; pending-- {
+		select {
+		case result := <-results:
+			finished[result.index] = true
+			if result.err != nil {
+				errs = append(errs, fmt.Errorf("source %s: %w", sources[result.index].Name, result.err))
+				warnings = append(warnings, fmt.Sprintf("source %s failed: %v", sources[result.index].Name, result.err))
+				continue
+			}
+			responses[result.index] = &result
+		case <-fetchCtx.Done():
+			// No se espera a las fuentes que no respondieron a tiempo
+			for i := range sources {
+				if !finished[i] {
+					errs = append(errs, fmt.Errorf("source %s: %w: %w", sources[i].Name, domain.ErrUpstreamTimeout, fetchCtx.Err()))
+					warnings = append(warnings, fmt.Sprintf("source %s did not answer before the deadline", sources[i].Name))
+				}
+			}
+			pending = 0
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+		}
+		return nil, fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	}
+
+	answered := 0
+	for _, response := range responses {
+		if response != nil {
+			answered++
+		}
+	}
+	if answered == 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(warnings) > 0 {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Partial = true
+			info.Warnings = append(info.Warnings, warnings...)
+		})
+	}
+
+	return r.merge(responses), nil
+}
@@ -0,0 +1,154 @@
+package federated
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBooksRepository devuelve un resultado fijo, opcionalmente con demora y antigüedad de caché
+type stubBooksRepository struct {
+	books    []domain.Book
+	err      error
+	delay    time.Duration
+	cacheAge time.Duration
+}
+
+func (s stubBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, ctx.Err())
+		}
+	}
+	if s.cacheAge > 0 {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Cached = true
+			info.CacheAge = s.cacheAge
+		})
+	}
+	return s.books, s.err
+}
+
+func TestFederatedBooksRepository_MergesByIDWithPreferOrder(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "api", Repository: stubBooksRepository{books: []domain.Book{
+				{ID: 1, Name: "Clean Code", Author: "", UnitsSold: 100, Price: domain.Price(50)},
+			}}},
+			{Name: "file", Repository: stubBooksRepository{books: []domain.Book{
+				{ID: 1, Name: "Clean Code (2nd)", Author: "Robert C. Martin", UnitsSold: 300, Price: domain.Price(45)},
+				{ID: 2, Name: "Refactoring", Author: "Martin Fowler", UnitsSold: 10, Price: domain.Price(60)},
+			}}},
+		},
+	})
+
+	merged, err := repository.GetMergedBooks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, merged, 2)
+
+	// El autor vacío de "api" se completa con el de "file"
+	assert.Equal(t, domain.Book{ID: 1, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 100, Price: domain.Price(50)}, merged[0].Book)
+	assert.Equal(t, domain.FieldSources{"name": "api", "author": "file", "units_sold": "api", "price": "api"}, merged[0].Sources)
+	assert.Equal(t, "file", merged[1].Sources["name"])
+}
+
+func TestFederatedBooksRepository_FieldPolicies(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "api", Repository: stubBooksRepository{
+				books:    []domain.Book{{ID: 1, Name: "API name", Author: "A", UnitsSold: 100, Price: domain.Price(50)}},
+				cacheAge: time.Minute,
+			}},
+			{Name: "db", Repository: stubBooksRepository{
+				books: []domain.Book{{ID: 1, Name: "DB name", Author: "A", UnitsSold: 300, Price: domain.Price(45)}},
+			}},
+		},
+		Fields: map[Field]FieldPolicy{
+			FieldName:      {Strategy: PreferOrder, Sources: []string{"db"}},
+			FieldUnitsSold: {Strategy: Max},
+			FieldPrice:     {Strategy: Latest},
+		},
+	})
+
+	merged, err := repository.GetMergedBooks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, merged, 1)
+
+	assert.Equal(t, "DB name", merged[0].Name)
+	assert.Equal(t, uint(300), merged[0].UnitsSold)
+	// "db" no está cacheado, así que su precio es el más reciente
+	assert.Equal(t, domain.Price(45), merged[0].Price)
+	assert.Equal(t, domain.FieldSources{"name": "db", "author": "api", "units_sold": "db", "price": "db"}, merged[0].Sources)
+}
+
+func TestFederatedBooksRepository_MinStrategy(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "a", Repository: stubBooksRepository{books: []domain.Book{{ID: 1, Name: "Book", Price: domain.Price(50)}}}},
+			{Name: "b", Repository: stubBooksRepository{books: []domain.Book{{ID: 1, Name: "Book", Price: domain.Price(40)}}}},
+		},
+		Fields: map[Field]FieldPolicy{FieldPrice: {Strategy: Min}},
+	})
+
+	merged, err := repository.GetMergedBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.Price(40), merged[0].Price)
+	assert.Equal(t, "b", merged[0].Sources["price"])
+}
+
+func TestFederatedBooksRepository_FailingSourceDoesNotBlockOthers(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "broken", Repository: stubBooksRepository{err: fmt.Errorf("%w: 503", domain.ErrUpstreamUnavailable)}},
+			{Name: "slow", Repository: stubBooksRepository{books: []domain.Book{{ID: 9, Name: "Slow"}}, delay: time.Second}},
+			{Name: "ok", Repository: stubBooksRepository{books: []domain.Book{{ID: 1, Name: "Book"}}}},
+		},
+		Deadline: 50 * time.Millisecond,
+	})
+
+	ctx := domain.WithFetchInfo(context.Background())
+	start := time.Now()
+	books, err := repository.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, []domain.Book{{ID: 1, Name: "Book"}}, books)
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	assert.True(t, info.Partial)
+	assert.Len(t, info.Warnings, 2)
+	assert.Equal(t, "ok", info.Provenance[1]["name"])
+}
+
+func TestFederatedBooksRepository_AllSourcesFail(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "a", Repository: stubBooksRepository{err: fmt.Errorf("%w: 503", domain.ErrUpstreamUnavailable)}},
+			{Name: "b", Repository: stubBooksRepository{err: fmt.Errorf("%w: bad json", domain.ErrMalformedPayload)}},
+		},
+	})
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+}
+
+func TestFederatedBooksRepository_CallerCanceled(t *testing.T) {
+	repository := NewFederatedBooksRepository(Config{
+		Sources: []Source{
+			{Name: "slow", Repository: stubBooksRepository{books: []domain.Book{{ID: 1}}, delay: time.Second}},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repository.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrContextCanceled)
+}
@@ -0,0 +1,130 @@
+package federated
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// candidate es la versión de un libro que aporta una fuente
+type candidate struct {
+	source int
+	book   domain.Book
+	age    time.Duration
+}
+
+// merge combina por ID las respuestas de las fuentes, conservando el orden de primera aparición
+func (r *FederatedBooksRepository) merge(responses []*sourceResult) []MergedBook {
+	var order []uint
+	candidates := make(map[uint][]candidate)
+	for _, response := range responses {
+		if response == nil {
+			continue
+		}
+		for _, book := range response.books {
+			if _, seen := candidates[book.ID]; !seen {
+				order = append(order, book.ID)
+			}
+			candidates[book.ID] = append(candidates[book.ID], candidate{source: response.index, book: book, age: response.age})
+		}
+	}
+
+	merged := make([]MergedBook, 0, len(order))
+	for _, id := range order {
+		book := MergedBook{
+			Book:    domain.Book{ID: id},
+			Sources: make(domain.FieldSources, len(fields)),
+		}
+		for _, field := range fields {
+			winner := r.resolve(field, candidates[id])
+			setField(&book.Book, field, winner.book)
+			book.Sources[string(field)] = r.config.Sources[winner.source].Name
+		}
+		merged = append(merged, book)
+	}
+	return merged
+}
+
+// resolve elige el candidato que aporta el valor del campo según su política
+func (r *FederatedBooksRepository) resolve(field Field, candidates []candidate) candidate {
+	policy := r.config.Fields[field]
+	ordered := r.byPreference(policy, candidates)
+	numeric := field == FieldUnitsSold || field == FieldPrice
+
+	switch {
+	case policy.Strategy == Max && numeric:
+		return slices.MaxFunc(ordered, func(a, b candidate) int {
+			// En caso de empate gana el preferido (MaxFunc devuelve el primer máximo)
+			return compareField(a.book, b.book, field)
+		})
+	case policy.Strategy == Min && numeric:
+		return slices.MinFunc(ordered, func(a, b candidate) int {
+			return compareField(a.book, b.book, field)
+		})
+	case policy.Strategy == Latest:
+		return slices.MinFunc(ordered, func(a, b candidate) int {
+			return cmp.Compare(a.age, b.age)
+		})
+	}
+
+	// PreferOrder: el primero según la preferencia, salteando textos vacíos
+	if !numeric {
+		for _, c := range ordered {
+			if fieldString(c.book, field) != "" {
+				return c
+			}
+		}
+	}
+	return ordered[0]
+}
+
+// byPreference ordena los candidatos según FieldPolicy.Sources y luego según el orden de Config.Sources
+func (r *FederatedBooksRepository) byPreference(policy FieldPolicy, candidates []candidate) []candidate {
+	rank := func(c candidate) int {
+		name := r.config.Sources[c.source].Name
+		if i := slices.Index(policy.Sources, name); i >= 0 {
+			return i
+		}
+		return len(policy.Sources) + c.source
+	}
+	ordered := slices.Clone(candidates)
+	slices.SortStableFunc(ordered, func(a, b candidate) int {
+		return rank(a) - rank(b)
+	})
+	return ordered
+}
+
+func setField(target *domain.Book, field Field, source domain.Book) {
+	switch field {
+	case FieldName:
+		target.Name = source.Name
+	case FieldAuthor:
+		target.Author = source.Author
+	case FieldUnitsSold:
+		target.UnitsSold = source.UnitsSold
+	case FieldPrice:
+		target.Price = source.Price
+	}
+}
+
+// compareField compara un campo numérico de dos versiones del libro
+// Los precios en distintas monedas se consideran empatados, de modo que decide la preferencia
+func compareField(a, b domain.Book, field Field) int {
+	if field == FieldPrice {
+		c, err := a.Price.Compare(b.Price)
+		if err != nil {
+			return 0
+		}
+		return c
+	}
+	return cmp.Compare(a.UnitsSold, b.UnitsSold)
+}
+
+func fieldString(book domain.Book, field Field) string {
+	if field == FieldAuthor {
+		return book.Author
+	}
+	return book.Name
+}
@@ -0,0 +1,201 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Config define cómo se lee y se vigila el archivo de catálogo
+type Config struct {
+	// Format es el formato del archivo; FormatAuto lo deduce de la extensión
+	Format Format
+	// CSV es el mapeo de columnas usado con FormatCSV
+	CSV CSVMapping
+	// PollInterval es cada cuánto se revisa si el archivo cambió (0 desactiva la recarga automática)
+	PollInterval time.Duration
+	// Validate rechaza un catálogo inválido; por defecto se usa ValidateCatalog
+	Validate func(books []domain.Book) error
+	// OnReload se invoca tras cada recarga automática con su resultado (nil si fue exitosa)
+	OnReload func(err error)
+}
+
+// DefaultConfig devuelve una configuración con recarga automática cada segundo
+func DefaultConfig() Config {
+	return Config{
+		CSV:          DefaultCSVMapping(),
+		PollInterval: time.Second,
+		Validate:     ValidateCatalog,
+	}
+}
+
+// catalog es una versión cargada del archivo; se reemplaza completa de forma atómica
+type catalog struct {
+	books    []domain.Book
+	loadedAt time.Time
+}
+
+// fileStamp identifica una versión del archivo en disco
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// FileBooksRepository implementa el repositorio de libros sobre un archivo local
+// (JSON, NDJSON o CSV) que se recarga automáticamente cuando cambia
+type FileBooksRepository struct {
+	path   string
+	format Format
+	config Config
+
+	current atomic.Pointer[catalog]
+
+	// reloadMu serializa las recargas y protege lastStamp y lastErr
+	reloadMu  sync.Mutex
+	lastStamp fileStamp
+	lastErr   error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewFileBooksRepository carga el archivo y, si PollInterval es positivo, empieza a vigilarlo
+// Falla si la primera carga no es válida
+func NewFileBooksRepository(path string, config Config) (*FileBooksRepository, error) {
+	format := config.Format
+	if format == FormatAuto {
+		var err error
+		if format, err = detectFormat(path); err != nil {
+			return nil, err
+		}
+	}
+	if config.CSV == (CSVMapping{}) {
+		config.CSV = DefaultCSVMapping()
+	}
+	if config.Validate == nil {
+		config.Validate = ValidateCatalog
+	}
+
+	r := &FileBooksRepository{
+		path:   path,
+		format: format,
+		config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	if config.PollInterval > 0 {
+		go r.watch()
+	} else {
+		close(r.done)
+	}
+	return r, nil
+}
+
+// GetBooks implementa la interfaz BooksRepository devolviendo la última versión válida del archivo
+func (r *FileBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	}
+	return slices.Clone(r.current.Load().books), nil
+}
+
+// LoadedAt devuelve el momento en que se cargó la versión actual del catálogo
+func (r *FileBooksRepository) LoadedAt() time.Time {
+	return r.current.Load().loadedAt
+}
+
+// LastError devuelve el error de la última recarga, o nil si fue exitosa
+func (r *FileBooksRepository) LastError() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	return r.lastErr
+}
+
+// Reload lee el archivo y, si es válido, reemplaza el catálogo actual
+// Si falla se conserva la versión anterior y se devuelve el error
+func (r *FileBooksRepository) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	stamp, err := r.stat()
+	if err == nil {
+		err = r.load()
+	}
+	// Se recuerda la versión vista aunque sea inválida para no reintentarla en cada revisión
+	r.lastStamp = stamp
+	r.lastErr = err
+	return err
+}
+
+// Close detiene la vigilancia del archivo
+func (r *FileBooksRepository) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+	return nil
+}
+
+func (r *FileBooksRepository) load() error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("opening catalog: %w", err)
+	}
+	defer f.Close()
+
+	books, err := decode(f, r.format, r.config.CSV)
+	if err != nil {
+		return fmt.Errorf("%w: %s catalog %s: %w", domain.ErrMalformedPayload, r.format, r.path, err)
+	}
+	if err := r.config.Validate(books); err != nil {
+		return fmt.Errorf("%w: catalog %s failed validation: %w", domain.ErrMalformedPayload, r.path, err)
+	}
+
+	r.current.Store(&catalog{books: books, loadedAt: time.Now()})
+	return nil
+}
+
+func (r *FileBooksRepository) stat() (fileStamp, error) {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("reading catalog: %w", err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// watch revisa periódicamente la fecha de modificación y el tamaño del archivo
+func (r *FileBooksRepository) watch() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+
+		stamp, err := r.stat()
+		r.reloadMu.Lock()
+		changed := err == nil && stamp != r.lastStamp
+		r.reloadMu.Unlock()
+		if !changed {
+			continue
+		}
+
+		err = r.Reload()
+		if r.config.OnReload != nil {
+			r.config.OnReload(err)
+		}
+	}
+}
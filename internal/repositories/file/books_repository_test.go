@@ -0,0 +1,172 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var expectedBooks = []domain.Book{
+	{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: domain.Price(40)},
+	{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func staticConfig() Config {
+	config := DefaultConfig()
+	config.PollInterval = 0
+	return config
+}
+
+func TestFileBooksRepository_Formats(t *testing.T) {
+	tests := map[string]string{
+		"books.json": `[
+			{"id": 1, "name": "The Go Programming Language", "author": "Alan Donovan", "units_sold": 5000, "price": 40},
+			{"id": 2, "name": "Clean Code", "author": "Robert C. Martin", "units_sold": 15000, "price": 50}
+		]`,
+		"books.ndjson": `{"id": 1, "name": "The Go Programming Language", "author": "Alan Donovan", "units_sold": 5000, "price": 40}
+
+{"id": 2, "name": "Clean Code", "author": "Robert C. Martin", "units_sold": 15000, "price": 50}
+`,
+		"books.csv": `id,name,author,units_sold,price
+1,The Go Programming Language,Alan Donovan,5000,40
+2,Clean Code,"Robert C. Martin",15000,50
+`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			writeFile(t, path, content)
+
+			repository, err := NewFileBooksRepository(path, staticConfig())
+			require.NoError(t, err)
+			defer repository.Close()
+
+			books, err := repository.GetBooks(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, expectedBooks, books)
+		})
+	}
+}
+
+func TestFileBooksRepository_CSVColumnMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	writeFile(t, path, `price;title;sold;writer;sku
+40;The Go Programming Language;5000;Alan Donovan;1
+50;Clean Code;15000;Robert C. Martin;2
+`)
+
+	config := staticConfig()
+	config.Format = FormatCSV
+	config.CSV = CSVMapping{ID: "sku", Name: "title", Author: "writer", UnitsSold: "sold", Price: "price", Comma: ';'}
+
+	repository, err := NewFileBooksRepository(path, config)
+	require.NoError(t, err)
+	defer repository.Close()
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expectedBooks, books)
+}
+
+func TestFileBooksRepository_InvalidInitialFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		"broken.json":    `[{"id": 1,`,
+		"duplicate.json": `[{"id": 1, "name": "A"}, {"id": 1, "name": "B"}]`,
+		"missing.csv":    "id,name\n1,Book\n",
+		"badnumber.csv":  "id,name,author,units_sold,price\n1,Book,Author,many,10\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			writeFile(t, path, content)
+
+			_, err := NewFileBooksRepository(path, staticConfig())
+			assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+		})
+	}
+
+	_, err := NewFileBooksRepository(filepath.Join(dir, "books.xml"), staticConfig())
+	assert.Error(t, err)
+}
+
+func TestFileBooksRepository_HotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.json")
+	writeFile(t, path, `[{"id": 1, "name": "Book 1"}]`)
+
+	reloads := make(chan error, 10)
+	config := DefaultConfig()
+	config.PollInterval = 5 * time.Millisecond
+	config.OnReload = func(err error) { reloads <- err }
+
+	repository, err := NewFileBooksRepository(path, config)
+	require.NoError(t, err)
+	defer repository.Close()
+
+	// Una versión válida reemplaza el catálogo
+	writeFile(t, path, `[{"id": 1, "name": "Book 1"}, {"id": 2, "name": "Book 2"}]`)
+	require.NoError(t, <-reloads)
+	books, _ := repository.GetBooks(context.Background())
+	assert.Len(t, books, 2)
+
+	// Una versión inválida se rechaza y se conserva la anterior
+	writeFile(t, path, `[{"id": 0, "name": ""}]`)
+	assert.ErrorIs(t, <-reloads, domain.ErrMalformedPayload)
+	books, _ = repository.GetBooks(context.Background())
+	assert.Len(t, books, 2)
+	assert.Error(t, repository.LastError())
+}
+
+func TestFileBooksRepository_ReloadKeepsPreviousWhenFileDisappears(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.json")
+	writeFile(t, path, `[{"id": 1, "name": "Book 1"}]`)
+
+	repository, err := NewFileBooksRepository(path, staticConfig())
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(path))
+	err = repository.Reload()
+	assert.True(t, errors.Is(err, os.ErrNotExist), "expected a not-exist error, got %v", err)
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, books, 1)
+}
+
+func TestFileBooksRepository_ReturnsCopies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.json")
+	writeFile(t, path, `[{"id": 1, "name": "Book 1"}]`)
+
+	repository, err := NewFileBooksRepository(path, staticConfig())
+	require.NoError(t, err)
+
+	books, _ := repository.GetBooks(context.Background())
+	books[0].Name = "mutated"
+
+	books, _ = repository.GetBooks(context.Background())
+	assert.Equal(t, "Book 1", books[0].Name)
+}
+
+func TestFileBooksRepository_DemoCatalog(t *testing.T) {
+	repository, err := NewFileBooksRepository(filepath.Join("..", "..", "..", "data", "books.json"), staticConfig())
+	require.NoError(t, err)
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, books, 3)
+}
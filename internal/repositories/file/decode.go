@@ -0,0 +1,225 @@
+package file
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Format es el formato del archivo de catálogo
+type Format int
+
+const (
+	// FormatAuto deduce el formato a partir de la extensión del archivo
+	FormatAuto Format = iota
+	// FormatJSON es un arreglo JSON de libros
+	FormatJSON
+	// FormatNDJSON es un libro JSON por línea (.ndjson o .jsonl)
+	FormatNDJSON
+	// FormatCSV es un CSV con una fila de encabezados
+	FormatCSV
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatJSON:
+		return "json"
+	case FormatNDJSON:
+		return "ndjson"
+	case FormatCSV:
+		return "csv"
+	default:
+		return "auto"
+	}
+}
+
+// CSVMapping indica qué columna del CSV (por nombre de encabezado) corresponde a cada campo
+type CSVMapping struct {
+	ID        string
+	Name      string
+	Author    string
+	UnitsSold string
+	// Price es un importe en unidades enteras, opcionalmente con su moneda ("12.99" o "12.99 ARS")
+	Price string
+	// Currency es una columna opcional con la moneda del precio; si falta se usa DefaultCurrency
+	Currency string
+	// Comma es el separador de columnas (',' por defecto)
+	Comma rune
+}
+
+// DefaultCSVMapping usa como encabezados los mismos nombres que los tags JSON de domain.Book
+func DefaultCSVMapping() CSVMapping {
+	return CSVMapping{
+		ID:        "id",
+		Name:      "name",
+		Author:    "author",
+		UnitsSold: "units_sold",
+		Price:     "price",
+		Currency:  "currency",
+		Comma:     ',',
+	}
+}
+
+// detectFormat deduce el formato según la extensión del archivo
+func detectFormat(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	case ".csv":
+		return FormatCSV, nil
+	default:
+		return FormatAuto, fmt.Errorf("cannot detect catalog format from %q", path)
+	}
+}
+
+// decode interpreta el contenido del archivo según el formato
+func decode(r io.Reader, format Format, mapping CSVMapping) ([]domain.Book, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(r)
+	case FormatNDJSON:
+		return decodeNDJSON(r)
+	case FormatCSV:
+		return decodeCSV(r, mapping)
+	default:
+		return nil, fmt.Errorf("unsupported catalog format %s", format)
+	}
+}
+
+func decodeJSON(r io.Reader) ([]domain.Book, error) {
+	var books []domain.Book
+	decoder := json.NewDecoder(r)
+	if err := decoder.Decode(&books); err != nil {
+		return nil, err
+	}
+	if books == nil {
+		books = []domain.Book{}
+	}
+	return books, nil
+}
+
+func decodeNDJSON(r io.Reader) ([]domain.Book, error) {
+	books := []domain.Book{}
+	decoder := json.NewDecoder(r)
+	for {
+		var book domain.Book
+		err := decoder.Decode(&book)
+		if errors.Is(err, io.EOF) {
+			return books, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", len(books)+1, err)
+		}
+		books = append(books, book)
+	}
+}
+
+func decodeCSV(r io.Reader, mapping CSVMapping) ([]domain.Book, error) {
+	reader := csv.NewReader(r)
+	if mapping.Comma != 0 {
+		reader.Comma = mapping.Comma
+	}
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	column := func(name string) (int, error) {
+		index, ok := columns[name]
+		if !ok {
+			return 0, fmt.Errorf("CSV column %q not found", name)
+		}
+		return index, nil
+	}
+	var idCol, nameCol, authorCol, unitsCol, priceCol int
+	for _, c := range []struct {
+		target *int
+		name   string
+	}{
+		{&idCol, mapping.ID},
+		{&nameCol, mapping.Name},
+		{&authorCol, mapping.Author},
+		{&unitsCol, mapping.UnitsSold},
+		{&priceCol, mapping.Price},
+	} {
+		if *c.target, err = column(c.name); err != nil {
+			return nil, err
+		}
+	}
+	currencyCol, hasCurrency := columns[mapping.Currency]
+	hasCurrency = hasCurrency && mapping.Currency != ""
+
+	books := []domain.Book{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return books, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := parseUint(record[idCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: column %q: %w", line, mapping.ID, err)
+		}
+		unitsSold, err := parseUint(record[unitsCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: column %q: %w", line, mapping.UnitsSold, err)
+		}
+		priceText := record[priceCol]
+		if hasCurrency && strings.TrimSpace(record[currencyCol]) != "" {
+			priceText += " " + record[currencyCol]
+		}
+		price, err := domain.ParseMoney(priceText)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: column %q: %w", line, mapping.Price, err)
+		}
+
+		books = append(books, domain.Book{
+			ID:        id,
+			Name:      strings.TrimSpace(record[nameCol]),
+			Author:    strings.TrimSpace(record[authorCol]),
+			UnitsSold: unitsSold,
+			Price:     price,
+		})
+	}
+}
+
+func parseUint(value string) (uint, error) {
+	parsed, err := strconv.ParseUint(strings.TrimSpace(value), 10, 0)
+	return uint(parsed), err
+}
+
+// ValidateCatalog es la validación por defecto: IDs no nulos y únicos y nombres no vacíos
+func ValidateCatalog(books []domain.Book) error {
+	seen := make(map[uint]struct{}, len(books))
+	for i, book := range books {
+		if book.ID == 0 {
+			return fmt.Errorf("book at index %d has no id", i)
+		}
+		if book.Name == "" {
+			return fmt.Errorf("book %d has no name", book.ID)
+		}
+		if _, dup := seen[book.ID]; dup {
+			return fmt.Errorf("duplicate book id %d", book.ID)
+		}
+		seen[book.ID] = struct{}{}
+	}
+	return nil
+}
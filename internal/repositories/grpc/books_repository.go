@@ -0,0 +1,276 @@
+// Package grpc implementa el repositorio de libros sobre el servicio gRPC BooksCatalog
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/grpc/catalogpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy define los reintentos que hace el propio cliente gRPC mediante el service config
+// Las llamadas con streaming sólo se reintentan mientras no se haya recibido ningún libro
+type RetryPolicy struct {
+	// MaxAttempts es el número total de intentos, entre 2 y 5 (gRPC limita el máximo); 1 o menos desactiva los reintentos
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	BackoffMultiplier float64
+	// RetryableStatusCodes son los códigos que se consideran transitorios
+	RetryableStatusCodes []codes.Code
+}
+
+// Config define cómo se consulta el catálogo
+type Config struct {
+	// Timeout limita cada llamada cuando el contexto no trae un deadline propio
+	Timeout time.Duration
+	// Streaming usa ListBooksStream en lugar de ListBooks, para catálogos grandes
+	Streaming bool
+	// MaxBooks limita la cantidad de libros aceptados (0 sin límite); superarlo es ErrPayloadTooLarge
+	MaxBooks int
+	// MaxMessageBytes limita el tamaño de cada respuesta (0 usa el límite de gRPC, 4 MiB)
+	MaxMessageBytes int
+	RetryPolicy     RetryPolicy
+}
+
+// DefaultConfig devuelve una configuración razonable para los catálogos internos
+func DefaultConfig() Config {
+	return Config{
+		Timeout:   10 * time.Second,
+		Streaming: true,
+		MaxBooks:  100_000,
+		RetryPolicy: RetryPolicy{
+			MaxAttempts:          3,
+			InitialBackoff:       100 * time.Millisecond,
+			MaxBackoff:           2 * time.Second,
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+		},
+	}
+}
+
+// GRPCBooksRepository implementa el repositorio de libros consumiendo el servicio BooksCatalog
+type GRPCBooksRepository struct {
+	client catalogpb.BooksCatalogClient
+	config Config
+	// conn es nil si el cliente se recibió ya construido
+	conn *grpc.ClientConn
+}
+
+// Dial crea el repositorio conectado a target (por ejemplo "dns:///catalog.internal:443")
+// opts debe incluir las credenciales de transporte; la política de reintentos se agrega como
+// service config por defecto, por lo que la que publique el servidor por DNS tiene prioridad
+// La conexión se establece de forma perezosa con la primera llamada
+func Dial(target string, config Config, opts ...grpc.DialOption) (*GRPCBooksRepository, error) {
+	serviceConfig, err := config.serviceConfig()
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]grpc.DialOption{grpc.WithDefaultServiceConfig(serviceConfig)}, opts...)
+	if config.MaxMessageBytes > 0 {
+		opts = append(opts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(config.MaxMessageBytes)))
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating gRPC client for %s: %w", target, err)
+	}
+	r := NewGRPCBooksRepository(catalogpb.NewBooksCatalogClient(conn), config)
+	r.conn = conn
+	return r, nil
+}
+
+// NewGRPCBooksRepository crea el repositorio sobre un cliente ya construido
+// En este caso los reintentos dependen de la configuración de la conexión del cliente
+func NewGRPCBooksRepository(client catalogpb.BooksCatalogClient, config Config) *GRPCBooksRepository {
+	return &GRPCBooksRepository{client: client, config: config}
+}
+
+// Close cierra la conexión creada por Dial
+func (r *GRPCBooksRepository) Close() error {
+	if r.conn == nil {
+		return nil
+	}
+	return r.conn.Close()
+}
+
+// GetBooks obtiene el catálogo completo
+// Los errores se devuelven envueltos en los errores tipados de domain según el código gRPC
+func (r *GRPCBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	return r.listBooks(ctx, &catalogpb.ListBooksRequest{})
+}
+
+// QueryBooks pide al servicio sólo los libros del autor y aplica el resto de la consulta en memoria
+func (r *GRPCBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	books, err := r.listBooks(ctx, &catalogpb.ListBooksRequest{Author: query.Author})
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(books), nil
+}
+
+func (r *GRPCBooksRepository) listBooks(ctx context.Context, req *catalogpb.ListBooksRequest) ([]domain.Book, error) {
+	// El deadline del contexto viaja al servidor; sin deadline se usa el timeout configurado
+	if _, ok := ctx.Deadline(); !ok && r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
+	if r.config.Streaming {
+		return r.streamBooks(ctx, req)
+	}
+	resp, err := r.client.ListBooks(ctx, req)
+	if err != nil {
+		return nil, classifyError(ctx, err)
+	}
+	if r.config.MaxBooks > 0 && len(resp.GetBooks()) > r.config.MaxBooks {
+		return nil, fmt.Errorf("%w: more than %d books", domain.ErrPayloadTooLarge, r.config.MaxBooks)
+	}
+	books := make([]domain.Book, 0, len(resp.GetBooks()))
+	for i, message := range resp.GetBooks() {
+		book, err := toDomain(message)
+		if err != nil {
+			return nil, fmt.Errorf("book at index %d: %w", i, err)
+		}
+		books = append(books, book)
+	}
+	return books, nil
+}
+
+// streamBooks decodifica el catálogo a medida que llega
+func (r *GRPCBooksRepository) streamBooks(ctx context.Context, req *catalogpb.ListBooksRequest) ([]domain.Book, error) {
+	// Cancelar el stream si se abandona antes de terminar (por ejemplo al superar MaxBooks)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := r.client.ListBooksStream(ctx, req)
+	if err != nil {
+		return nil, classifyError(ctx, err)
+	}
+	books := []domain.Book{}
+	for {
+		message, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return books, nil
+		}
+		if err != nil {
+			return nil, classifyError(ctx, err)
+		}
+		if r.config.MaxBooks > 0 && len(books) >= r.config.MaxBooks {
+			return nil, fmt.Errorf("%w: more than %d books", domain.ErrPayloadTooLarge, r.config.MaxBooks)
+		}
+		book, err := toDomain(message)
+		if err != nil {
+			return nil, fmt.Errorf("book at index %d: %w", len(books), err)
+		}
+		books = append(books, book)
+	}
+}
+
+// toDomain convierte un libro del servicio; el precio viene en unidades enteras de domain.DefaultCurrency
+// Los valores que no entran en domain.Book son un payload inválido
+func toDomain(message *catalogpb.Book) (domain.Book, error) {
+	for _, value := range []uint64{message.GetId(), message.GetUnitsSold(), message.GetPrice()} {
+		if value > math.MaxUint32 {
+			return domain.Book{}, fmt.Errorf("%w: value %d out of range", domain.ErrMalformedPayload, value)
+		}
+	}
+	return domain.Book{
+		ID:        uint(message.GetId()),
+		Name:      message.GetName(),
+		Author:    message.GetAuthor(),
+		UnitsSold: uint(message.GetUnitsSold()),
+		Price:     domain.Price(uint(message.GetPrice())),
+	}, nil
+}
+
+// classifyError traduce un error gRPC al error tipado correspondiente
+func classifyError(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	}
+	switch status.Code(err) {
+	case codes.Canceled:
+		return fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+	case codes.DataLoss:
+		return fmt.Errorf("%w: %w", domain.ErrMalformedPayload, err)
+	case codes.ResourceExhausted:
+		// gRPC lo usa también cuando la respuesta supera el tamaño máximo de mensaje
+		if ctx.Err() == nil && isMessageTooLarge(err) {
+			return fmt.Errorf("%w: %w", domain.ErrPayloadTooLarge, err)
+		}
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	default:
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	}
+}
+
+func isMessageTooLarge(err error) bool {
+	message := status.Convert(err).Message()
+	return strings.Contains(message, "larger than max") || strings.Contains(message, "message too large")
+}
+
+// serviceConfig arma el service config JSON con la política de reintentos para BooksCatalog
+func (c Config) serviceConfig() (string, error) {
+	methodConfig := map[string]any{
+		"name": []map[string]string{{"service": catalogpb.BooksCatalog_ServiceDesc.ServiceName}},
+	}
+	p := c.RetryPolicy
+	if p.MaxAttempts > 1 {
+		if len(p.RetryableStatusCodes) == 0 || p.InitialBackoff <= 0 || p.MaxBackoff <= 0 || p.BackoffMultiplier <= 0 {
+			return "", errors.New("gRPC retry policy needs retryable codes and positive backoff settings")
+		}
+		retryableCodes := make([]string, 0, len(p.RetryableStatusCodes))
+		for _, code := range p.RetryableStatusCodes {
+			// El service config espera los nombres en mayúsculas (UNAVAILABLE, RESOURCE_EXHAUSTED)
+			retryableCodes = append(retryableCodes, strings.ToUpper(toSnakeCase(code.String())))
+		}
+		methodConfig["retryPolicy"] = map[string]any{
+			"maxAttempts":          p.MaxAttempts,
+			"initialBackoff":       seconds(p.InitialBackoff),
+			"maxBackoff":           seconds(p.MaxBackoff),
+			"backoffMultiplier":    p.BackoffMultiplier,
+			"retryableStatusCodes": retryableCodes,
+		}
+	}
+	data, err := json.Marshal(map[string]any{"methodConfig": []any{methodConfig}})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// seconds formatea una duración como la espera el service config ("0.100s")
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
+
+// toSnakeCase convierte el nombre de un código ("ResourceExhausted") a "resource_exhausted"
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	previous := rune(0)
+	for _, r := range name {
+		if unicode.IsUpper(r) && unicode.IsLower(previous) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+		previous = r
+	}
+	return b.String()
+}
@@ -0,0 +1,250 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/grpc/catalogpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// catalogServer es un BooksCatalog en memoria que puede fallar o demorarse a pedido
+type catalogServer struct {
+	catalogpb.UnimplementedBooksCatalogServer
+	books []*catalogpb.Book
+	// failures es la cantidad de llamadas iniciales que responden con failCode
+	failures atomic.Int32
+	failCode codes.Code
+	delay    time.Duration
+	calls    atomic.Int32
+	// lastAuthor es el filtro recibido en la última llamada
+	lastAuthor atomic.Value
+}
+
+func (s *catalogServer) prepare(ctx context.Context, req *catalogpb.ListBooksRequest) error {
+	s.calls.Add(1)
+	s.lastAuthor.Store(req.GetAuthor())
+	if s.failures.Add(-1) >= 0 {
+		return status.Error(s.failCode, "catalog not ready")
+	}
+	if s.delay > 0 {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(s.delay):
+		}
+	}
+	return nil
+}
+
+func (s *catalogServer) filter(author string) []*catalogpb.Book {
+	books := []*catalogpb.Book{}
+	for _, book := range s.books {
+		if author == "" || book.GetAuthor() == author {
+			books = append(books, book)
+		}
+	}
+	return books
+}
+
+func (s *catalogServer) ListBooks(ctx context.Context, req *catalogpb.ListBooksRequest) (*catalogpb.ListBooksResponse, error) {
+	if err := s.prepare(ctx, req); err != nil {
+		return nil, err
+	}
+	return &catalogpb.ListBooksResponse{Books: s.filter(req.GetAuthor())}, nil
+}
+
+func (s *catalogServer) ListBooksStream(req *catalogpb.ListBooksRequest, stream catalogpb.BooksCatalog_ListBooksStreamServer) error {
+	if err := s.prepare(stream.Context(), req); err != nil {
+		return err
+	}
+	for _, book := range s.filter(req.GetAuthor()) {
+		if err := stream.Send(book); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sampleCatalog() []*catalogpb.Book {
+	return []*catalogpb.Book{
+		{Id: 1, Name: "The Fellowship of the Ring", Author: "J.R.R. Tolkien", UnitsSold: 50000000, Price: 20},
+		{Id: 2, Name: "The Two Towers", Author: "J.R.R. Tolkien", UnitsSold: 30000000, Price: 20},
+		{Id: 3, Name: "A Game of Thrones", Author: "George R.R. Martin", UnitsSold: 90000000, Price: 15},
+	}
+}
+
+// startCatalog levanta el servidor en un bufconn y devuelve un repositorio conectado con Dial
+func startCatalog(t *testing.T, server *catalogServer, config Config) *GRPCBooksRepository {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	catalogpb.RegisterBooksCatalogServer(grpcServer, server)
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	repository, err := Dial("passthrough:///bufnet", config,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repository.Close() })
+	return repository
+}
+
+// fastRetries reintenta rápido para no demorar los tests
+func fastRetries(config Config) Config {
+	config.RetryPolicy.InitialBackoff = time.Millisecond
+	config.RetryPolicy.MaxBackoff = 5 * time.Millisecond
+	return config
+}
+
+func TestGRPCBooksRepository_GetBooks(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		config := DefaultConfig()
+		config.Streaming = streaming
+		repository := startCatalog(t, &catalogServer{books: sampleCatalog()}, config)
+
+		books, err := repository.GetBooks(context.Background())
+		require.NoError(t, err)
+		require.Len(t, books, 3)
+		assert.Equal(t, domain.Book{ID: 3, Name: "A Game of Thrones", Author: "George R.R. Martin", UnitsSold: 90000000, Price: domain.Price(15)}, books[2])
+	}
+}
+
+func TestGRPCBooksRepository_EmptyCatalog(t *testing.T) {
+	repository := startCatalog(t, &catalogServer{}, DefaultConfig())
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, books)
+	assert.Empty(t, books)
+}
+
+func TestGRPCBooksRepository_QueryBooksSendsAuthor(t *testing.T) {
+	server := &catalogServer{books: sampleCatalog()}
+	repository := startCatalog(t, server, DefaultConfig())
+
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{Author: "J.R.R. Tolkien", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	assert.Equal(t, "J.R.R. Tolkien", server.lastAuthor.Load())
+}
+
+func TestGRPCBooksRepository_RetriesViaServiceConfig(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		server := &catalogServer{books: sampleCatalog(), failCode: codes.Unavailable}
+		server.failures.Store(2)
+		config := fastRetries(DefaultConfig())
+		config.Streaming = streaming
+		repository := startCatalog(t, server, config)
+
+		// Los dos primeros intentos fallan y el cliente gRPC los reintenta sin que el repositorio lo note
+		books, err := repository.GetBooks(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, books, 3)
+		assert.Equal(t, int32(3), server.calls.Load())
+	}
+}
+
+func TestGRPCBooksRepository_RetriesExhausted(t *testing.T) {
+	server := &catalogServer{books: sampleCatalog(), failCode: codes.Unavailable}
+	server.failures.Store(10)
+	repository := startCatalog(t, server, fastRetries(DefaultConfig()))
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+	assert.Equal(t, int32(3), server.calls.Load())
+}
+
+func TestGRPCBooksRepository_NonRetryableCode(t *testing.T) {
+	server := &catalogServer{books: sampleCatalog(), failCode: codes.DataLoss}
+	server.failures.Store(1)
+	repository := startCatalog(t, server, fastRetries(DefaultConfig()))
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+	assert.Equal(t, int32(1), server.calls.Load())
+}
+
+func TestGRPCBooksRepository_UsesContextDeadline(t *testing.T) {
+	server := &catalogServer{books: sampleCatalog(), delay: time.Second}
+	repository := startCatalog(t, server, DefaultConfig())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := repository.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrUpstreamTimeout)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestGRPCBooksRepository_DefaultTimeout(t *testing.T) {
+	config := DefaultConfig()
+	config.Timeout = 50 * time.Millisecond
+	repository := startCatalog(t, &catalogServer{books: sampleCatalog(), delay: time.Second}, config)
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamTimeout)
+}
+
+func TestGRPCBooksRepository_Canceled(t *testing.T) {
+	repository := startCatalog(t, &catalogServer{books: sampleCatalog(), delay: time.Second}, DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	_, err := repository.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrContextCanceled)
+}
+
+func TestGRPCBooksRepository_MaxBooks(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		config := DefaultConfig()
+		config.Streaming = streaming
+		config.MaxBooks = 2
+		repository := startCatalog(t, &catalogServer{books: sampleCatalog()}, config)
+
+		_, err := repository.GetBooks(context.Background())
+		assert.ErrorIs(t, err, domain.ErrPayloadTooLarge)
+	}
+}
+
+func TestGRPCBooksRepository_ValueOutOfRange(t *testing.T) {
+	books := append(sampleCatalog(), &catalogpb.Book{Id: 4, Name: "Huge", Author: "Someone", Price: 1 << 40})
+	repository := startCatalog(t, &catalogServer{books: books}, DefaultConfig())
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrMalformedPayload)
+}
+
+func TestConfig_ServiceConfig(t *testing.T) {
+	serviceConfig, err := DefaultConfig().serviceConfig()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"methodConfig":[{
+		"name":[{"service":"bookshop.catalog.v1.BooksCatalog"}],
+		"retryPolicy":{"maxAttempts":3,"initialBackoff":"0.100s","maxBackoff":"2.000s","backoffMultiplier":2,
+			"retryableStatusCodes":["UNAVAILABLE","RESOURCE_EXHAUSTED"]}}]}`, serviceConfig)
+
+	// Sin reintentos no se publica retryPolicy
+	config := DefaultConfig()
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 1}
+	serviceConfig, err = config.serviceConfig()
+	require.NoError(t, err)
+	assert.NotContains(t, serviceConfig, "retryPolicy")
+
+	config.RetryPolicy = RetryPolicy{MaxAttempts: 3}
+	_, err = config.serviceConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+// Catálogo de libros expuesto por los servicios internos a través de gRPC
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: catalog.proto
+
+package catalogpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BooksCatalog_ListBooks_FullMethodName       = "/bookshop.catalog.v1.BooksCatalog/ListBooks"
+	BooksCatalog_ListBooksStream_FullMethodName = "/bookshop.catalog.v1.BooksCatalog/ListBooksStream"
+)
+
+// BooksCatalogClient is the client API for BooksCatalog service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BooksCatalog publica el catálogo de libros
+type BooksCatalogClient interface {
+	// ListBooks devuelve el catálogo completo en una única respuesta
+	ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (*ListBooksResponse, error)
+	// ListBooksStream envía el catálogo libro por libro, para catálogos grandes
+	ListBooksStream(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error)
+}
+
+type booksCatalogClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBooksCatalogClient(cc grpc.ClientConnInterface) BooksCatalogClient {
+	return &booksCatalogClient{cc}
+}
+
+func (c *booksCatalogClient) ListBooks(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (*ListBooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListBooksResponse)
+	err := c.cc.Invoke(ctx, BooksCatalog_ListBooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *booksCatalogClient) ListBooksStream(ctx context.Context, in *ListBooksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Book], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BooksCatalog_ServiceDesc.Streams[0], BooksCatalog_ListBooksStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListBooksRequest, Book]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BooksCatalog_ListBooksStreamClient = grpc.ServerStreamingClient[Book]
+
+// BooksCatalogServer is the server API for BooksCatalog service.
+// All implementations must embed UnimplementedBooksCatalogServer
+// for forward compatibility.
+//
+// BooksCatalog publica el catálogo de libros
+type BooksCatalogServer interface {
+	// ListBooks devuelve el catálogo completo en una única respuesta
+	ListBooks(context.Context, *ListBooksRequest) (*ListBooksResponse, error)
+	// ListBooksStream envía el catálogo libro por libro, para catálogos grandes
+	ListBooksStream(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error
+	mustEmbedUnimplementedBooksCatalogServer()
+}
+
+// UnimplementedBooksCatalogServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBooksCatalogServer struct{}
+
+func (UnimplementedBooksCatalogServer) ListBooks(context.Context, *ListBooksRequest) (*ListBooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBooks not implemented")
+}
+func (UnimplementedBooksCatalogServer) ListBooksStream(*ListBooksRequest, grpc.ServerStreamingServer[Book]) error {
+	return status.Errorf(codes.Unimplemented, "method ListBooksStream not implemented")
+}
+func (UnimplementedBooksCatalogServer) mustEmbedUnimplementedBooksCatalogServer() {}
+func (UnimplementedBooksCatalogServer) testEmbeddedByValue()                      {}
+
+// UnsafeBooksCatalogServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BooksCatalogServer will
+// result in compilation errors.
+type UnsafeBooksCatalogServer interface {
+	mustEmbedUnimplementedBooksCatalogServer()
+}
+
+func RegisterBooksCatalogServer(s grpc.ServiceRegistrar, srv BooksCatalogServer) {
+	// If the following call pancis, it indicates UnimplementedBooksCatalogServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BooksCatalog_ServiceDesc, srv)
+}
+
+func _BooksCatalog_ListBooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BooksCatalogServer).ListBooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BooksCatalog_ListBooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BooksCatalogServer).ListBooks(ctx, req.(*ListBooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BooksCatalog_ListBooksStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListBooksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BooksCatalogServer).ListBooksStream(m, &grpc.GenericServerStream[ListBooksRequest, Book]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BooksCatalog_ListBooksStreamServer = grpc.ServerStreamingServer[Book]
+
+// BooksCatalog_ServiceDesc is the grpc.ServiceDesc for BooksCatalog service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BooksCatalog_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bookshop.catalog.v1.BooksCatalog",
+	HandlerType: (*BooksCatalogServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListBooks",
+			Handler:    _BooksCatalog_ListBooks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListBooksStream",
+			Handler:       _BooksCatalog_ListBooksStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "catalog.proto",
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Authenticator agrega credenciales a una petición
+type Authenticator interface {
+	// Authenticate modifica la petición (que ya es una copia propia) antes de enviarla
+	Authenticate(req *http.Request) error
+}
+
+// invalidator es implementado por los autenticadores que guardan credenciales que el servidor puede rechazar
+type invalidator interface {
+	// Invalidate descarta la credencial guardada para obtener una nueva en la próxima petición
+	Invalidate()
+}
+
+// AuthenticatorFunc adapta una función a Authenticator
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate implementa Authenticator
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// Bearer envía un token fijo en el header Authorization
+func Bearer(token Secret) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token.Value())
+		return nil
+	})
+}
+
+// APIKeyHeader envía la API key en el header indicado (por ejemplo X-Api-Key)
+func APIKeyHeader(header string, key Secret) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.Header.Set(header, key.Value())
+		return nil
+	})
+}
+
+// APIKeyQuery envía la API key como parámetro de la URL, conservando los demás parámetros
+func APIKeyQuery(param string, key Secret) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		query := req.URL.Query()
+		query.Set(param, key.Value())
+		req.URL.RawQuery = query.Encode()
+		return nil
+	})
+}
+
+// Transport es un http.RoundTripper que autentica cada petición antes de enviarla
+// Si el servidor responde 401 y el autenticador guarda credenciales (como ClientCredentials),
+// las descarta y reintenta una vez con credenciales nuevas
+type Transport struct {
+	next          http.RoundTripper
+	authenticator Authenticator
+}
+
+// NewTransport crea el transporte alrededor de next (http.DefaultTransport si es nil)
+func NewTransport(next http.RoundTripper, authenticator Authenticator) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{next: next, authenticator: authenticator}
+}
+
+// Middleware adapta NewTransport a la firma de los middlewares de transporte
+func Middleware(authenticator Authenticator) func(next http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return NewTransport(next, authenticator)
+	}
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	inv, ok := t.authenticator.(invalidator)
+	if !ok || !replayable(req) {
+		return resp, nil
+	}
+
+	// El token pudo revocarse antes de su vencimiento: pedir uno nuevo y reintentar una vez
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	inv.Invalidate()
+	return t.send(req)
+}
+
+func (t *Transport) send(req *http.Request) (*http.Response, error) {
+	// Un RoundTripper no debe modificar la petición recibida
+	authenticated := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		authenticated.Body = body
+	}
+	if err := t.authenticator.Authenticate(authenticated); err != nil {
+		return nil, fmt.Errorf("authenticating request: %w", err)
+	}
+	return t.next.RoundTrip(authenticated)
+}
+
+// replayable indica si la petición puede enviarse de nuevo
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// errTokenResponse se usa para describir un fallo del servidor de tokens sin incluir el cuerpo,
+// que podría contener credenciales
+var errTokenResponse = errors.New("token endpoint rejected the request")
+
+// readLimited lee hasta limit bytes del cuerpo
+func readLimited(body io.Reader, limit int64) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.LimitReader(body, limit)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
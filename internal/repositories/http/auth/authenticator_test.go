@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecret_NeverPrinted(t *testing.T) {
+	secret := NewSecret("super-secret")
+	config := struct {
+		Token Secret `json:"token"`
+	}{secret}
+
+	data, _ := json.Marshal(config)
+	for _, out := range []string{fmt.Sprint(secret), fmt.Sprintf("%v %+v %#v %s", config, config, config, secret), string(data)} {
+		if strings.Contains(out, "super-secret") {
+			t.Errorf("Expected the secret to be redacted, got %s", out)
+		}
+	}
+	if secret.Value() != "super-secret" {
+		t.Errorf("Expected Value to return the secret")
+	}
+}
+
+func TestLoadSecret(t *testing.T) {
+	t.Setenv("BOOKS_TEST_TOKEN", "from-env")
+	secret, err := LoadSecret("BOOKS_TEST_TOKEN")
+	if err != nil || secret.Value() != "from-env" {
+		t.Errorf("Expected the secret from the environment, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BOOKS_TEST_KEY_FILE", path)
+	secret, err = LoadSecret("BOOKS_TEST_KEY")
+	if err != nil || secret.Value() != "from-file" {
+		t.Errorf("Expected the secret from the file without the newline, got %q, %v", secret.Value(), err)
+	}
+
+	if _, err := LoadSecret("BOOKS_TEST_MISSING"); err == nil {
+		t.Errorf("Expected an error for a missing secret")
+	}
+}
+
+func TestStaticAuthenticators(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		authenticator Authenticator
+		check         func(r *http.Request) bool
+	}{
+		{"bearer", Bearer(NewSecret("token")), func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == "Bearer token"
+		}},
+		{"api key header", APIKeyHeader("X-Api-Key", NewSecret("key")), func(r *http.Request) bool {
+			return r.Header.Get("X-Api-Key") == "key"
+		}},
+		{"api key query", APIKeyQuery("api_key", NewSecret("key")), func(r *http.Request) bool {
+			return r.URL.Query().Get("api_key") == "key" && r.URL.Query().Get("page") == "2"
+		}},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/books?page=2", nil)
+		resp, err := NewTransport(nil, tt.authenticator).RoundTrip(req)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		resp.Body.Close()
+		if !tt.check(got) {
+			t.Errorf("%s: credentials not sent, got %v %v", tt.name, got.URL, got.Header)
+		}
+		// La petición original no se modifica
+		if req.Header.Get("Authorization") != "" || req.URL.Query().Has("api_key") {
+			t.Errorf("%s: expected the original request to be untouched", tt.name)
+		}
+	}
+}
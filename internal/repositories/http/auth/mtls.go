@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// MutualTLSConfig carga el certificado y la clave del cliente y, si se indica caFile, las
+// autoridades con las que se verifica al servidor (si no, se usan las del sistema)
+func MutualTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		// El error de crypto/tls no incluye el contenido de la clave
+		return nil, fmt.Errorf("loading client certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s has no valid certificates", caFile)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// MutualTLS devuelve un middleware que presenta el certificado del cliente en cada conexión
+// Debe aplicarse sobre un *http.Transport (el transporte del cliente o http.DefaultTransport),
+// es decir antes que cualquier otro middleware; el transporte original no se modifica
+func MutualTLS(config *tls.Config) func(next http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		base, ok := next.(*http.Transport)
+		if !ok {
+			return errorTransport{errors.New("mutual TLS requires an *http.Transport")}
+		}
+		transport := base.Clone()
+		// Conservar la configuración TLS del transporte (por ejemplo un bundle de CA propio)
+		// salvo lo que define el TLS mutuo
+		tlsConfig := config.Clone()
+		if base.TLSClientConfig != nil {
+			tlsConfig = base.TLSClientConfig.Clone()
+			tlsConfig.Certificates = config.Certificates
+			if config.RootCAs != nil {
+				tlsConfig.RootCAs = config.RootCAs
+			}
+			tlsConfig.MinVersion = max(tlsConfig.MinVersion, config.MinVersion)
+		}
+		transport.TLSClientConfig = tlsConfig
+		return transport
+	}
+}
+
+// errorTransport falla todas las peticiones con el mismo error de configuración
+type errorTransport struct {
+	err error
+}
+
+func (t errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
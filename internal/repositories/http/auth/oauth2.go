@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientCredentialsConfig define cómo obtener tokens con el flujo OAuth2 client credentials
+type ClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret Secret
+	Scopes       []string
+	// BasicAuth envía las credenciales del cliente en el header Authorization en lugar del cuerpo
+	BasicAuth bool
+	// RefreshBefore es cuánto antes del vencimiento se pide un token nuevo
+	RefreshBefore time.Duration
+	// Client es el cliente usado para pedir tokens (uno con timeout de 10s si es nil)
+	Client *http.Client
+}
+
+// DefaultRefreshBefore es la anticipación con la que se renuevan los tokens por defecto
+const DefaultRefreshBefore = 30 * time.Second
+
+// maxTokenResponse limita el tamaño de la respuesta del servidor de tokens
+const maxTokenResponse = 1 << 20
+
+// ClientCredentials es un Authenticator que obtiene tokens OAuth2 y los reutiliza hasta poco antes
+// de que venzan; las peticiones concurrentes comparten una única obtención
+type ClientCredentials struct {
+	config ClientCredentialsConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   Secret
+	expires time.Time
+}
+
+// NewClientCredentials crea el autenticador; el primer token se pide con la primera petición
+func NewClientCredentials(config ClientCredentialsConfig) *ClientCredentials {
+	if config.RefreshBefore <= 0 {
+		config.RefreshBefore = DefaultRefreshBefore
+	}
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &ClientCredentials{config: config, now: time.Now}
+}
+
+// Authenticate implementa Authenticator
+func (c *ClientCredentials) Authenticate(req *http.Request) error {
+	token, err := c.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Value())
+	return nil
+}
+
+// Token devuelve el token vigente, pidiendo uno nuevo si no hay o está por vencer
+// Si la renovación falla y el token anterior todavía no venció, se sigue usando
+func (c *ClientCredentials) Token(ctx context.Context) (Secret, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if !c.token.IsZero() && (c.expires.IsZero() || now.Add(c.config.RefreshBefore).Before(c.expires)) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := c.fetch(ctx)
+	if err != nil {
+		if !c.token.IsZero() && now.Before(c.expires) {
+			return c.token, nil
+		}
+		return Secret{}, err
+	}
+	c.token = token
+	c.expires = time.Time{}
+	if expiresIn > 0 {
+		c.expires = c.now().Add(expiresIn)
+	}
+	return c.token, nil
+}
+
+// Invalidate descarta el token guardado, por ejemplo porque el servidor lo rechazó
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = Secret{}
+	c.expires = time.Time{}
+}
+
+// tokenResponse es la respuesta del servidor de tokens (RFC 6749, sección 5)
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	// Error y ErrorDescription vienen en las respuestas de error y no contienen credenciales
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// fetch pide un token nuevo; los errores nunca incluyen el secreto ni el token
+func (c *ClientCredentials) fetch(ctx context.Context) (Secret, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+	if !c.config.BasicAuth {
+		form.Set("client_id", c.config.ClientID)
+		form.Set("client_secret", c.config.ClientSecret.Value())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Secret{}, 0, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if c.config.BasicAuth {
+		req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret.Value()))
+	}
+
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return Secret{}, 0, fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := readLimited(resp.Body, maxTokenResponse)
+	if err != nil {
+		return Secret{}, 0, fmt.Errorf("reading token response: %w", err)
+	}
+
+	var token tokenResponse
+	decodeErr := json.Unmarshal(body, &token)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && token.Error != "" {
+			return Secret{}, 0, fmt.Errorf("%w: status %d: %s %s", errTokenResponse, resp.StatusCode, token.Error, token.ErrorDescription)
+		}
+		return Secret{}, 0, fmt.Errorf("%w: status %d", errTokenResponse, resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return Secret{}, 0, fmt.Errorf("%w: invalid JSON response", errTokenResponse)
+	}
+	if token.AccessToken == "" {
+		return Secret{}, 0, fmt.Errorf("%w: response without access_token", errTokenResponse)
+	}
+	if token.TokenType != "" && !strings.EqualFold(token.TokenType, "bearer") {
+		return Secret{}, 0, fmt.Errorf("%w: unsupported token type %q", errTokenResponse, token.TokenType)
+	}
+	return NewSecret(token.AccessToken), time.Duration(token.ExpiresIn) * time.Second, nil
+}
@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	booksrepo "educabot.com/bookshop/internal/repositories/http"
+)
+
+// tokenServer simula un servidor de tokens OAuth2 y un proveedor que exige el token vigente
+type tokenServer struct {
+	server    *httptest.Server
+	issued    atomic.Int32
+	expiresIn int
+	// revoked hace que el proveedor rechace el token actual una vez
+	revoked atomic.Bool
+	current atomic.Value
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.current.Store("")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok {
+			r.ParseForm()
+			id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		if r.FormValue("grant_type") != "client_credentials" || id != "bookshop" || secret != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client", "error_description": "bad credentials"})
+			return
+		}
+		token := fmt.Sprintf("token-%d", ts.issued.Add(1))
+		ts.current.Store(token)
+		json.NewEncoder(w).Encode(map[string]any{"access_token": token, "token_type": "Bearer", "expires_in": ts.expiresIn})
+	})
+	mux.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+ts.current.Load().(string) || ts.revoked.CompareAndSwap(true, false) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	ts.server = httptest.NewServer(mux)
+	t.Cleanup(ts.server.Close)
+	return ts
+}
+
+func (ts *tokenServer) config() ClientCredentialsConfig {
+	return ClientCredentialsConfig{
+		TokenURL:     ts.server.URL + "/token",
+		ClientID:     "bookshop",
+		ClientSecret: NewSecret("s3cr3t"),
+		Scopes:       []string{"books:read"},
+	}
+}
+
+func (ts *tokenServer) get(t *testing.T, client *http.Client) int {
+	t.Helper()
+	resp, err := client.Get(ts.server.URL + "/books")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestClientCredentials_CachesToken(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	client := &http.Client{Transport: NewTransport(nil, NewClientCredentials(ts.config()))}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if status := ts.get(t, client); status != http.StatusOK {
+				t.Errorf("Expected 200, got %d", status)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if issued := ts.issued.Load(); issued != 1 {
+		t.Errorf("Expected a single token for concurrent requests, got %d", issued)
+	}
+}
+
+func TestClientCredentials_BasicAuth(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	config := ts.config()
+	config.BasicAuth = true
+	client := &http.Client{Transport: NewTransport(nil, NewClientCredentials(config))}
+
+	if status := ts.get(t, client); status != http.StatusOK {
+		t.Errorf("Expected 200, got %d", status)
+	}
+}
+
+func TestClientCredentials_RefreshesBeforeExpiry(t *testing.T) {
+	ts := newTokenServer(t, 60)
+	credentials := NewClientCredentials(ts.config())
+	now := time.Now()
+	credentials.now = func() time.Time { return now }
+
+	first, err := credentials.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Faltan 40s para el vencimiento: se reutiliza
+	now = now.Add(20 * time.Second)
+	if token, _ := credentials.Token(context.Background()); token.Value() != first.Value() {
+		t.Errorf("Expected the cached token, got a new one")
+	}
+	// Faltan 20s, menos que RefreshBefore (30s): se renueva antes de que venza
+	now = now.Add(20 * time.Second)
+	if token, _ := credentials.Token(context.Background()); token.Value() == first.Value() {
+		t.Errorf("Expected the token to be refreshed before expiry")
+	}
+	if issued := ts.issued.Load(); issued != 2 {
+		t.Errorf("Expected 2 tokens, got %d", issued)
+	}
+}
+
+func TestClientCredentials_KeepsValidTokenWhenRefreshFails(t *testing.T) {
+	ts := newTokenServer(t, 60)
+	credentials := NewClientCredentials(ts.config())
+	now := time.Now()
+	credentials.now = func() time.Time { return now }
+
+	first, err := credentials.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ts.server.Close()
+
+	now = now.Add(40 * time.Second)
+	token, err := credentials.Token(context.Background())
+	if err != nil || token.Value() != first.Value() {
+		t.Errorf("Expected the still valid token while the token server is down, got %v", err)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, err := credentials.Token(context.Background()); err == nil {
+		t.Errorf("Expected an error once the token expired")
+	}
+}
+
+func TestClientCredentials_RetriesOnceWithNewTokenAfter401(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	client := &http.Client{Transport: NewTransport(nil, NewClientCredentials(ts.config()))}
+
+	if status := ts.get(t, client); status != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", status)
+	}
+	ts.revoked.Store(true)
+	if status := ts.get(t, client); status != http.StatusOK {
+		t.Errorf("Expected the retry with a new token to succeed, got %d", status)
+	}
+	if issued := ts.issued.Load(); issued != 2 {
+		t.Errorf("Expected 2 tokens, got %d", issued)
+	}
+}
+
+func TestClientCredentials_ErrorsDoNotLeakSecrets(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	config := ts.config()
+	config.ClientSecret = NewSecret("wrong-secret")
+	client := &http.Client{Transport: NewTransport(nil, NewClientCredentials(config))}
+
+	_, err := client.Get(ts.server.URL + "/books")
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if strings.Contains(err.Error(), "wrong-secret") {
+		t.Errorf("Expected the error not to include the secret: %v", err)
+	}
+	if !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected the OAuth2 error code in the message: %v", err)
+	}
+}
+
+func TestClientCredentials_WithHTTPBooksRepository(t *testing.T) {
+	ts := newTokenServer(t, 3600)
+	repository := booksrepo.NewHTTPBooksRepositoryWithConfig(ts.server.Client(), ts.server.URL+"/books",
+		booksrepo.WithTransportMiddleware(Middleware(NewClientCredentials(ts.config()))))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the authenticated request to succeed, got %v", err)
+	}
+	if len(books) != 0 {
+		t.Errorf("Expected an empty catalog, got %d books", len(books))
+	}
+}
@@ -0,0 +1,74 @@
+// Package auth agrega credenciales a las peticiones al proveedor de libros: tokens bearer,
+// API keys en headers o parámetros, OAuth2 client credentials y TLS mutuo
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Secret es una credencial que nunca se muestra: al imprimirla o serializarla se ve "REDACTED"
+// El valor real sólo se obtiene con Value, al armar la petición
+type Secret struct {
+	value string
+}
+
+const redacted = "REDACTED"
+
+// NewSecret envuelve un valor ya leído
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// LoadSecret lee la credencial de la variable de entorno name o, si no está definida, del archivo
+// indicado en name_FILE (por ejemplo un secreto montado por el orquestador)
+// Se descartan los saltos de línea finales del archivo
+func LoadSecret(name string) (Secret, error) {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		return NewSecret(value), nil
+	}
+	if path := os.Getenv(name + "_FILE"); path != "" {
+		return SecretFromFile(path)
+	}
+	return Secret{}, fmt.Errorf("secret %s is not set (define %s or %s_FILE)", name, name, name)
+}
+
+// SecretFromFile lee la credencial de un archivo
+func SecretFromFile(path string) (Secret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		// El error de os no incluye el contenido, sólo la ruta
+		return Secret{}, fmt.Errorf("reading secret: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return Secret{}, fmt.Errorf("secret file %s is empty", path)
+	}
+	return NewSecret(value), nil
+}
+
+// Value devuelve el valor real de la credencial
+func (s Secret) Value() string {
+	return s.value
+}
+
+// IsZero indica si la credencial está vacía
+func (s Secret) IsZero() bool {
+	return s.value == ""
+}
+
+// String implementa fmt.Stringer sin mostrar el valor
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString oculta el valor también con %#v
+func (s Secret) GoString() string {
+	return "auth.Secret(" + redacted + ")"
+}
+
+// MarshalJSON oculta el valor al serializar configuraciones
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
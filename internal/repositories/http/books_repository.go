@@ -2,13 +2,15 @@ package http
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
+	"slices"
 	"time"
 
 	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/validation"
 )
 
 const (
@@ -18,78 +20,280 @@ const (
 
 // HTTPBooksRepository implementa el repositorio de libros usando HTTP
 type HTTPBooksRepository struct {
-	client *http.Client
-	apiURL string
+	client       *http.Client
+	apiURL       string
+	retryPolicy  RetryPolicy
+	decodeLimits DecodeLimits
+	mapping      *Mapping
+	authorParam  string
+	pagination   PaginationConfig
+	conditional  bool
+	validators   validatorCache
+	snapshots    *snapshotStore
+	validation   *validation.Pipeline
+	quarantine   quarantineStore
+	limits       *hostLimiters
+	stats        retryCounters
+	// transportConfig es nil si el cliente se recibió ya construido
+	transportConfig *TransportConfig
 }
 
-// NewHTTPBooksRepository crea una nueva instancia del repositorio de libros HTTP
-func NewHTTPBooksRepository() *HTTPBooksRepository {
-	return &HTTPBooksRepository{
-		client: &http.Client{
-			Timeout: timeout,
-		},
-		apiURL: defaultBooksAPIURL,
+// Option configura aspectos opcionales del repositorio HTTP
+type Option func(*HTTPBooksRepository)
+
+// WithRetryPolicy reemplaza la política de reintentos por defecto
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *HTTPBooksRepository) {
+		r.retryPolicy = policy
 	}
 }
 
+// WithTransportMiddleware envuelve el transporte del cliente HTTP, por ejemplo para inyectar fallas
+// o autenticar las peticiones; next es el transporte del cliente o http.DefaultTransport
+// El cliente recibido no se modifica: el repositorio usa una copia
+func WithTransportMiddleware(wrap func(next http.RoundTripper) http.RoundTripper) Option {
+	return func(r *HTTPBooksRepository) {
+		client := *r.client
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		client.Transport = wrap(next)
+		r.client = &client
+	}
+}
+
+// NewHTTPBooksRepository crea una nueva instancia del repositorio de libros HTTP
+func NewHTTPBooksRepository(opts ...Option) *HTTPBooksRepository {
+	return NewHTTPBooksRepositoryWithConfig(&http.Client{Timeout: timeout}, defaultBooksAPIURL, opts...)
+}
+
 // NewHTTPBooksRepositoryWithConfig crea una nueva instancia del repositorio de libros HTTP con configuración personalizada
 // Útil para pruebas o entornos específicos
-func NewHTTPBooksRepositoryWithConfig(client *http.Client, apiURL string) *HTTPBooksRepository {
-	return &HTTPBooksRepository{
-		client: client,
-		apiURL: apiURL,
+func NewHTTPBooksRepositoryWithConfig(client *http.Client, apiURL string, opts ...Option) *HTTPBooksRepository {
+	r := &HTTPBooksRepository{
+		client:       client,
+		apiURL:       apiURL,
+		retryPolicy:  DefaultRetryPolicy(),
+		decodeLimits: DefaultDecodeLimits(),
+		conditional:  true,
+		authorParam:  defaultAuthorParam,
+		validation:   validation.NewPipeline(validation.PolicyKeep, validation.DefaultRules()...),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.snapshots != nil {
+		// Cargar el último snapshot al iniciar para tenerlo listo si el proveedor está caído
+		r.snapshots.latestValid()
+	}
+	return r
+}
+
+// RetryStats devuelve los contadores de intentos acumulados por el repositorio
+func (p *HTTPBooksRepository) RetryStats() RetryStats {
+	return p.stats.snapshot()
 }
 
 // GetBooks obtiene libros desde la API externa
-func (p *HTTPBooksRepository) GetBooks(ctx context.Context) []domain.Book {
-	// Verificar si el contexto ya ha sido cancelado
-	if ctx.Err() != nil {
-		fmt.Printf("Context already canceled: %v\n", ctx.Err())
-		return []domain.Book{}
+// Un catálogo vacío se devuelve como slice vacío sin error; cualquier fallo se
+// devuelve envuelto en uno de los errores tipados de domain
+// Los fallos transitorios se reintentan según la política de reintentos configurada
+// Los libros inválidos se tratan según la política del pipeline de validación
+func (p *HTTPBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	p.stats.calls.Add(1)
+
+	books, summary, err := p.fetchCatalog(ctx, p.apiURL)
+	if err != nil {
+		return p.serveSnapshot(ctx, err)
+	}
+
+	// Avisar a las capas superiores que el catálogo no cambió desde la última obtención
+	if summary.notModified {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.NotModified = true
+		})
+	}
+
+	// Validar los datos recibidos para asegurar integridad; los inválidos quedan en cuarentena
+	books, err = p.validate(ctx, books, true)
+	if err != nil {
+		return p.serveSnapshot(ctx, err)
+	}
+
+	// Guardar un snapshot sólo si el catálogo está completo y cambió
+	if p.snapshots != nil && !summary.notModified && !summary.partial {
+		if err := p.snapshots.save(p.apiURL, books); err != nil {
+			// El catálogo es válido igualmente: se informa como advertencia y no como error
+			domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+				info.Warnings = append(info.Warnings, fmt.Sprintf("could not save books snapshot: %v", err))
+			})
+		}
+	}
+
+	return books, nil
+}
+
+// fetchCatalog obtiene el catálogo completo de baseURL, con o sin paginación
+func (p *HTTPBooksRepository) fetchCatalog(ctx context.Context, baseURL string) ([]domain.Book, fetchSummary, error) {
+	if p.pagination.Strategy != PaginationNone {
+		return p.fetchAllPages(ctx, baseURL)
+	}
+	page, err := p.fetchWithRetry(ctx, baseURL)
+	if err != nil {
+		return nil, fetchSummary{}, err
+	}
+	return page.books, fetchSummary{notModified: page.notModified}, nil
+}
+
+// serveSnapshot responde con el último snapshot válido cuando falla la obtención
+// Si no hay snapshots configurados o disponibles, o quien llama canceló, se devuelve la causa original
+func (p *HTTPBooksRepository) serveSnapshot(ctx context.Context, cause error) ([]domain.Book, error) {
+	if p.snapshots == nil || errors.Is(cause, domain.ErrContextCanceled) {
+		return nil, cause
+	}
+	snap, err := p.snapshots.latestValid()
+	if err != nil {
+		return nil, cause
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL, nil)
+	domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+		info.FromSnapshot = true
+		info.SnapshotAt = snap.createdAt
+		info.Warnings = append(info.Warnings, fmt.Sprintf("serving snapshot from %s: %v", snap.createdAt.Format(time.RFC3339), cause))
+	})
+	return slices.Clone(snap.books), nil
+}
+
+// fetchResult es el resultado de una petición exitosa a la API externa
+type fetchResult struct {
+	books  []domain.Book
+	header http.Header
+	// notModified indica que el servidor respondió 304 y se reutilizaron los libros anteriores
+	notModified bool
+}
+
+// fetchWithRetry obtiene los libros de la URL indicada reintentando los fallos transitorios
+func (p *HTTPBooksRepository) fetchWithRetry(ctx context.Context, url string) (*fetchResult, error) {
+	maxAttempts := max(p.retryPolicy.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		// Verificar si el contexto ya ha sido cancelado
+		if err := ctx.Err(); err != nil {
+			return nil, classifyRequestError(ctx, err)
+		}
+
+		p.stats.attempts.Add(1)
+		if attempt > 1 {
+			p.stats.retries.Add(1)
+		}
+
+		result, err := p.fetchBooks(ctx, url)
+		if err == nil {
+			return result, nil
+		}
+		if attempt >= maxAttempts || !p.retryPolicy.shouldRetry(err) {
+			if attempt > 1 {
+				p.stats.exhausted.Add(1)
+				return nil, fmt.Errorf("after %d attempts: %w", attempt, err)
+			}
+			return nil, err
+		}
+
+		delay := p.retryPolicy.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+			// Una espera mayor al límite retendría la petición del cliente: se devuelve el error
+			if limit := p.retryPolicy.retryAfterLimit(); limit > 0 && statusErr.RetryAfter > limit {
+				p.stats.exhausted.Add(1)
+				return nil, fmt.Errorf("after %d attempts (Retry-After %s exceeds %s): %w", attempt, statusErr.RetryAfter, limit, err)
+			}
+			delay = statusErr.RetryAfter
+		}
+
+		// No esperar más allá del deadline de quien llama: se devuelve el último error
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			p.stats.exhausted.Add(1)
+			return nil, fmt.Errorf("after %d attempts (deadline reached before next retry): %w", attempt, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, classifyRequestError(ctx, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// fetchBooks realiza un único intento de obtener los libros desde la URL indicada
+func (p *HTTPBooksRepository) fetchBooks(ctx context.Context, url string) (*fetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return []domain.Book{}
+		return nil, fmt.Errorf("%w: creating request: %w", domain.ErrUpstreamUnavailable, err)
 	}
 
 	// Añadir un header de aceptación para especificar que esperamos JSON
 	req.Header.Add("Accept", "application/json")
+	if p.conditional {
+		p.validators.apply(req)
+	}
+
+	// Respetar los límites del host; el lugar se libera al terminar de leer la respuesta
+	var limiter *hostLimiter
+	if p.limits != nil {
+		limiter = p.limits.get(req.URL.Host)
+		release, err := limiter.acquire(ctx, req.URL.Host)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		fmt.Printf("Error making request: %v\n", err)
-		return []domain.Book{}
+		return nil, classifyRequestError(ctx, err)
 	}
 	defer resp.Body.Close()
+	if limiter != nil {
+		limiter.observe(resp)
+	}
+
+	// Con un 304 se reutilizan los libros ya decodificados para esta URL
+	if resp.StatusCode == http.StatusNotModified && p.conditional {
+		if books, ok := p.validators.notModified(url); ok {
+			return &fetchResult{books: books, header: resp.Header, notModified: true}, nil
+		}
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Unexpected status code: %d\n", resp.StatusCode)
-		return []domain.Book{}
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	// Limitar el tamaño del cuerpo para prevenir ataques DoS
-	const maxBodySize = 1 << 20 // 1 MB
-	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	// Decodificar el cuerpo en streaming respetando los límites configurados
+	books, err := decodeBooks(ctx, resp.Body, p.decodeLimits, p.mapping)
 	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return []domain.Book{}
+		return nil, err
 	}
 
-	var books []domain.Book
-	if err := json.Unmarshal(body, &books); err != nil {
-		fmt.Printf("Error unmarshaling response: %v\n", err)
-		return []domain.Book{}
+	if p.conditional {
+		p.validators.store(url, resp.Header, books)
 	}
+	return &fetchResult{books: books, header: resp.Header}, nil
+}
 
-	// Validar los datos recibidos para asegurar integridad
-	for i, book := range books {
-		if book.ID == 0 || book.Name == "" {
-			fmt.Printf("Warning: Book at index %d has missing required fields\n", i)
-		}
+// classifyRequestError traduce un error de red o de contexto al error tipado correspondiente
+func classifyRequestError(ctx context.Context, err error) error {
+	var netErr net.Error
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+	default:
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
 	}
-
-	return books
-}
\ No newline at end of file
+}
@@ -2,9 +2,11 @@ package http
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"educabot.com/bookshop/internal/core/domain"
 )
@@ -39,7 +41,10 @@ func TestHTTPBooksRepository_GetBooks(t *testing.T) {
 	}
 
 	// Call the GetBooks method
-	books := repository.GetBooks(context.Background())
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	// Verify the results
 	if len(books) != 2 {
@@ -52,7 +57,7 @@ func TestHTTPBooksRepository_GetBooks(t *testing.T) {
 		Name:      "Test Book 1",
 		Author:    "Test Author 1",
 		UnitsSold: 1000,
-		Price:     25,
+		Price:     domain.Price(25),
 	}
 	if books[0] != expectedBook1 {
 		t.Errorf("Expected book %+v, got %+v", expectedBook1, books[0])
@@ -64,7 +69,7 @@ func TestHTTPBooksRepository_GetBooks(t *testing.T) {
 		Name:      "Test Book 2",
 		Author:    "Test Author 2",
 		UnitsSold: 2000,
-		Price:     30,
+		Price:     domain.Price(30),
 	}
 	if books[1] != expectedBook2 {
 		t.Errorf("Expected book %+v, got %+v", expectedBook2, books[1])
@@ -85,9 +90,12 @@ func TestHTTPBooksRepository_GetBooks_Error(t *testing.T) {
 	}
 
 	// Call the GetBooks method
-	books := repository.GetBooks(context.Background())
+	books, err := repository.GetBooks(context.Background())
 
-	// Verify that an empty slice is returned on error
+	// Verify that the typed error is returned on a non-200 status
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
 	if len(books) != 0 {
 		t.Errorf("Expected 0 books on error, got %d", len(books))
 	}
@@ -109,9 +117,12 @@ func TestHTTPBooksRepository_GetBooks_InvalidJSON(t *testing.T) {
 	}
 
 	// Call the GetBooks method
-	books := repository.GetBooks(context.Background())
+	books, err := repository.GetBooks(context.Background())
 
-	// Verify that an empty slice is returned on JSON parsing error
+	// Verify that a malformed payload error is returned on JSON parsing error
+	if !errors.Is(err, domain.ErrMalformedPayload) {
+		t.Errorf("Expected ErrMalformedPayload, got %v", err)
+	}
 	if len(books) != 0 {
 		t.Errorf("Expected 0 books on JSON parsing error, got %d", len(books))
 	}
@@ -126,9 +137,12 @@ func TestHTTPBooksRepository_GetBooks_ContextCanceled(t *testing.T) {
 	repository := NewHTTPBooksRepository()
 
 	// Call the GetBooks method with canceled context
-	books := repository.GetBooks(ctx)
+	books, err := repository.GetBooks(ctx)
 
-	// Verify that an empty slice is returned on canceled context
+	// Verify that a context canceled error is returned on canceled context
+	if !errors.Is(err, domain.ErrContextCanceled) {
+		t.Errorf("Expected ErrContextCanceled, got %v", err)
+	}
 	if len(books) != 0 {
 		t.Errorf("Expected 0 books on canceled context, got %d", len(books))
 	}
@@ -153,7 +167,10 @@ func TestHTTPBooksRepository_GetBooks_InvalidData(t *testing.T) {
 	}
 
 	// Call the GetBooks method
-	books := repository.GetBooks(context.Background())
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
 
 	// Verify books are returned even with missing fields, since the repository
 	// should handle this gracefully with warnings
@@ -170,4 +187,63 @@ func TestHTTPBooksRepository_GetBooks_InvalidData(t *testing.T) {
 	if books[1].ID != 0 {
 		t.Errorf("Expected ID 0 for second book, got %d", books[1].ID)
 	}
-}
\ No newline at end of file
+}
+
+func TestHTTPBooksRepository_GetBooks_EmptyCatalog(t *testing.T) {
+	// Create a mock server that returns an empty catalog
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+
+	books, err := repository.GetBooks(context.Background())
+
+	// An empty catalog is not an outage
+	if err != nil {
+		t.Fatalf("Expected no error for an empty catalog, got %v", err)
+	}
+	if books == nil || len(books) != 0 {
+		t.Errorf("Expected an empty, non-nil slice, got %#v", books)
+	}
+}
+
+func TestHTTPBooksRepository_GetBooks_Timeout(t *testing.T) {
+	// Create a mock server that answers slower than the client timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := server.Client()
+	client.Timeout = 20 * time.Millisecond
+	repository := NewHTTPBooksRepositoryWithConfig(client, server.URL)
+
+	_, err := repository.GetBooks(context.Background())
+
+	if !errors.Is(err, domain.ErrUpstreamTimeout) {
+		t.Errorf("Expected ErrUpstreamTimeout, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_GetBooks_Unreachable(t *testing.T) {
+	// Start and immediately close a server so the address refuses connections
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(&http.Client{Timeout: time.Second}, url)
+
+	_, err := repository.GetBooks(context.Background())
+
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
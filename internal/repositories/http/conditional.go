@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// WithConditionalRequests activa o desactiva el uso de ETag y Last-Modified para
+// evitar volver a descargar y decodificar un catálogo que no cambió (activado por defecto)
+func WithConditionalRequests(enabled bool) Option {
+	return func(r *HTTPBooksRepository) {
+		r.conditional = enabled
+	}
+}
+
+// validatorEntry guarda los validadores y el resultado decodificado de una URL
+type validatorEntry struct {
+	etag         string
+	lastModified string
+	books        []domain.Book
+}
+
+// validatorCache recuerda los validadores de cada URL pedida (una por página al paginar)
+type validatorCache struct {
+	mu      sync.Mutex
+	entries map[string]validatorEntry
+}
+
+// apply añade If-None-Match e If-Modified-Since si hay validadores guardados para la URL
+func (c *validatorCache) apply(req *http.Request) {
+	c.mu.Lock()
+	entry, ok := c.entries[validatorKey(req.URL.String())]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// notModified devuelve una copia de los libros guardados para la URL ante una respuesta 304
+func (c *validatorCache) notModified(rawURL string) ([]domain.Book, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[validatorKey(rawURL)]
+	if !ok {
+		return nil, false
+	}
+	return slices.Clone(entry.books), true
+}
+
+// store guarda los validadores de una respuesta 200; si no trae ninguno se olvida la URL
+func (c *validatorCache) store(rawURL string, header http.Header, books []domain.Book) {
+	etag, lastModified := header.Get("ETag"), header.Get("Last-Modified")
+	key := validatorKey(rawURL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if etag == "" && lastModified == "" {
+		delete(c.entries, key)
+		return
+	}
+	if c.entries == nil {
+		c.entries = make(map[string]validatorEntry)
+	}
+	c.entries[key] = validatorEntry{
+		etag:         etag,
+		lastModified: lastModified,
+		books:        slices.Clone(books),
+	}
+}
+
+// validatorKey normaliza la URL como la serializa net/http al enviar la petición, para que
+// store y notModified (que reciben la URL configurada) y apply (que recibe la petición) coincidan
+func validatorKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.String()
+}
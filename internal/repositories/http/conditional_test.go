@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// versionedServer sirve un catálogo con ETag y responde 304 si el cliente ya tiene la versión actual
+type versionedServer struct {
+	mu      sync.Mutex
+	etag    string
+	body    string
+	headers []http.Header
+}
+
+func (s *versionedServer) setVersion(etag, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.etag, s.body = etag, body
+}
+
+func (s *versionedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, r.Header.Clone())
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	w.Write([]byte(s.body))
+}
+
+func TestHTTPBooksRepository_Conditional_ETag(t *testing.T) {
+	versioned := &versionedServer{}
+	versioned.setVersion(`"v1"`, `[{"id": 1, "name": "Book 1", "author": "Author", "units_sold": 10, "price": 5}]`)
+	server := httptest.NewServer(versioned)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+
+	first, err := repository.GetBooks(context.Background())
+	if err != nil || len(first) != 1 {
+		t.Fatalf("Expected 1 book, got %v (%v)", first, err)
+	}
+
+	// Segunda llamada: el servidor responde 304 y se reutiliza el catálogo anterior
+	ctx := domain.WithFetchInfo(context.Background())
+	second, err := repository.GetBooks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error on 304, got %v", err)
+	}
+	if len(second) != 1 || second[0] != first[0] {
+		t.Errorf("Expected the previous catalog on 304, got %v", second)
+	}
+	if info, _ := domain.FetchInfoFromContext(ctx); !info.NotModified {
+		t.Errorf("Expected NotModified to be recorded, got %+v", info)
+	}
+	if got := versioned.headers[1].Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("Expected If-None-Match \"v1\", got %q", got)
+	}
+
+	// Tercera llamada: el catálogo cambió y se decodifica la nueva versión
+	versioned.setVersion(`"v2"`, `[{"id": 2, "name": "Book 2"}, {"id": 3, "name": "Book 3"}]`)
+	third, err := repository.GetBooks(context.Background())
+	if err != nil || len(third) != 2 {
+		t.Fatalf("Expected 2 books after the change, got %v (%v)", third, err)
+	}
+}
+
+func TestHTTPBooksRepository_Conditional_NonCanonicalURL(t *testing.T) {
+	versioned := &versionedServer{}
+	versioned.setVersion(`"v1"`, `[{"id": 1, "name": "Book 1", "author": "Author", "units_sold": 10, "price": 5}]`)
+	server := httptest.NewServer(versioned)
+	defer server.Close()
+
+	// El "?" final no se envía, así que la URL de la petición difiere de la configurada
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL+"/books?")
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ctx := domain.WithFetchInfo(context.Background())
+	if _, err := repository.GetBooks(ctx); err != nil {
+		t.Fatalf("Expected no error on 304, got %v", err)
+	}
+
+	if got := versioned.headers[1].Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("Expected If-None-Match %q, got %q", `"v1"`, got)
+	}
+	if info, _ := domain.FetchInfoFromContext(ctx); !info.NotModified {
+		t.Errorf("Expected NotModified to be recorded, got %+v", info)
+	}
+}
+
+func TestHTTPBooksRepository_Conditional_LastModified(t *testing.T) {
+	const lastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+	var mu sync.Mutex
+	var ifModifiedSince []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ifModifiedSince = append(ifModifiedSince, r.Header.Get("If-Modified-Since"))
+		mu.Unlock()
+		if r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte(`[{"id": 1, "name": "Book 1"}]`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+
+	for i := 0; i < 2; i++ {
+		books, err := repository.GetBooks(context.Background())
+		if err != nil || len(books) != 1 {
+			t.Fatalf("Call %d: expected 1 book, got %v (%v)", i, books, err)
+		}
+	}
+	if ifModifiedSince[0] != "" || ifModifiedSince[1] != lastModified {
+		t.Errorf("Unexpected If-Modified-Since headers: %q", ifModifiedSince)
+	}
+}
+
+func TestHTTPBooksRepository_Conditional_Disabled(t *testing.T) {
+	versioned := &versionedServer{}
+	versioned.setVersion(`"v1"`, `[{"id": 1, "name": "Book 1"}]`)
+	server := httptest.NewServer(versioned)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithConditionalRequests(false))
+
+	for i := 0; i < 2; i++ {
+		if _, err := repository.GetBooks(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	for _, header := range versioned.headers {
+		if header.Get("If-None-Match") != "" {
+			t.Errorf("Expected no conditional headers when disabled, got %q", header.Get("If-None-Match"))
+		}
+	}
+}
+
+func TestHTTPBooksRepository_Conditional_Paginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		etag := `"page-` + page + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if page == "1" {
+			w.Write([]byte(`[{"id": 1, "name": "Book 1"}, {"id": 2, "name": "Book 2"}]`))
+			return
+		}
+		w.Write([]byte(`[{"id": 3, "name": "Book 3"}]`))
+	}))
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationPageNumber)
+	config.PageSize = 2
+	config.Concurrency = 1
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithPagination(config))
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := repository.GetBooks(ctx)
+	if err != nil || len(books) != 3 {
+		t.Fatalf("Expected 3 books from the revalidated pages, got %v (%v)", books, err)
+	}
+	if info, _ := domain.FetchInfoFromContext(ctx); !info.NotModified {
+		t.Errorf("Expected NotModified when every page returned 304, got %+v", info)
+	}
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// DecodeLimits define los límites aplicados al decodificar la respuesta de la API externa
+// Superarlos produce domain.ErrPayloadTooLarge en lugar de truncar el catálogo
+type DecodeLimits struct {
+	// MaxBytes es el tamaño máximo del cuerpo de la respuesta (0 sin límite)
+	MaxBytes int64
+	// MaxBooks es la cantidad máxima de libros aceptados (0 sin límite)
+	MaxBooks int
+}
+
+// DefaultDecodeLimits devuelve los límites usados por defecto por los constructores del repositorio
+func DefaultDecodeLimits() DecodeLimits {
+	return DecodeLimits{
+		MaxBytes: 32 << 20, // 32 MB
+		MaxBooks: 100_000,
+	}
+}
+
+// WithDecodeLimits reemplaza los límites de decodificación por defecto
+func WithDecodeLimits(limits DecodeLimits) Option {
+	return func(r *HTTPBooksRepository) {
+		r.decodeLimits = limits
+	}
+}
+
+// decodeBooks decodifica un arreglo JSON de libros de a uno, sin cargar el cuerpo completo en memoria
+// Con un mapeo, cada elemento se convierte según sus selectores en lugar del esquema de domain.Book
+func decodeBooks(ctx context.Context, body io.Reader, limits DecodeLimits, mapping *Mapping) ([]domain.Book, error) {
+	reader := &limitedBodyReader{r: body, remaining: limits.MaxBytes, limited: limits.MaxBytes > 0}
+	decoder := json.NewDecoder(reader)
+
+	books := []domain.Book{}
+	err := streamBooks(decoder, mapping, func(book domain.Book) error {
+		if limits.MaxBooks > 0 && len(books) >= limits.MaxBooks {
+			return fmt.Errorf("%w: more than %d books", domain.ErrPayloadTooLarge, limits.MaxBooks)
+		}
+		books = append(books, book)
+		return nil
+	})
+
+	switch {
+	case err == nil:
+		return books, nil
+	case errors.Is(err, domain.ErrPayloadTooLarge):
+		return nil, err
+	case reader.readErr != nil:
+		// El error proviene de la lectura del cuerpo (timeout, conexión cortada), no del JSON
+		return nil, classifyRequestError(ctx, reader.readErr)
+	default:
+		return nil, fmt.Errorf("%w: %w", domain.ErrMalformedPayload, err)
+	}
+}
+
+// streamBooks recorre el arreglo JSON token a token e invoca yield por cada libro
+// Un cuerpo "null" se interpreta como un catálogo vacío
+func streamBooks(decoder *json.Decoder, mapping *Mapping, yield func(book domain.Book) error) error {
+	if mapping != nil {
+		decoder.UseNumber()
+		if err := seekRoot(decoder, mapping.root); err != nil {
+			return err
+		}
+	}
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return finishDocument(decoder, mapping)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array, got %v", token)
+	}
+
+	for index := 0; decoder.More(); index++ {
+		book, err := decodeBook(decoder, mapping)
+		if err != nil {
+			return fmt.Errorf("book at index %d: %w", index, err)
+		}
+		if err := yield(book); err != nil {
+			return err
+		}
+	}
+
+	// Consumir el ']' de cierre
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	return finishDocument(decoder, mapping)
+}
+
+// decodeBook decodifica el siguiente elemento del arreglo, aplicando el mapeo si lo hay
+func decodeBook(decoder *json.Decoder, mapping *Mapping) (domain.Book, error) {
+	var book domain.Book
+	if mapping == nil {
+		err := decoder.Decode(&book)
+		return book, err
+	}
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return book, err
+	}
+	return mapping.book(value)
+}
+
+// seekRoot avanza el decoder hasta el valor indicado por la ruta raíz del mapeo
+// Sólo se admiten claves de objeto: los elementos anteriores se descartan sin conservarlos
+func seekRoot(decoder *json.Decoder, root []pathSegment) error {
+	for _, segment := range root {
+		if segment.isIndex {
+			return errors.New("array indexes are not supported in the root path")
+		}
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '{' {
+			return fmt.Errorf("expected a JSON object containing %q, got %v", segment.key, token)
+		}
+		for {
+			if !decoder.More() {
+				return fmt.Errorf("root key %q not found", segment.key)
+			}
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if key == segment.key {
+				break
+			}
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finishDocument verifica el final del documento; con una ruta raíz el resto del objeto
+// envolvente se ignora
+func finishDocument(decoder *json.Decoder, mapping *Mapping) error {
+	if mapping != nil && len(mapping.root) > 0 {
+		return nil
+	}
+	return expectEOF(decoder)
+}
+
+// expectEOF verifica que no haya datos después del valor JSON principal
+func expectEOF(decoder *json.Decoder) error {
+	if _, err := decoder.Token(); err != io.EOF {
+		if err == nil {
+			return errors.New("unexpected data after JSON value")
+		}
+		return err
+	}
+	return nil
+}
+
+// limitedBodyReader falla con domain.ErrPayloadTooLarge al superar el límite de bytes
+// y recuerda los errores de lectura para distinguirlos de los errores de sintaxis
+type limitedBodyReader struct {
+	r         io.Reader
+	remaining int64
+	limited   bool
+	readErr   error
+}
+
+func (l *limitedBodyReader) Read(p []byte) (int, error) {
+	if l.limited {
+		if l.remaining < 0 {
+			return 0, fmt.Errorf("%w: body exceeds limit", domain.ErrPayloadTooLarge)
+		}
+		// Leer un byte más que el límite para detectar el exceso
+		if int64(len(p)) > l.remaining+1 {
+			p = p[:l.remaining+1]
+		}
+	}
+
+	n, err := l.r.Read(p)
+	if l.limited {
+		l.remaining -= int64(n)
+		if l.remaining < 0 {
+			return n, fmt.Errorf("%w: body exceeds limit", domain.ErrPayloadTooLarge)
+		}
+	}
+	if err != nil && err != io.EOF {
+		l.readErr = err
+	}
+	return n, err
+}
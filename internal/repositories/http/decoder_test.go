@@ -0,0 +1,145 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// catalogJSON genera un catálogo JSON con n libros
+func catalogJSON(n int) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for i := 1; i <= n; i++ {
+		if i > 1 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `{"id": %d, "name": "Book %d", "author": "Author", "units_sold": 100, "price": 10}`, i, i)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+func TestDecodeBooks_LargeCatalog(t *testing.T) {
+	// Un catálogo de más de 1 MB debe decodificarse completo
+	body := catalogJSON(20000)
+	if len(body) <= 1<<20 {
+		t.Fatalf("Test catalog should exceed 1 MB, got %d bytes", len(body))
+	}
+
+	books, err := decodeBooks(context.Background(), strings.NewReader(body), DefaultDecodeLimits(), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 20000 {
+		t.Errorf("Expected 20000 books, got %d", len(books))
+	}
+	if books[19999].ID != 20000 {
+		t.Errorf("Expected last book ID 20000, got %d", books[19999].ID)
+	}
+}
+
+func TestDecodeBooks_Limits(t *testing.T) {
+	body := catalogJSON(10)
+
+	tests := []struct {
+		name   string
+		limits DecodeLimits
+		err    error
+	}{
+		{"no limits", DecodeLimits{}, nil},
+		{"exact book count", DecodeLimits{MaxBooks: 10}, nil},
+		{"exact byte count", DecodeLimits{MaxBytes: int64(len(body))}, nil},
+		{"too many books", DecodeLimits{MaxBooks: 9}, domain.ErrPayloadTooLarge},
+		{"too many bytes", DecodeLimits{MaxBytes: int64(len(body)) - 1}, domain.ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books, err := decodeBooks(context.Background(), strings.NewReader(body), tt.limits, nil)
+			if tt.err == nil {
+				if err != nil || len(books) != 10 {
+					t.Errorf("Expected 10 books and no error, got %d books and %v", len(books), err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Expected %v, got %v", tt.err, err)
+			}
+			if errors.Is(err, domain.ErrMalformedPayload) {
+				t.Errorf("Oversize error should be distinct from a malformed payload, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeBooks_Malformed(t *testing.T) {
+	tests := map[string]string{
+		"not json":       `not a valid json`,
+		"object":         `{"id": 1}`,
+		"truncated":      `[{"id": 1, "name": "Book"`,
+		"trailing data":  `[] []`,
+		"wrong type":     `[{"id": "one"}]`,
+		"empty response": ``,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodeBooks(context.Background(), strings.NewReader(body), DefaultDecodeLimits(), nil)
+			if !errors.Is(err, domain.ErrMalformedPayload) {
+				t.Errorf("Expected ErrMalformedPayload, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeBooks_Null(t *testing.T) {
+	books, err := decodeBooks(context.Background(), strings.NewReader(`null`), DefaultDecodeLimits(), nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if books == nil || len(books) != 0 {
+		t.Errorf("Expected an empty, non-nil slice, got %#v", books)
+	}
+}
+
+func TestDecodeBooks_ReadErrorIsNotMalformed(t *testing.T) {
+	body := io.MultiReader(strings.NewReader(`[{"id": 1,`), iotest.ErrReader(errors.New("connection reset by peer")))
+
+	_, err := decodeBooks(context.Background(), body, DefaultDecodeLimits(), nil)
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable for a read error, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_GetBooks_PayloadTooLarge(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(catalogJSON(5)))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(fastRetryPolicy(3)),
+		WithDecodeLimits(DecodeLimits{MaxBooks: 2}),
+	)
+
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, domain.ErrPayloadTooLarge) {
+		t.Fatalf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+	// Un catálogo demasiado grande no es un fallo transitorio
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls.Load())
+	}
+}
@@ -0,0 +1,315 @@
+// Package fixture implementa un http.RoundTripper que graba las interacciones con un servidor real
+// en un archivo y luego las reproduce, para que las pruebas no dependan de la red
+package fixture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Mode indica si el transporte graba o reproduce
+type Mode int
+
+const (
+	// ModeReplay responde sólo con las interacciones del archivo; una petición sin grabar falla
+	ModeReplay Mode = iota
+	// ModeRecord envía las peticiones al servidor real y reemplaza el archivo con lo grabado
+	ModeRecord
+	// ModeReplayOrRecord reproduce si el archivo existe y graba si no
+	ModeReplayOrRecord
+)
+
+// ParseMode interpreta el nombre de un modo ("replay", "record" o "auto")
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "auto":
+		return ModeReplayOrRecord, nil
+	default:
+		return ModeReplay, fmt.Errorf("unknown fixture mode %q", name)
+	}
+}
+
+// EnvMode es la variable de entorno con la que las pruebas eligen el modo, por ejemplo
+// BOOKS_FIXTURES_MODE=record para volver a grabar contra el servidor real
+const EnvMode = "BOOKS_FIXTURES_MODE"
+
+// ModeFromEnv lee el modo de EnvMode; sin valor se reproduce
+func ModeFromEnv() (Mode, error) {
+	return ParseMode(os.Getenv(EnvMode))
+}
+
+// ErrNoInteraction indica que no hay ninguna interacción grabada que coincida con la petición
+var ErrNoInteraction = errors.New("no recorded interaction matches the request")
+
+// Config define el archivo, el modo y las reglas de coincidencia y redacción
+type Config struct {
+	Path string
+	Mode Mode
+	// Transport es el transporte real usado al grabar (http.DefaultTransport si es nil)
+	Transport http.RoundTripper
+	// Matcher decide qué interacción grabada corresponde a cada petición (DefaultMatcher si es nil)
+	Matcher *Matcher
+	// Redaction indica qué datos sensibles se reemplazan antes de guardar (DefaultRedaction si es nil)
+	Redaction *Redaction
+	// ReplayLatency hace que cada respuesta reproducida tarde lo mismo que al grabarse,
+	// útil para probar timeouts
+	ReplayLatency bool
+}
+
+// Cassette es el contenido de un archivo de fixtures
+type Cassette struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction es un par petición/respuesta grabado
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+	// DurationMS es lo que tardó el servidor en responder al grabar
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// RecordedRequest es una petición grabada (ya redactada)
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse es una respuesta grabada (ya redactada)
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body es un cuerpo grabado; se guarda como texto si es UTF-8 válido y en base64 si no
+type Body []byte
+
+// MarshalJSON implementa json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implementa json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// Transport es el http.RoundTripper que graba o reproduce
+type Transport struct {
+	config    Config
+	recording bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	// used cuenta cuántas veces se reprodujo cada interacción
+	used []int
+}
+
+// New crea el transporte; en modo reproducción lee el archivo indicado
+func New(config Config) (*Transport, error) {
+	if config.Transport == nil {
+		config.Transport = http.DefaultTransport
+	}
+	if config.Matcher == nil {
+		matcher := DefaultMatcher()
+		config.Matcher = &matcher
+	}
+	if config.Redaction == nil {
+		redaction := DefaultRedaction()
+		config.Redaction = &redaction
+	}
+
+	t := &Transport{config: config, cassette: Cassette{Version: 1}}
+	switch config.Mode {
+	case ModeRecord:
+		t.recording = true
+	case ModeReplayOrRecord:
+		if _, err := os.Stat(config.Path); errors.Is(err, os.ErrNotExist) {
+			t.recording = true
+		}
+	}
+	if t.recording {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.cassette); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", config.Path, err)
+	}
+	t.used = make([]int, len(t.cassette.Interactions))
+	return t, nil
+}
+
+// Recording indica si el transporte está grabando
+func (t *Transport) Recording() bool {
+	return t.recording
+}
+
+// Client devuelve un cliente HTTP que usa este transporte
+func (t *Transport) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: t, Timeout: timeout}
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	recorded := t.config.Redaction.request(req, requestBody)
+
+	if t.recording {
+		return t.record(req, recorded)
+	}
+	return t.replay(req, recorded)
+}
+
+func (t *Transport) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	started := time.Now()
+	resp, err := t.config.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request:    recorded,
+		Response:   t.config.Redaction.response(resp, body),
+		DurationMS: time.Since(started).Milliseconds(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	if err := t.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	interaction, err := t.next(recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.config.ReplayLatency && interaction.DurationMS > 0 {
+		timer := time.NewTimer(time.Duration(interaction.DurationMS) * time.Millisecond)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	body := []byte(interaction.Response.Body)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+		StatusCode:    interaction.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// next devuelve la primera interacción coincidente que no se haya reproducido todavía; si todas
+// se usaron, se repite la última coincidente para que las peticiones repetidas sigan funcionando
+func (t *Transport) next(recorded RecordedRequest) (Interaction, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	last := -1
+	for i, interaction := range t.cassette.Interactions {
+		if !t.config.Matcher.matches(interaction.Request, recorded) {
+			continue
+		}
+		last = i
+		if t.used[i] == 0 {
+			break
+		}
+	}
+	if last < 0 {
+		return Interaction{}, fmt.Errorf("%w: %s %s (fixture %s)", ErrNoInteraction, recorded.Method, recorded.URL, t.config.Path)
+	}
+	t.used[last]++
+	return t.cassette.Interactions[last], nil
+}
+
+// save escribe el archivo de forma atómica; se llama con el lock tomado
+func (t *Transport) save() error {
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.config.Path), 0o755); err != nil {
+		return fmt.Errorf("creating fixture directory: %w", err)
+	}
+	tmp := t.config.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing fixture: %w", err)
+	}
+	return os.Rename(tmp, t.config.Path)
+}
+
+// readBody lee el cuerpo completo y lo reemplaza por una copia para que pueda volver a leerse
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
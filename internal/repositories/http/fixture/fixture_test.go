@@ -0,0 +1,226 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransport_RecordThenReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id":1,"name":"Book","author":"Author","units_sold":10,"price":20}]`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "books.json")
+	recorder, err := New(Config{Path: path, Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	recorded := get(t, recorder.Client(time.Second), server.URL+"/books?page=1")
+	if calls != 1 {
+		t.Errorf("Expected 1 upstream call while recording, got %d", calls)
+	}
+
+	// Al reproducir no se consulta al servidor y la respuesta es la misma
+	server.Close()
+	player, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if player.Recording() {
+		t.Errorf("Expected replay mode")
+	}
+	if replayed := get(t, player.Client(time.Second), server.URL+"/books?page=1"); replayed != recorded {
+		t.Errorf("Expected replayed body %q, got %q", recorded, replayed)
+	}
+	// Las peticiones repetidas reutilizan la última interacción coincidente
+	if replayed := get(t, player.Client(time.Second), server.URL+"/books?page=1"); replayed != recorded {
+		t.Errorf("Expected repeated body %q, got %q", recorded, replayed)
+	}
+}
+
+func TestTransport_ReplayInOrder(t *testing.T) {
+	path := writeCassette(t, `{"version":1,"interactions":[
+		{"request":{"method":"GET","url":"http://upstream/books"},"response":{"status":200,"body":"first"}},
+		{"request":{"method":"GET","url":"http://upstream/books"},"response":{"status":200,"body":"second"}}
+	]}`)
+	player, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client := player.Client(time.Second)
+	for _, expected := range []string{"first", "second", "second"} {
+		if body := get(t, client, "http://upstream/books"); body != expected {
+			t.Errorf("Expected %q, got %q", expected, body)
+		}
+	}
+}
+
+func TestTransport_NoInteraction(t *testing.T) {
+	path := writeCassette(t, `{"version":1,"interactions":[
+		{"request":{"method":"GET","url":"http://upstream/books?page=1"},"response":{"status":200,"body":"[]"}}
+	]}`)
+	player, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = player.Client(time.Second).Get("http://upstream/books?page=2")
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("Expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestMatcher_QueryParams(t *testing.T) {
+	matcher := Matcher{IgnoreQueryParams: []string{"ts"}}
+	recorded := RecordedRequest{Method: http.MethodGet, URL: "http://upstream/books?b=2&a=1&ts=1"}
+
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"http://upstream/books?a=1&b=2&ts=99", true},
+		{"http://upstream/books?a=1&b=2", true},
+		{"http://upstream/books?a=1", false},
+		{"http://other/books?a=1&b=2", false},
+	}
+	for _, tt := range tests {
+		incoming := RecordedRequest{Method: http.MethodGet, URL: tt.url}
+		if got := matcher.matches(recorded, incoming); got != tt.expected {
+			t.Errorf("matches(%q) = %v, expected %v", tt.url, got, tt.expected)
+		}
+	}
+}
+
+func TestTransport_RedactsSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret-cookie")
+		w.Write([]byte(`{"access_token":"secret-token","expires_in":60}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "auth.json")
+	recorder, err := New(Config{Path: path, Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/token?api_key=secret-key", strings.NewReader("grant_type=client_credentials&client_secret=secret-client"))
+	req.Header.Set("Authorization", "Bearer secret-bearer")
+	resp, err := recorder.Client(time.Second).Do(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// El cliente recibe la respuesta original; sólo el archivo se redacta
+	if !strings.Contains(string(body), "secret-token") {
+		t.Errorf("Expected the live response to be untouched, got %s", body)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, secret := range []string{"secret-token", "secret-cookie", "secret-key", "secret-client", "secret-bearer"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("Expected %q to be redacted from the fixture:\n%s", secret, data)
+		}
+	}
+	if !strings.Contains(string(data), "grant_type=client_credentials") {
+		t.Errorf("Expected non-secret data to be kept:\n%s", data)
+	}
+
+	// La petición se redacta igual al reproducir, así que coincide aunque el secreto cambie
+	player, err := New(Config{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	req, _ = http.NewRequest(http.MethodPost, server.URL+"/token?api_key=other-key", strings.NewReader("grant_type=client_credentials&client_secret=other-client"))
+	if _, err := player.Client(time.Second).Do(req); err != nil {
+		t.Errorf("Expected the redacted request to match, got %v", err)
+	}
+}
+
+func TestTransport_ReplayLatency(t *testing.T) {
+	path := writeCassette(t, `{"version":1,"interactions":[
+		{"request":{"method":"GET","url":"http://upstream/books"},"response":{"status":200,"body":"[]"},"duration_ms":200}
+	]}`)
+	player, err := New(Config{Path: path, ReplayLatency: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://upstream/books", nil)
+	started := time.Now()
+	_, err = player.Client(0).Do(req)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected the replay to stop at the deadline, took %v", elapsed)
+	}
+}
+
+func TestBody_JSON(t *testing.T) {
+	for _, body := range []Body{Body("texto"), Body{0xff, 0x00, 0xfe}} {
+		data, err := body.MarshalJSON()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var decoded Body
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if string(decoded) != string(body) {
+			t.Errorf("Expected %v after round trip, got %v", body, decoded)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for name, expected := range map[string]Mode{"": ModeReplay, "replay": ModeReplay, "RECORD": ModeRecord, "auto": ModeReplayOrRecord} {
+		mode, err := ParseMode(name)
+		if err != nil || mode != expected {
+			t.Errorf("ParseMode(%q) = %v, %v; expected %v", name, mode, err, expected)
+		}
+	}
+	if _, err := ParseMode("live"); err == nil {
+		t.Errorf("Expected an error for an unknown mode")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return string(body)
+}
+
+func writeCassette(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
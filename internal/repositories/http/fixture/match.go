@@ -0,0 +1,165 @@
+package fixture
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+)
+
+// Redacted reemplaza los valores sensibles en los archivos grabados
+const Redacted = "REDACTED"
+
+// Matcher define cuándo una petición coincide con una interacción grabada
+// El método, el esquema, el host y la ruta siempre deben coincidir
+type Matcher struct {
+	// IgnoreQueryParams son parámetros que no se comparan (por ejemplo marcas de tiempo)
+	IgnoreQueryParams []string
+	// Headers son los headers que deben coincidir
+	Headers []string
+	// Body indica si el cuerpo de la petición debe coincidir
+	Body bool
+	// Custom es una condición adicional opcional
+	Custom func(recorded, incoming RecordedRequest) bool
+}
+
+// DefaultMatcher compara método, URL completa, el header Accept y el cuerpo
+func DefaultMatcher() Matcher {
+	return Matcher{Headers: []string{"Accept"}, Body: true}
+}
+
+func (m *Matcher) matches(recorded, incoming RecordedRequest) bool {
+	if recorded.Method != incoming.Method {
+		return false
+	}
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	incomingURL, err := url.Parse(incoming.URL)
+	if err != nil {
+		return false
+	}
+	if recordedURL.Scheme != incomingURL.Scheme || recordedURL.Host != incomingURL.Host || recordedURL.Path != incomingURL.Path {
+		return false
+	}
+	if m.query(recordedURL) != m.query(incomingURL) {
+		return false
+	}
+	for _, name := range m.Headers {
+		if !slices.Equal(recorded.Headers.Values(name), incoming.Headers.Values(name)) {
+			return false
+		}
+	}
+	if m.Body && !bytes.Equal(recorded.Body, incoming.Body) {
+		return false
+	}
+	return m.Custom == nil || m.Custom(recorded, incoming)
+}
+
+// query devuelve los parámetros comparables en forma canónica (ordenados por nombre)
+func (m *Matcher) query(u *url.URL) string {
+	values := u.Query()
+	for _, name := range m.IgnoreQueryParams {
+		values.Del(name)
+	}
+	return values.Encode()
+}
+
+// Redaction define qué datos sensibles se reemplazan por Redacted antes de guardar o comparar
+type Redaction struct {
+	// Headers son headers de petición y respuesta cuyo valor se reemplaza
+	Headers []string
+	// QueryParams son parámetros de la URL cuyo valor se reemplaza
+	QueryParams []string
+	// BodyPatterns se aplican a los cuerpos; se reemplaza el primer grupo capturado o, si no hay grupos,
+	// la coincidencia completa
+	BodyPatterns []*regexp.Regexp
+}
+
+// DefaultRedaction cubre las credenciales habituales en headers, parámetros y cuerpos JSON o de formulario
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Headers:     []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		QueryParams: []string{"api_key", "apikey", "access_token", "token", "client_secret"},
+		BodyPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`"(?:access_token|refresh_token|id_token|client_secret|password)"\s*:\s*"([^"]*)"`),
+			regexp.MustCompile(`(?:^|&)(?:client_secret|password|refresh_token)=([^&]*)`),
+		},
+	}
+}
+
+func (r *Redaction) request(req *http.Request, body []byte) RecordedRequest {
+	u := *req.URL
+	values := u.Query()
+	changed := false
+	for _, name := range r.QueryParams {
+		if values.Has(name) {
+			values.Set(name, Redacted)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = values.Encode()
+	}
+	return RecordedRequest{
+		Method:  req.Method,
+		URL:     u.String(),
+		Headers: r.headers(req.Header),
+		Body:    r.body(body),
+	}
+}
+
+func (r *Redaction) response(resp *http.Response, body []byte) RecordedResponse {
+	return RecordedResponse{
+		Status:  resp.StatusCode,
+		Headers: r.headers(resp.Header),
+		Body:    r.body(body),
+	}
+}
+
+func (r *Redaction) headers(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := header.Clone()
+	for _, name := range r.Headers {
+		if values := redacted.Values(name); len(values) > 0 {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+	return redacted
+}
+
+func (r *Redaction) body(body []byte) Body {
+	if len(body) == 0 {
+		return nil
+	}
+	redacted := slices.Clone(body)
+	for _, pattern := range r.BodyPatterns {
+		redacted = replaceSecrets(pattern, redacted)
+	}
+	return redacted
+}
+
+// replaceSecrets reemplaza el primer grupo de cada coincidencia (o la coincidencia completa)
+func replaceSecrets(pattern *regexp.Regexp, body []byte) []byte {
+	var out []byte
+	last := 0
+	for _, match := range pattern.FindAllSubmatchIndex(body, -1) {
+		start, end := match[0], match[1]
+		if len(match) >= 4 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		out = append(out, body[last:start]...)
+		out = append(out, Redacted...)
+		last = end
+	}
+	if out == nil {
+		return body
+	}
+	return append(out, body[last:]...)
+}
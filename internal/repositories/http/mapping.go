@@ -0,0 +1,374 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Coercion indica cómo convertir el valor del catálogo externo al tipo del campo de domain.Book
+type Coercion string
+
+const (
+	// CoerceNone exige que el valor ya tenga el tipo del campo (número entero o string)
+	CoerceNone Coercion = ""
+	// CoerceNumber acepta números y strings numéricos, por ejemplo "1200"
+	CoerceNumber Coercion = "number"
+	// CoerceCents convierte un importe en unidades (por ejemplo 12.99 o "12.99") a centavos;
+	// en el precio redondea a los decimales de la moneda
+	CoerceCents Coercion = "cents"
+	// CoerceMinorUnits indica que el precio ya viene en unidades menores de la moneda, por ejemplo 1299
+	CoerceMinorUnits Coercion = "minor"
+	// CoerceString acepta strings y números, que se convierten a su representación textual
+	CoerceString Coercion = "string"
+)
+
+// FieldSpec indica de dónde se lee un campo del libro y cómo se convierte
+type FieldSpec struct {
+	// Path es un selector del estilo "title", "pricing.amount" o "authors[0].name"
+	Path   string   `json:"path"`
+	Coerce Coercion `json:"coerce,omitempty"`
+}
+
+// Mapping describe cómo leer un catálogo cuyo esquema no coincide con el de domain.Book
+// Las claves de Fields son los nombres JSON de domain.Book (id, name, author, units_sold, price) y
+// currency, la moneda del precio cuando éste es un número; los campos no indicados se leen del
+// atributo con el mismo nombre. El precio puede ser un número en unidades de la moneda, un texto
+// como "12.99 USD" (con CoerceNumber) o el objeto {"amount", "currency"} de domain.Money
+type Mapping struct {
+	// Root es la ruta al arreglo de libros dentro de la respuesta (vacío si la respuesta es el arreglo)
+	Root   string               `json:"root,omitempty"`
+	Fields map[string]FieldSpec `json:"fields"`
+
+	root   []pathSegment
+	fields [bookFieldCount]compiledField
+}
+
+// Índices de los campos de domain.Book dentro de Mapping.fields
+const (
+	bookFieldID = iota
+	bookFieldName
+	bookFieldAuthor
+	bookFieldUnitsSold
+	bookFieldPrice
+	bookFieldCurrency
+	bookFieldCount
+)
+
+var bookFieldNames = [bookFieldCount]string{"id", "name", "author", "units_sold", "price", "currency"}
+
+type compiledField struct {
+	path   []pathSegment
+	coerce Coercion
+}
+
+// pathSegment es una clave de objeto o, si isIndex es verdadero, una posición de arreglo
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// ParseMapping lee una configuración de mapeo en JSON y la valida
+func ParseMapping(data []byte) (*Mapping, error) {
+	var mapping Mapping
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&mapping); err != nil {
+		return nil, fmt.Errorf("parsing field mapping: %w", err)
+	}
+	if err := mapping.compile(); err != nil {
+		return nil, err
+	}
+	return &mapping, nil
+}
+
+// LoadMapping lee una configuración de mapeo desde un archivo JSON
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading field mapping: %w", err)
+	}
+	return ParseMapping(data)
+}
+
+// NewMapping crea un mapeo validado a partir de la ruta raíz y las especificaciones de campos
+func NewMapping(root string, fields map[string]FieldSpec) (*Mapping, error) {
+	mapping := &Mapping{Root: root, Fields: fields}
+	if err := mapping.compile(); err != nil {
+		return nil, err
+	}
+	return mapping, nil
+}
+
+// WithMapping hace que el repositorio lea los libros según el mapeo indicado
+// Sin mapeo se espera el esquema de domain.Book
+func WithMapping(mapping *Mapping) Option {
+	return func(r *HTTPBooksRepository) {
+		r.mapping = mapping
+	}
+}
+
+// compile valida el mapeo y precalcula los selectores
+func (m *Mapping) compile() error {
+	root, err := parsePath(m.Root, true)
+	if err != nil {
+		return fmt.Errorf("invalid root %q: %w", m.Root, err)
+	}
+	m.root = root
+
+	for field, name := range bookFieldNames {
+		m.fields[field] = compiledField{path: []pathSegment{{key: name}}}
+	}
+	for name, spec := range m.Fields {
+		field := slices.Index(bookFieldNames[:], name)
+		if field < 0 {
+			return fmt.Errorf("unknown book field %q", name)
+		}
+		path, err := parsePath(spec.Path, false)
+		if err != nil {
+			return fmt.Errorf("invalid path %q for field %q: %w", spec.Path, name, err)
+		}
+		if err := checkCoercion(field, spec.Coerce); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+		m.fields[field] = compiledField{path: path, coerce: spec.Coerce}
+	}
+	return nil
+}
+
+// checkCoercion verifica que la conversión tenga sentido para el tipo del campo
+func checkCoercion(field int, coerce Coercion) error {
+	numeric := field == bookFieldID || field == bookFieldUnitsSold || field == bookFieldPrice
+	switch coerce {
+	case CoerceNone:
+		return nil
+	case CoerceNumber, CoerceCents:
+		if !numeric {
+			return fmt.Errorf("coercion %q only applies to numeric fields", coerce)
+		}
+		return nil
+	case CoerceMinorUnits:
+		if field != bookFieldPrice {
+			return fmt.Errorf("coercion %q only applies to the price", coerce)
+		}
+		return nil
+	case CoerceString:
+		if numeric {
+			return fmt.Errorf("coercion %q only applies to text fields", coerce)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown coercion %q", coerce)
+	}
+}
+
+// parsePath interpreta un selector como "pricing.amount" o "authors[0].name"
+func parsePath(path string, allowEmpty bool) ([]pathSegment, error) {
+	if path == "" {
+		if allowEmpty {
+			return nil, nil
+		}
+		return nil, errors.New("empty path")
+	}
+
+	var segments []pathSegment
+	for _, part := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key != "" {
+			segments = append(segments, pathSegment{key: key})
+		}
+		if rest == "" {
+			if key == "" {
+				return nil, errors.New("empty segment")
+			}
+			continue
+		}
+		// Una o más posiciones de arreglo: "[0]", "[0][1]"
+		for _, index := range strings.Split("["+rest, "[")[1:] {
+			digits, ok := strings.CutSuffix(index, "]")
+			n, err := strconv.Atoi(digits)
+			if !ok || err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index in %q", part)
+			}
+			segments = append(segments, pathSegment{index: n, isIndex: true})
+		}
+	}
+	return segments, nil
+}
+
+// lookup recorre el valor decodificado según el selector; found es falso si falta algún tramo
+func lookup(value any, path []pathSegment) (any, bool) {
+	for _, segment := range path {
+		if segment.isIndex {
+			items, ok := value.([]any)
+			if !ok || segment.index >= len(items) {
+				return nil, false
+			}
+			value = items[segment.index]
+			continue
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[segment.key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// book construye un libro a partir de un elemento del catálogo decodificado con UseNumber
+// Los campos ausentes o nulos quedan con su valor cero para que los trate la validación
+func (m *Mapping) book(value any) (domain.Book, error) {
+	var book domain.Book
+	// El precio se convierte al final porque sus decimales dependen de la moneda
+	var price any
+	var currency domain.Currency
+	for field, spec := range m.fields {
+		raw, found := lookup(value, spec.path)
+		if !found || raw == nil {
+			continue
+		}
+		var err error
+		switch field {
+		case bookFieldID:
+			book.ID, err = coerceUint(raw, spec.coerce)
+		case bookFieldName:
+			book.Name, err = coerceString(raw, spec.coerce)
+		case bookFieldAuthor:
+			book.Author, err = coerceString(raw, spec.coerce)
+		case bookFieldUnitsSold:
+			book.UnitsSold, err = coerceUint(raw, spec.coerce)
+		case bookFieldPrice:
+			price = raw
+		case bookFieldCurrency:
+			var text string
+			if text, err = coerceString(raw, spec.coerce); err == nil {
+				currency, err = domain.ParseCurrency(text)
+			}
+		}
+		if err != nil {
+			return domain.Book{}, fmt.Errorf("field %q: %w", bookFieldNames[field], err)
+		}
+	}
+	if price != nil {
+		var err error
+		if book.Price, err = coercePrice(price, m.fields[bookFieldPrice].coerce, currency); err != nil {
+			return domain.Book{}, fmt.Errorf("field %q: %w", bookFieldNames[bookFieldPrice], err)
+		}
+	}
+	return book, nil
+}
+
+// coercePrice convierte el precio en la moneda indicada (DefaultCurrency si es "")
+// Un objeto o un texto con moneda propia tienen prioridad sobre el campo currency
+func coercePrice(raw any, coerce Coercion, currency domain.Currency) (domain.Money, error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	var text string
+	switch v := raw.(type) {
+	case map[string]any:
+		// El objeto de domain.Money, con el importe ya en unidades menores
+		data, err := json.Marshal(v)
+		if err != nil {
+			return domain.Money{}, err
+		}
+		var money domain.Money
+		if err := json.Unmarshal(data, &money); err != nil {
+			return domain.Money{}, err
+		}
+		return money, nil
+	case json.Number:
+		text = v.String()
+	case string:
+		if coerce == CoerceNone || coerce == CoerceMinorUnits {
+			return domain.Money{}, fmt.Errorf("expected a number, got string %q", v)
+		}
+		text = strings.TrimSpace(v)
+	default:
+		return domain.Money{}, fmt.Errorf("expected a number, got %T", raw)
+	}
+
+	switch coerce {
+	case CoerceMinorUnits:
+		amount, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return domain.Money{}, fmt.Errorf("%q is not an integer amount", text)
+		}
+		return domain.NewMoney(amount, currency), nil
+	case CoerceCents:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return domain.Money{}, fmt.Errorf("invalid number %q", text)
+		}
+		f = math.Round(f * math.Pow10(currency.Exponent()))
+		if math.Abs(f) > 1<<53 {
+			return domain.Money{}, fmt.Errorf("%q is out of range", text)
+		}
+		return domain.NewMoney(int64(f), currency), nil
+	}
+	if len(strings.Fields(text)) == 1 {
+		text += " " + string(currency)
+	}
+	return domain.ParseMoney(text)
+}
+
+func coerceUint(raw any, coerce Coercion) (uint, error) {
+	var text string
+	switch v := raw.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		if coerce != CoerceNumber && coerce != CoerceCents {
+			return 0, fmt.Errorf("expected a number, got string %q", v)
+		}
+		text = strings.TrimSpace(v)
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", raw)
+	}
+
+	if coerce != CoerceCents {
+		if n, err := strconv.ParseUint(text, 10, 0); err == nil {
+			return uint(n), nil
+		}
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	if coerce == CoerceCents {
+		f = math.Round(f * 100)
+	}
+	if f < 0 || f != math.Trunc(f) || f > math.MaxUint32 {
+		return 0, fmt.Errorf("%q is not a non-negative integer", text)
+	}
+	return uint(f), nil
+}
+
+func coerceString(raw any, coerce Coercion) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		if coerce == CoerceString {
+			return v.String(), nil
+		}
+		return "", fmt.Errorf("expected a string, got number %s", v)
+	case bool:
+		if coerce == CoerceString {
+			return strconv.FormatBool(v), nil
+		}
+	}
+	return "", fmt.Errorf("expected a string, got %T", raw)
+}
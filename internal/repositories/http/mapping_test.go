@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+const partnerMapping = `{
+	"root": "data.items",
+	"fields": {
+		"id": {"path": "sku", "coerce": "number"},
+		"name": {"path": "title"},
+		"author": {"path": "authors[0].name"},
+		"units_sold": {"path": "sold", "coerce": "number"},
+		"price": {"path": "pricing.amount", "coerce": "cents"}
+	}
+}`
+
+const partnerCatalog = `{
+	"meta": {"page": 1, "tags": ["a", "b"]},
+	"data": {
+		"total": 2,
+		"items": [
+			{"sku": "101", "title": "Partner Book", "authors": [{"name": "Jane Doe"}], "sold": "1500", "pricing": {"amount": 12.99, "currency": "USD"}},
+			{"sku": 102, "title": "Second Book", "authors": [], "sold": 7, "pricing": {"amount": "8.5"}}
+		]
+	},
+	"links": {"next": null}
+}`
+
+func mustParseMapping(t *testing.T, config string) *Mapping {
+	t.Helper()
+	mapping, err := ParseMapping([]byte(config))
+	if err != nil {
+		t.Fatalf("Expected valid mapping, got %v", err)
+	}
+	return mapping
+}
+
+func TestDecodeBooks_WithMapping(t *testing.T) {
+	mapping := mustParseMapping(t, partnerMapping)
+
+	books, err := decodeBooks(context.Background(), strings.NewReader(partnerCatalog), DefaultDecodeLimits(), mapping)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []domain.Book{
+		{ID: 101, Name: "Partner Book", Author: "Jane Doe", UnitsSold: 1500, Price: domain.NewMoney(1299, "USD")},
+		// Un autor ausente queda vacío para que lo trate la validación
+		{ID: 102, Name: "Second Book", Author: "", UnitsSold: 7, Price: domain.NewMoney(850, "USD")},
+	}
+	if len(books) != len(expected) {
+		t.Fatalf("Expected %d books, got %d", len(expected), len(books))
+	}
+	for i := range expected {
+		if books[i] != expected[i] {
+			t.Errorf("Book %d: expected %+v, got %+v", i, expected[i], books[i])
+		}
+	}
+}
+
+func TestDecodeBooks_MappingDefaultsToBookFieldNames(t *testing.T) {
+	// Sólo se renombra el título; el resto de los campos usa los nombres de domain.Book
+	mapping, err := NewMapping("", map[string]FieldSpec{"name": {Path: "title"}})
+	if err != nil {
+		t.Fatalf("Expected valid mapping, got %v", err)
+	}
+
+	body := `[{"id": 1, "title": "Book", "author": "Author", "units_sold": 10, "price": 20}]`
+	books, err := decodeBooks(context.Background(), strings.NewReader(body), DefaultDecodeLimits(), mapping)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := domain.Book{ID: 1, Name: "Book", Author: "Author", UnitsSold: 10, Price: domain.Price(20)}
+	if len(books) != 1 || books[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, books)
+	}
+}
+
+func TestDecodeBooks_MappingPriceCurrency(t *testing.T) {
+	mapping := mustParseMapping(t, `{
+		"fields": {
+			"price": {"path": "price.value", "coerce": "minor"},
+			"currency": {"path": "price.currency"}
+		}
+	}`)
+
+	body := `[
+		{"id": 1, "price": {"value": 1500, "currency": "jpy"}},
+		{"id": 2, "price": {"value": 1299}}
+	]`
+	books, err := decodeBooks(context.Background(), strings.NewReader(body), DefaultDecodeLimits(), mapping)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 2 || books[0].Price != domain.NewMoney(1500, "JPY") || books[1].Price != domain.NewMoney(1299, "USD") {
+		t.Errorf("Unexpected prices %+v", books)
+	}
+
+	// Un precio con decimales no está en unidades menores y un texto requiere la coerción number o cents
+	for _, invalid := range []string{`[{"price": {"value": 12.99}}]`, `[{"price": {"value": "1299"}}]`} {
+		_, err = decodeBooks(context.Background(), strings.NewReader(invalid), DefaultDecodeLimits(), mapping)
+		if !errors.Is(err, domain.ErrMalformedPayload) {
+			t.Errorf("Expected ErrMalformedPayload for %s, got %v", invalid, err)
+		}
+	}
+}
+
+func TestDecodeBooks_MappingCoercionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"string without coercion", `[{"id": "1"}]`},
+		{"fractional number", `[{"id": 1.5}]`},
+		{"negative number", `[{"units_sold": -3}]`},
+		{"number as name", `[{"name": 42}]`},
+		{"invalid numeric string", `{"data": {"items": [{"sku": "abc"}]}}`},
+		{"missing root", `{"data": {}}`},
+	}
+
+	plain := mustParseMapping(t, `{"fields": {}}`)
+	partner := mustParseMapping(t, partnerMapping)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := plain
+			if strings.HasPrefix(tt.body, "{") {
+				mapping = partner
+			}
+			_, err := decodeBooks(context.Background(), strings.NewReader(tt.body), DefaultDecodeLimits(), mapping)
+			if !errors.Is(err, domain.ErrMalformedPayload) {
+				t.Errorf("Expected ErrMalformedPayload, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseMapping_Invalid(t *testing.T) {
+	configs := map[string]string{
+		"unknown field":         `{"fields": {"isbn": {"path": "isbn"}}}`,
+		"unknown coercion":      `{"fields": {"price": {"path": "price", "coerce": "euros"}}}`,
+		"cents on text field":   `{"fields": {"name": {"path": "title", "coerce": "cents"}}}`,
+		"minor on units sold":   `{"fields": {"units_sold": {"path": "sold", "coerce": "minor"}}}`,
+		"string on numeric":     `{"fields": {"id": {"path": "id", "coerce": "string"}}}`,
+		"empty path":            `{"fields": {"name": {"path": ""}}}`,
+		"bad index":             `{"fields": {"author": {"path": "authors[x].name"}}}`,
+		"unknown config option": `{"fields": {}, "strict": true}`,
+	}
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseMapping([]byte(config)); err == nil {
+				t.Errorf("Expected an error for %s", config)
+			}
+		})
+	}
+}
+
+func TestHTTPBooksRepository_GetBooks_WithMappingFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(path, []byte(partnerMapping), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mapping, err := LoadMapping(path)
+	if err != nil {
+		t.Fatalf("Expected mapping to load, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(partnerCatalog))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithMapping(mapping))
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 2 || books[0].Price != domain.NewMoney(1299, "USD") || books[1].ID != 102 {
+		t.Errorf("Unexpected books %+v", books)
+	}
+}
@@ -0,0 +1,330 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// PaginationStrategy define cómo se recorren las páginas de la API externa
+type PaginationStrategy int
+
+const (
+	// PaginationNone realiza una única petición y asume que devuelve el catálogo completo
+	PaginationNone PaginationStrategy = iota
+	// PaginationPageNumber pide páginas numeradas (?page=N&limit=M), como mockapi
+	PaginationPageNumber
+	// PaginationOffset pide páginas por desplazamiento (?offset=N&limit=M)
+	PaginationOffset
+	// PaginationLinkHeader sigue el enlace rel="next" del header Link (paginación por cursor)
+	PaginationLinkHeader
+)
+
+// PaginationConfig define los parámetros de la paginación
+type PaginationConfig struct {
+	Strategy PaginationStrategy
+	// PageSize es la cantidad de libros pedida por página; una página con menos libros es la última
+	// Con las estrategias numeradas un valor no positivo usa defaultPageSize; con PaginationLinkHeader
+	// omite el parámetro de límite
+	PageSize int
+	// PageParam, OffsetParam y LimitParam son los nombres de los parámetros de la query
+	PageParam   string
+	OffsetParam string
+	LimitParam  string
+	// FirstPage es el número de la primera página para PaginationPageNumber
+	FirstPage int
+	// MaxPages es la cantidad máxima de páginas a recorrer; superarla se trata como un catálogo demasiado grande
+	MaxPages int
+	// Concurrency es la cantidad de páginas pedidas en paralelo (no aplica a PaginationLinkHeader)
+	Concurrency int
+	// AllowPartial devuelve las páginas obtenidas antes de un fallo, marcando la respuesta
+	// como parcial, en lugar de fallar la obtención completa
+	AllowPartial bool
+}
+
+// defaultPageSize es el tamaño de página de DefaultPaginationConfig
+const defaultPageSize = 100
+
+// DefaultPaginationConfig devuelve una configuración compatible con mockapi para la estrategia indicada
+func DefaultPaginationConfig(strategy PaginationStrategy) PaginationConfig {
+	return PaginationConfig{
+		Strategy:    strategy,
+		PageSize:    defaultPageSize,
+		PageParam:   "page",
+		OffsetParam: "offset",
+		LimitParam:  "limit",
+		FirstPage:   1,
+		MaxPages:    1000,
+		Concurrency: 4,
+	}
+}
+
+// WithPagination activa la paginación con la configuración indicada
+func WithPagination(config PaginationConfig) Option {
+	return func(r *HTTPBooksRepository) {
+		r.pagination = config.normalized()
+	}
+}
+
+// normalized completa los valores que no pueden ser cero
+// Sin un tamaño de página la condición de última página (una página incompleta) no se cumpliría
+// nunca y cada obtención recorrería MaxPages páginas
+func (c PaginationConfig) normalized() PaginationConfig {
+	c.Concurrency = max(c.Concurrency, 1)
+	c.MaxPages = max(c.MaxPages, 1)
+	if c.Strategy != PaginationLinkHeader && c.PageSize <= 0 {
+		c.PageSize = defaultPageSize
+	}
+	return c
+}
+
+// fetchSummary describe cómo terminó una obtención paginada
+type fetchSummary struct {
+	// notModified es verdadero si todas las páginas respondieron 304
+	notModified bool
+	// partial es verdadero si se devolvieron sólo las páginas anteriores a un fallo
+	partial bool
+}
+
+// fetchAllPages recorre todas las páginas de baseURL según la estrategia configurada
+func (p *HTTPBooksRepository) fetchAllPages(ctx context.Context, baseURL string) ([]domain.Book, fetchSummary, error) {
+	config := p.pagination.normalized()
+
+	var books []domain.Book
+	var summary fetchSummary
+	var failure error
+	if config.Strategy == PaginationLinkHeader {
+		books, summary.notModified, failure = p.fetchLinkedPages(ctx, config, baseURL)
+	} else {
+		books, summary.notModified, failure = p.fetchNumberedPages(ctx, config, baseURL)
+	}
+
+	if failure != nil {
+		// Sólo se devuelve un catálogo parcial si se obtuvo algo y quien llama sigue esperando
+		if !config.AllowPartial || len(books) == 0 || errors.Is(failure, domain.ErrContextCanceled) {
+			return nil, fetchSummary{}, failure
+		}
+		summary = fetchSummary{partial: true}
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Partial = true
+			info.Warnings = append(info.Warnings, fmt.Sprintf("partial catalog (%d books): %v", len(books), failure))
+		})
+	}
+
+	if limit := p.decodeLimits.MaxBooks; limit > 0 && len(books) > limit {
+		return nil, fetchSummary{}, fmt.Errorf("%w: more than %d books", domain.ErrPayloadTooLarge, limit)
+	}
+	if books == nil {
+		books = []domain.Book{}
+	}
+	return books, summary, nil
+}
+
+// fetchNumberedPages pide páginas numeradas o por desplazamiento en lotes concurrentes
+// y se detiene en la primera página incompleta
+// Devuelve los libros de las páginas anteriores al primer fallo junto con ese fallo
+func (p *HTTPBooksRepository) fetchNumberedPages(ctx context.Context, config PaginationConfig, baseURL string) ([]domain.Book, bool, error) {
+	var books []domain.Book
+	notModified := true
+	for batchStart := 0; batchStart < config.MaxPages; batchStart += config.Concurrency {
+		size := min(config.Concurrency, config.MaxPages-batchStart)
+		results := make([]*fetchResult, size)
+		errs := make([]error, size)
+
+		var wg sync.WaitGroup
+		for i := 0; i < size; i++ {
+			pageURL, err := config.pageURL(baseURL, batchStart+i)
+			if err != nil {
+				return books, false, err
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = p.fetchWithRetry(ctx, pageURL)
+			}(i)
+		}
+		wg.Wait()
+
+		// Procesar en orden: las páginas posteriores a la última o a un fallo se descartan
+		for i := 0; i < size; i++ {
+			if errs[i] != nil {
+				return books, false, fmt.Errorf("page %d: %w", batchStart+i, errs[i])
+			}
+			books = append(books, results[i].books...)
+			notModified = notModified && results[i].notModified
+			if len(results[i].books) < config.PageSize {
+				return books, notModified, nil
+			}
+		}
+	}
+	return books, false, fmt.Errorf("%w: more than %d pages", domain.ErrPayloadTooLarge, config.MaxPages)
+}
+
+// fetchLinkedPages sigue secuencialmente los enlaces rel="next" del header Link
+func (p *HTTPBooksRepository) fetchLinkedPages(ctx context.Context, config PaginationConfig, baseURL string) ([]domain.Book, bool, error) {
+	next, err := config.firstLinkedURL(baseURL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var books []domain.Book
+	notModified := true
+	for page := 0; next != ""; page++ {
+		if page >= config.MaxPages {
+			return books, false, fmt.Errorf("%w: more than %d pages", domain.ErrPayloadTooLarge, config.MaxPages)
+		}
+		result, err := p.fetchWithRetry(ctx, next)
+		if err != nil {
+			return books, false, fmt.Errorf("page %d: %w", page, err)
+		}
+		books = append(books, result.books...)
+		notModified = notModified && result.notModified
+		if next, err = nextLink(result.header, next); err != nil {
+			return books, false, err
+		}
+	}
+	return books, notModified, nil
+}
+
+// pageURL construye la URL de la página con índice index (empezando en 0)
+func (c PaginationConfig) pageURL(apiURL string, index int) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid API URL: %w", domain.ErrUpstreamUnavailable, err)
+	}
+	query := u.Query()
+	switch c.Strategy {
+	case PaginationOffset:
+		query.Set(c.OffsetParam, strconv.Itoa(index*c.PageSize))
+	default:
+		query.Set(c.PageParam, strconv.Itoa(c.FirstPage+index))
+	}
+	if c.LimitParam != "" {
+		query.Set(c.LimitParam, strconv.Itoa(c.PageSize))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// firstLinkedURL construye la URL de la primera página para PaginationLinkHeader
+func (c PaginationConfig) firstLinkedURL(apiURL string) (string, error) {
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid API URL: %w", domain.ErrUpstreamUnavailable, err)
+	}
+	if c.LimitParam != "" && c.PageSize > 0 {
+		query := u.Query()
+		query.Set(c.LimitParam, strconv.Itoa(c.PageSize))
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
+// nextLink devuelve la URL absoluta del enlace rel="next" del header Link, o "" si no hay
+func nextLink(header http.Header, current string) (string, error) {
+	for _, value := range header.Values("Link") {
+		for _, link := range parseLinks(value) {
+			for _, rel := range link.rels {
+				if strings.EqualFold(rel, "next") {
+					return resolveLink(current, link.target)
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// linkValue es un enlace del header Link con los tipos de relación de su parámetro rel
+type linkValue struct {
+	target string
+	rels   []string
+}
+
+// parseLinks interpreta un header Link (RFC 8288)
+// Los enlaces se separan por comas, pero una coma dentro de <...> o de un parámetro entre
+// comillas forma parte del valor, así que no alcanza con dividir el texto
+func parseLinks(value string) []linkValue {
+	var links []linkValue
+	i := 0
+	for i < len(value) {
+		start := strings.IndexByte(value[i:], '<')
+		if start < 0 {
+			break
+		}
+		i += start + 1
+		end := strings.IndexByte(value[i:], '>')
+		if end < 0 {
+			break
+		}
+		link := linkValue{target: value[i : i+end]}
+		i += end + 1
+
+		// Leer los parámetros hasta la coma que separa el próximo enlace
+		for i < len(value) && value[i] != ',' {
+			if value[i] != ';' {
+				i++
+				continue
+			}
+			i++
+			nameEnd := i
+			for nameEnd < len(value) && !strings.ContainsRune("=;,", rune(value[nameEnd])) {
+				nameEnd++
+			}
+			name := strings.TrimSpace(value[i:nameEnd])
+			i = nameEnd
+			if i >= len(value) || value[i] != '=' {
+				continue
+			}
+			var paramValue string
+			paramValue, i = parseLinkParamValue(value, i+1)
+			if strings.EqualFold(name, "rel") {
+				link.rels = strings.Fields(paramValue)
+			}
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+// parseLinkParamValue lee el valor de un parámetro que empieza en i, entre comillas (con escapes)
+// o sin ellas, y devuelve el valor y la posición siguiente
+func parseLinkParamValue(value string, i int) (string, int) {
+	for i < len(value) && value[i] == ' ' {
+		i++
+	}
+	if i < len(value) && value[i] == '"' {
+		var quoted strings.Builder
+		for i++; i < len(value) && value[i] != '"'; i++ {
+			if value[i] == '\\' && i+1 < len(value) {
+				i++
+			}
+			quoted.WriteByte(value[i])
+		}
+		return quoted.String(), i + 1
+	}
+	end := i
+	for end < len(value) && value[end] != ';' && value[end] != ',' {
+		end++
+	}
+	return strings.TrimSpace(value[i:end]), end
+}
+
+// resolveLink resuelve un enlace posiblemente relativo contra la URL actual
+func resolveLink(current, link string) (string, error) {
+	base, err := url.Parse(current)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid page URL: %w", domain.ErrMalformedPayload, err)
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid Link header: %w", domain.ErrMalformedPayload, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
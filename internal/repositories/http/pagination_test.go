@@ -0,0 +1,242 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// paginatedServer sirve un catálogo de total libros paginado por page/limit u offset/limit
+// failPage indica una página (empezando en 1) que responde 500, o 0 para ninguna
+func paginatedServer(t *testing.T, total int, failPage int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		query := r.URL.Query()
+		limit, err := strconv.Atoi(query.Get("limit"))
+		if err != nil {
+			t.Errorf("Expected a limit parameter, got %q", query.Get("limit"))
+		}
+
+		var offset int
+		if query.Has("offset") {
+			offset, _ = strconv.Atoi(query.Get("offset"))
+		} else {
+			page, _ := strconv.Atoi(query.Get("page"))
+			offset = (page - 1) * limit
+		}
+		if failPage > 0 && offset/limit+1 == failPage {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		books := []domain.Book{}
+		for id := offset + 1; id <= min(offset+limit, total); id++ {
+			books = append(books, domain.Book{ID: uint(id), Name: fmt.Sprintf("Book %d", id), Author: "Author", Price: domain.Price(10)})
+		}
+		json.NewEncoder(w).Encode(books)
+	}))
+}
+
+func assertSequentialIDs(t *testing.T, books []domain.Book, expected int) {
+	t.Helper()
+	if len(books) != expected {
+		t.Fatalf("Expected %d books, got %d", expected, len(books))
+	}
+	for i, book := range books {
+		if book.ID != uint(i+1) {
+			t.Fatalf("Expected book %d to have ID %d, got %d", i, i+1, book.ID)
+		}
+	}
+}
+
+func TestHTTPBooksRepository_Pagination_PageNumber(t *testing.T) {
+	var requests atomic.Int32
+	server := paginatedServer(t, 250, 0, &requests)
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationPageNumber)
+	config.Concurrency = 2
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithPagination(config))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertSequentialIDs(t, books, 250)
+
+	// 3 páginas necesarias más la cuarta pedida en el mismo lote
+	if requests.Load() != 4 {
+		t.Errorf("Expected 4 page requests, got %d", requests.Load())
+	}
+}
+
+func TestHTTPBooksRepository_Pagination_Offset(t *testing.T) {
+	var requests atomic.Int32
+	server := paginatedServer(t, 200, 0, &requests)
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationOffset)
+	config.Concurrency = 1
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithPagination(config))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertSequentialIDs(t, books, 200)
+
+	// Un catálogo múltiplo del tamaño de página termina con una página vacía
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests.Load())
+	}
+}
+
+func TestHTTPBooksRepository_Pagination_LinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		switch cursor {
+		case "":
+			// Enlace absoluto, con otros rel en el mismo header
+			w.Header().Set("Link", fmt.Sprintf(`<%s/books?cursor=abc>; rel="next", <%s/books>; rel="first"`, server.URL, server.URL))
+			w.Write([]byte(`[{"id": 1, "name": "Book 1"}, {"id": 2, "name": "Book 2"}]`))
+		case "abc":
+			// Enlace relativo
+			w.Header().Set("Link", `</books?cursor=def>; rel="next"`)
+			w.Write([]byte(`[{"id": 3, "name": "Book 3"}]`))
+		case "def":
+			w.Write([]byte(`[{"id": 4, "name": "Book 4"}]`))
+		default:
+			t.Errorf("Unexpected cursor %q", cursor)
+		}
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL+"/books",
+		WithPagination(DefaultPaginationConfig(PaginationLinkHeader)))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertSequentialIDs(t, books, 4)
+}
+
+func TestHTTPBooksRepository_Pagination_PartialFailure(t *testing.T) {
+	var requests atomic.Int32
+	server := paginatedServer(t, 500, 3, &requests)
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationPageNumber)
+	config.Concurrency = 1
+
+	t.Run("fail whole fetch", func(t *testing.T) {
+		repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+			WithPagination(config), WithRetryPolicy(NoRetryPolicy()))
+
+		_, err := repository.GetBooks(context.Background())
+		if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+			t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+		}
+	})
+
+	t.Run("allow partial", func(t *testing.T) {
+		partialConfig := config
+		partialConfig.AllowPartial = true
+		repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+			WithPagination(partialConfig), WithRetryPolicy(NoRetryPolicy()))
+
+		ctx := domain.WithFetchInfo(context.Background())
+		books, err := repository.GetBooks(ctx)
+		if err != nil {
+			t.Fatalf("Expected partial data without error, got %v", err)
+		}
+		assertSequentialIDs(t, books, 200)
+
+		info, _ := domain.FetchInfoFromContext(ctx)
+		if !info.Partial || len(info.Warnings) != 1 {
+			t.Errorf("Expected a partial result with one warning, got %+v", info)
+		}
+	})
+}
+
+func TestHTTPBooksRepository_Pagination_MaxPages(t *testing.T) {
+	var requests atomic.Int32
+	server := paginatedServer(t, 1000, 0, &requests)
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationPageNumber)
+	config.MaxPages = 3
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithPagination(config))
+
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, domain.ErrPayloadTooLarge) {
+		t.Errorf("Expected ErrPayloadTooLarge, got %v", err)
+	}
+	if requests.Load() != 3 {
+		t.Errorf("Expected 3 page requests, got %d", requests.Load())
+	}
+}
+
+func TestHTTPBooksRepository_Pagination_ZeroPageSizeUsesDefault(t *testing.T) {
+	var requests atomic.Int32
+	server := paginatedServer(t, 150, 0, &requests)
+	defer server.Close()
+
+	config := DefaultPaginationConfig(PaginationPageNumber)
+	config.PageSize = 0
+	config.Concurrency = 1
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithPagination(config))
+
+	// Sin tamaño de página nunca habría una página incompleta y se recorrerían MaxPages páginas
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertSequentialIDs(t, books, 150)
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 page requests, got %d", requests.Load())
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		link     string
+		expected string
+	}{
+		{"no header", "", ""},
+		{"no next", `<https://api.test/books?page=1>; rel="prev"`, ""},
+		{"absolute", `<https://other.test/books?cursor=x>; rel="next"`, "https://other.test/books?cursor=x"},
+		{"relative", `</v2/books?cursor=y>; rel=next`, "https://api.test/v2/books?cursor=y"},
+		{"multiple rels", `<?cursor=z>; rel="last next"`, "https://api.test/books?cursor=z"},
+		{"comma in URL", `<https://api.test/books?ids=1,2>; rel="prev", <https://api.test/books?ids=3,4>; rel="next"`, "https://api.test/books?ids=3,4"},
+		{"comma in quoted param", `<?cursor=a>; title="first, then"; rel="prev", <?cursor=b>; rel=next`, "https://api.test/books?cursor=b"},
+		{"escaped quote", `<?cursor=c>; title="say \"next\", later"; rel="next"`, "https://api.test/books?cursor=c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.link != "" {
+				header.Set("Link", tt.link)
+			}
+			next, err := nextLink(header, "https://api.test/books?page=1")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if next != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, next)
+			}
+		})
+	}
+}
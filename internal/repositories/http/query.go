@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// defaultAuthorParam es el parámetro con el que mockapi filtra por autor
+const defaultAuthorParam = "author"
+
+// WithAuthorQueryParam define el parámetro de la API usado para filtrar por autor en la fuente
+// Un parámetro vacío desactiva el filtrado en la fuente y QueryBooks filtra el catálogo completo
+func WithAuthorQueryParam(param string) Option {
+	return func(r *HTTPBooksRepository) {
+		r.authorParam = param
+	}
+}
+
+// QueryBooks implementa la interfaz QueryableBooksRepository
+// El autor se envía a la API como parámetro; el resto de la consulta se aplica en memoria, igual que
+// el propio autor porque la API compara por subcadena y sin distinguir mayúsculas
+func (p *HTTPBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	if query.Author == "" || p.authorParam == "" {
+		books, err := p.GetBooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(books), nil
+	}
+
+	p.stats.calls.Add(1)
+	queryURL, err := withQueryParam(p.apiURL, p.authorParam, query.Author)
+	if err != nil {
+		return nil, fmt.Errorf("%w: building query URL: %w", domain.ErrUpstreamUnavailable, err)
+	}
+
+	books, _, err := p.fetchCatalog(ctx, queryURL)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		// mockapi responde 404 cuando el filtro no coincide con ningún libro
+		books, err = []domain.Book{}, nil
+	}
+	if err == nil {
+		// Un resultado filtrado no reemplaza la cuarentena ni el snapshot del catálogo completo
+		books, err = p.validate(ctx, books, false)
+	}
+	if err != nil {
+		// Sin la fuente, o si la validación rechaza la respuesta, se filtra el último snapshot como en GetBooks
+		if books, err = p.serveSnapshot(ctx, err); err != nil {
+			return nil, err
+		}
+	}
+	return query.Apply(books), nil
+}
+
+// withQueryParam agrega o reemplaza un parámetro en la URL
+func withQueryParam(rawURL, param, value string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	values := u.Query()
+	values.Set(param, value)
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
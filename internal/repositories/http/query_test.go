@@ -0,0 +1,133 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/validation"
+)
+
+// authorFilterServer imita a mockapi: filtra por subcadena del autor y responde 404 sin coincidencias
+func authorFilterServer(t *testing.T, queries *[]string) *httptest.Server {
+	t.Helper()
+	catalog := []struct{ id, author string }{{"1", "Robert C. Martin"}, {"2", "Robert C. Martin Jr."}, {"3", "Alan Donovan"}}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.RawQuery)
+		author := strings.ToLower(r.URL.Query().Get("author"))
+
+		var items []string
+		for _, book := range catalog {
+			if strings.Contains(strings.ToLower(book.author), author) {
+				items = append(items, `{"id": `+book.id+`, "name": "Book `+book.id+`", "author": "`+book.author+`", "units_sold": 10, "price": 10}`)
+			}
+		}
+		if len(items) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`"Not found"`))
+			return
+		}
+		w.Write([]byte("[" + strings.Join(items, ",") + "]"))
+	}))
+}
+
+func TestHTTPBooksRepository_QueryBooks_PushesAuthorToAPI(t *testing.T) {
+	var queries []string
+	server := authorFilterServer(t, &queries)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{Author: "Robert C. Martin"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(queries) != 1 || queries[0] != "author=Robert+C.+Martin" {
+		t.Errorf("Expected the author filter to be sent to the API, got %v", queries)
+	}
+	// La API compara por subcadena: el repositorio debe quedarse sólo con las coincidencias exactas
+	if len(books) != 1 || books[0].ID != 1 {
+		t.Errorf("Expected only book 1, got %+v", books)
+	}
+}
+
+func TestHTTPBooksRepository_QueryBooks_NoMatches(t *testing.T) {
+	var queries []string
+	server := authorFilterServer(t, &queries)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{Author: "Nobody"})
+	if err != nil {
+		t.Fatalf("Expected no error for a 404 on a filtered request, got %v", err)
+	}
+	if books == nil || len(books) != 0 {
+		t.Errorf("Expected an empty result, got %#v", books)
+	}
+}
+
+func TestHTTPBooksRepository_QueryBooks_WithoutPushDown(t *testing.T) {
+	var queries []string
+	server := authorFilterServer(t, &queries)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithAuthorQueryParam(""))
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{
+		Author: "Alan Donovan",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(queries) != 1 || queries[0] != "" {
+		t.Errorf("Expected the full catalog to be requested, got %v", queries)
+	}
+	if len(books) != 1 || books[0].ID != 3 {
+		t.Errorf("Expected only book 3, got %+v", books)
+	}
+}
+
+func TestHTTPBooksRepository_QueryBooks_InvalidQuery(t *testing.T) {
+	repository := NewHTTPBooksRepositoryWithConfig(http.DefaultClient, "http://127.0.0.1:0")
+
+	_, err := repository.QueryBooks(context.Background(), domain.BooksQuery{Limit: -1})
+	if !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("Expected ErrInvalidQuery, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_QueryBooks_ValidationFailureServesSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("author") {
+			// La respuesta filtrada trae un libro sin nombre, que PolicyFail rechaza
+			w.Write([]byte(`[{"id": 2, "name": "", "author": "Author", "units_sold": 10, "price": 5}]`))
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "Book 1", "author": "Author", "units_sold": 10, "price": 5}, {"id": 3, "name": "Book 3", "author": "Other", "units_sold": 10, "price": 5}]`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(NoRetryPolicy()), WithSnapshots(DefaultSnapshotConfig(t.TempDir())),
+		WithValidation(validation.NewPipeline(validation.PolicyFail, validation.DefaultRules()...)))
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Igual que GetBooks, una respuesta rechazada por la validación se reemplaza por el snapshot filtrado
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := repository.QueryBooks(ctx, domain.BooksQuery{Author: "Author"})
+	if err != nil {
+		t.Fatalf("Expected the snapshot to be served, got %v", err)
+	}
+	if len(books) != 1 || books[0].ID != 1 {
+		t.Errorf("Expected only book 1 from the snapshot, got %+v", books)
+	}
+	if info, _ := domain.FetchInfoFromContext(ctx); !info.FromSnapshot {
+		t.Errorf("Expected FromSnapshot to be recorded, got %+v", info)
+	}
+}
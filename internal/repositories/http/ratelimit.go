@@ -0,0 +1,278 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// ErrRateLimited indica que la petición no se envió porque se alcanzó el límite del host
+// Se devuelve junto con domain.ErrUpstreamUnavailable, o domain.ErrUpstreamTimeout si el cupo
+// no iba a llegar antes del deadline, y nunca se reintenta
+var ErrRateLimited = errors.New("outbound rate limit reached")
+
+// RateLimit define el ritmo y la concurrencia permitidos hacia un host
+type RateLimit struct {
+	// Rate es la cantidad de peticiones por segundo permitidas (0 no limita el ritmo)
+	Rate float64
+	// Burst es la cantidad de peticiones que pueden enviarse juntas tras un periodo sin uso (mínimo 1)
+	Burst int
+	// MaxInFlight es la cantidad máxima de peticiones simultáneas (0 no limita la concurrencia)
+	MaxInFlight int
+	// FailFast rechaza las peticiones que no tienen cupo en lugar de esperarlo
+	// Sin FailFast se espera hasta el deadline del contexto, y se rechaza de inmediato si
+	// el cupo no va a llegar a tiempo
+	FailFast bool
+	// MinRate es el ritmo mínimo al que se baja ante respuestas 429 (Rate/10 si es cero)
+	MinRate float64
+}
+
+// RateLimitConfig define los límites por host; los hosts no listados usan Default
+type RateLimitConfig struct {
+	Default RateLimit
+	// Hosts usa como clave el host de la URL, con el puerto si lo tiene (por ejemplo "api.example.com:8443")
+	Hosts map[string]RateLimit
+}
+
+// WithRateLimits limita el ritmo y la concurrencia de las peticiones a cada host
+// Ante una respuesta 429 el ritmo del host se reduce a la mitad (sin bajar de MinRate) y se respeta
+// su Retry-After; cada respuesta exitosa lo recupera gradualmente hasta Rate
+func WithRateLimits(config RateLimitConfig) Option {
+	return func(r *HTTPBooksRepository) {
+		r.limits = newHostLimiters(config)
+	}
+}
+
+// RateLimitStats resume el estado del limitador de un host
+type RateLimitStats struct {
+	// Rate es el ritmo vigente, que puede ser menor al configurado tras respuestas 429
+	Rate float64 `json:"rate"`
+	// InFlight es la cantidad de peticiones en curso
+	InFlight int `json:"in_flight"`
+	// Throttled es la cantidad de respuestas 429 recibidas
+	Throttled uint64 `json:"throttled"`
+	// Rejected es la cantidad de peticiones rechazadas por falta de cupo
+	Rejected uint64 `json:"rejected"`
+}
+
+// RateLimitStats devuelve el estado de los limitadores de los hosts consultados hasta ahora
+func (p *HTTPBooksRepository) RateLimitStats() map[string]RateLimitStats {
+	if p.limits == nil {
+		return nil
+	}
+	return p.limits.stats()
+}
+
+// hostLimiters crea y guarda un limitador por host
+type hostLimiters struct {
+	config RateLimitConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+}
+
+func newHostLimiters(config RateLimitConfig) *hostLimiters {
+	return &hostLimiters{config: config, now: time.Now, hosts: make(map[string]*hostLimiter)}
+}
+
+func (l *hostLimiters) get(host string) *hostLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limiter, ok := l.hosts[host]
+	if !ok {
+		limit, ok := l.config.Hosts[host]
+		if !ok {
+			limit = l.config.Default
+		}
+		limiter = newHostLimiter(limit, l.now)
+		l.hosts[host] = limiter
+	}
+	return limiter
+}
+
+func (l *hostLimiters) stats() map[string]RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]RateLimitStats, len(l.hosts))
+	for host, limiter := range l.hosts {
+		stats[host] = limiter.stats()
+	}
+	return stats
+}
+
+// hostLimiter combina un token bucket con un semáforo de peticiones en curso
+type hostLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+	// slots es nil si no se limita la concurrencia
+	slots chan struct{}
+
+	mu sync.Mutex
+	// rate es el ritmo vigente; 0 si no se limita el ritmo
+	rate   float64
+	tokens float64
+	last   time.Time
+	// blockedUntil es el fin de la espera pedida por el servidor con Retry-After
+	blockedUntil time.Time
+
+	throttled atomic.Uint64
+	rejected  atomic.Uint64
+}
+
+func newHostLimiter(limit RateLimit, now func() time.Time) *hostLimiter {
+	limit.Burst = max(limit.Burst, 1)
+	if limit.MinRate <= 0 || limit.MinRate > limit.Rate {
+		limit.MinRate = limit.Rate / 10
+	}
+	h := &hostLimiter{
+		limit:  limit,
+		now:    now,
+		rate:   limit.Rate,
+		tokens: float64(limit.Burst),
+		last:   now(),
+	}
+	if limit.MaxInFlight > 0 {
+		h.slots = make(chan struct{}, limit.MaxInFlight)
+	}
+	return h
+}
+
+// acquire espera un lugar entre las peticiones en curso y un token del bucket
+// Devuelve la función que libera el lugar, que debe llamarse al terminar de leer la respuesta
+func (h *hostLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	release := func() {}
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		default:
+			if h.limit.FailFast {
+				return nil, h.reject(fmt.Errorf("%w: %w: %d requests in flight to %s", domain.ErrUpstreamUnavailable, ErrRateLimited, h.limit.MaxInFlight, host))
+			}
+			select {
+			case h.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, h.reject(fmt.Errorf("%w: waiting for a free slot to %s", classifyRequestError(ctx, ctx.Err()), host))
+			}
+		}
+		release = func() { <-h.slots }
+	}
+
+	if err := h.take(ctx, host); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// take consume un token, esperando si hace falta
+func (h *hostLimiter) take(ctx context.Context, host string) error {
+	wait, ok := h.reserve()
+	if !ok || wait <= 0 {
+		return nil
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	switch {
+	case h.limit.FailFast:
+		h.cancelReservation()
+		return h.reject(fmt.Errorf("%w: %w: next request to %s allowed in %v", domain.ErrUpstreamUnavailable, ErrRateLimited, host, wait))
+	case hasDeadline && h.now().Add(wait).After(deadline):
+		// Esperar sería inútil: el cupo llegaría después del deadline
+		h.cancelReservation()
+		return h.reject(fmt.Errorf("%w: %w: next request to %s allowed in %v, after the deadline", domain.ErrUpstreamTimeout, ErrRateLimited, host, wait))
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		h.cancelReservation()
+		return h.reject(fmt.Errorf("%w: waiting for the rate limit of %s", classifyRequestError(ctx, ctx.Err()), host))
+	}
+}
+
+// reserve toma un token aunque todavía no esté disponible y devuelve cuánto hay que esperar,
+// incluida la espera pedida por el servidor con Retry-After
+// ok es falso si no se limita el ritmo ni hay una espera pedida por el servidor
+func (h *hostLimiter) reserve() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	var wait time.Duration
+	if h.rate > 0 {
+		h.tokens = math.Min(h.tokens+now.Sub(h.last).Seconds()*h.rate, float64(h.limit.Burst))
+		h.last = now
+		h.tokens--
+		if h.tokens < 0 {
+			wait = time.Duration(-h.tokens / h.rate * float64(time.Second))
+		}
+	}
+	// El bloqueo por Retry-After se respeta aunque sólo se limiten las peticiones en curso
+	if blocked := h.blockedUntil.Sub(now); blocked > wait {
+		wait = blocked
+	}
+	return wait, h.rate > 0 || wait > 0
+}
+
+// cancelReservation devuelve el token de una petición que no llegó a enviarse
+func (h *hostLimiter) cancelReservation() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.rate > 0 {
+		h.tokens = math.Min(h.tokens+1, float64(h.limit.Burst))
+	}
+}
+
+func (h *hostLimiter) reject(err error) error {
+	h.rejected.Add(1)
+	return err
+}
+
+// observe ajusta el ritmo según la respuesta: lo reduce ante un 429 y lo recupera ante un éxito
+func (h *hostLimiter) observe(resp *http.Response) {
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		h.throttled.Add(1)
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), h.now())
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if h.rate > 0 {
+			h.rate = math.Max(h.rate/2, h.limit.MinRate)
+			h.tokens = math.Min(h.tokens, 0)
+		}
+		if retryAfter > 0 {
+			h.blockedUntil = h.now().Add(retryAfter)
+		}
+	case resp.StatusCode < http.StatusBadRequest:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// Recuperación aditiva: diez respuestas exitosas devuelven el ritmo configurado
+		if h.rate > 0 && h.rate < h.limit.Rate {
+			h.rate = math.Min(h.rate+h.limit.Rate/10, h.limit.Rate)
+		}
+	}
+}
+
+func (h *hostLimiter) stats() RateLimitStats {
+	h.mu.Lock()
+	rate := h.rate
+	h.mu.Unlock()
+	return RateLimitStats{
+		Rate:      rate,
+		InFlight:  len(h.slots),
+		Throttled: h.throttled.Load(),
+		Rejected:  h.rejected.Load(),
+	}
+}
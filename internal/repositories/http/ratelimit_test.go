@@ -0,0 +1,230 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+func newLimitedServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if handler != nil {
+			handler(w, r)
+			return
+		}
+		w.Write([]byte(`[{"id":1,"name":"Book","author":"Author","units_sold":10,"price":20}]`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestRateLimit_SpacesRequests(t *testing.T) {
+	server, hits := newLimitedServer(t, nil)
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRateLimits(RateLimitConfig{Default: RateLimit{Rate: 20, Burst: 1}}))
+
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := repository.GetBooks(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	// La primera petición usa el burst; las otras dos esperan 50ms cada una
+	if elapsed := time.Since(started); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be spaced by the rate limit, took %v", elapsed)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("Expected 3 upstream requests, got %d", hits.Load())
+	}
+}
+
+func TestRateLimit_FailFast(t *testing.T) {
+	server, hits := newLimitedServer(t, nil)
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithConditionalRequests(false),
+		WithRateLimits(RateLimitConfig{Default: RateLimit{Rate: 1, Burst: 1, FailFast: true}}))
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	started := time.Now()
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected a rate limit error, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected an immediate rejection, took %v", elapsed)
+	}
+	// Los rechazos no se reintentan
+	if stats := repository.RetryStats(); stats.Retries != 0 {
+		t.Errorf("Expected no retries, got %d", stats.Retries)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("Expected 1 upstream request, got %d", hits.Load())
+	}
+	host := mustHost(t, server.URL)
+	if stats := repository.RateLimitStats()[host]; stats.Rejected != 1 {
+		t.Errorf("Expected 1 rejected request, got %+v", stats)
+	}
+}
+
+func TestRateLimit_RejectsWhenDeadlineComesFirst(t *testing.T) {
+	server, _ := newLimitedServer(t, nil)
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRateLimits(RateLimitConfig{Default: RateLimit{Rate: 1, Burst: 1}}))
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := repository.GetBooks(ctx)
+	if !errors.Is(err, ErrRateLimited) || !errors.Is(err, domain.ErrUpstreamTimeout) {
+		t.Errorf("Expected a rate limit timeout, got %v", err)
+	}
+	// No tiene sentido esperar un cupo que llega después del deadline
+	if elapsed := time.Since(started); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected an immediate rejection, took %v", elapsed)
+	}
+}
+
+func TestRateLimit_MaxInFlight(t *testing.T) {
+	var current, peak atomic.Int32
+	server, _ := newLimitedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte(`[]`))
+	})
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithConditionalRequests(false),
+		WithRateLimits(RateLimitConfig{Default: RateLimit{MaxInFlight: 2}}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repository.GetBooks(context.Background()); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak.Load())
+	}
+}
+
+func TestRateLimit_AdaptsTo429(t *testing.T) {
+	var throttle atomic.Bool
+	throttle.Store(true)
+	server, _ := newLimitedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if throttle.CompareAndSwap(true, false) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	host := mustHost(t, server.URL)
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithConditionalRequests(false),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, RetryableStatusCodes: []int{http.StatusTooManyRequests}}),
+		WithRateLimits(RateLimitConfig{
+			Default: RateLimit{Rate: 1},
+			Hosts:   map[string]RateLimit{host: {Rate: 1000, Burst: 10}},
+		}))
+
+	// El 429 reduce el ritmo a la mitad y el reintento exitoso lo recupera un décimo
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	stats := repository.RateLimitStats()[host]
+	if stats.Throttled != 1 {
+		t.Errorf("Expected 1 throttled response, got %d", stats.Throttled)
+	}
+	if stats.Rate != 600 {
+		t.Errorf("Expected the rate to drop to 500 and recover to 600, got %v", stats.Rate)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := repository.GetBooks(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if stats := repository.RateLimitStats()[host]; stats.Rate != 1000 {
+		t.Errorf("Expected the rate to recover to 1000, got %v", stats.Rate)
+	}
+}
+
+func TestHostLimiter_RetryAfterBlocksHost(t *testing.T) {
+	now := time.Now()
+	limiter := newHostLimiter(RateLimit{Rate: 100, Burst: 5}, func() time.Time { return now })
+
+	limiter.observe(&http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"2"}}})
+
+	wait, ok := limiter.reserve()
+	if !ok || wait != 2*time.Second {
+		t.Errorf("Expected to wait for Retry-After (2s), got %v", wait)
+	}
+	if rate := limiter.stats().Rate; rate != 50 {
+		t.Errorf("Expected the rate to be halved to 50, got %v", rate)
+	}
+}
+
+func TestRateLimit_RetryAfterWithOnlyMaxInFlight(t *testing.T) {
+	var calls atomic.Int32
+	server, _ := newLimitedServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(NoRetryPolicy()),
+		WithRateLimits(RateLimitConfig{Default: RateLimit{MaxInFlight: 2, FailFast: true}}))
+
+	if _, err := repository.GetBooks(context.Background()); err == nil {
+		t.Fatal("Expected the 429 to fail the first request")
+	}
+
+	// Sin límite de ritmo el host sigue bloqueado durante el Retry-After
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited while the host is blocked, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected the blocked request not to reach the server, got %d requests", calls.Load())
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/http/fixture"
+)
+
+// mockapiBooksURL es la URL del proveedor con la que se grabó testdata/fixtures/mockapi_books.json
+const mockapiBooksURL = "https://6781684b85151f714b0aa5db.mockapi.io/api/v1/books"
+
+func TestHTTPBooksRepository_ReplayedFixture(t *testing.T) {
+	mode, err := fixture.ModeFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport, err := fixture.New(fixture.Config{Path: "testdata/fixtures/mockapi_books.json", Mode: mode})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repository := NewHTTPBooksRepositoryWithConfig(transport.Client(5*time.Second), mockapiBooksURL)
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transport.Recording() {
+		// Al grabar el contenido depende del proveedor
+		return
+	}
+	if len(books) != 8 {
+		t.Fatalf("Expected 8 books, got %d", len(books))
+	}
+	if books[3].Name != "The Hobbit" || books[3].Price != domain.Price(15) {
+		t.Errorf("Expected The Hobbit at $15, got %+v", books[3])
+	}
+
+	// La segunda consulta es condicional; el fixture no tiene un 304, así que se reproduce el 200
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Errorf("Expected no error on the repeated request, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_ReplayedLatencyTimesOut(t *testing.T) {
+	transport, err := fixture.New(fixture.Config{Path: "testdata/fixtures/mockapi_books.json", ReplayLatency: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	repository := NewHTTPBooksRepositoryWithConfig(transport.Client(5*time.Second), mockapiBooksURL, WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := repository.GetBooks(ctx); err == nil {
+		t.Errorf("Expected the recorded latency to exceed the deadline")
+	}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// RetryPolicy define cuántas veces y con qué espera se reintenta una petición a la API externa
+type RetryPolicy struct {
+	// MaxAttempts es el número total de intentos (1 desactiva los reintentos)
+	MaxAttempts int
+	// BaseDelay es la espera antes del primer reintento
+	BaseDelay time.Duration
+	// MaxDelay limita la espera calculada por el backoff exponencial
+	MaxDelay time.Duration
+	// MaxRetryAfter es la mayor espera pedida por el servidor con Retry-After que se respeta;
+	// si el servidor pide más no se reintenta (0 usa MaxDelay)
+	MaxRetryAfter time.Duration
+	// Multiplier es el factor de crecimiento de la espera entre reintentos
+	Multiplier float64
+	// Jitter es la fracción (0 a 1) de la espera que se aleatoriza para evitar reintentos sincronizados
+	Jitter float64
+	// RetryableStatusCodes son los códigos HTTP que se consideran transitorios
+	RetryableStatusCodes []int
+	// RetryOnNetworkErrors reintenta errores de conexión (conexión rechazada, reseteada, DNS, etc.)
+	RetryOnNetworkErrors bool
+	// RetryOnTimeout reintenta cuando un intento individual excede el timeout del cliente
+	RetryOnTimeout bool
+}
+
+// DefaultRetryPolicy devuelve la política usada por defecto por los constructores del repositorio
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:   3,
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      2 * time.Second,
+		MaxRetryAfter: 10 * time.Second,
+		Multiplier:    2,
+		Jitter:        0.5,
+		RetryableStatusCodes: []int{
+			http.StatusRequestTimeout,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryOnNetworkErrors: true,
+		RetryOnTimeout:       true,
+	}
+}
+
+// NoRetryPolicy devuelve una política que realiza un único intento
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// shouldRetry indica si el error de un intento puede reintentarse según la política
+func (p RetryPolicy) shouldRetry(err error) bool {
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &statusErr):
+		return slices.Contains(p.RetryableStatusCodes, statusErr.StatusCode)
+	case errors.Is(err, domain.ErrContextCanceled), errors.Is(err, domain.ErrMalformedPayload), errors.Is(err, ErrRateLimited):
+		return false
+	case errors.Is(err, domain.ErrUpstreamTimeout):
+		return p.RetryOnTimeout
+	case errors.Is(err, domain.ErrUpstreamUnavailable):
+		return p.RetryOnNetworkErrors
+	default:
+		return false
+	}
+}
+
+// retryAfterLimit devuelve la mayor espera pedida con Retry-After que se respeta antes de reintentar
+func (p RetryPolicy) retryAfterLimit() time.Duration {
+	if p.MaxRetryAfter > 0 {
+		return p.MaxRetryAfter
+	}
+	return p.MaxDelay
+}
+
+// backoff calcula la espera antes del reintento número retry (empezando en 1)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// StatusError representa una respuesta de la API externa con un código distinto de 200
+// Envuelve domain.ErrUpstreamUnavailable para que errors.Is siga funcionando
+type StatusError struct {
+	StatusCode int
+	// RetryAfter es la espera solicitada por el servidor mediante el header Retry-After (0 si no se envió)
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%v: unexpected status code %d", domain.ErrUpstreamUnavailable, e.StatusCode)
+}
+
+// Unwrap permite identificar el error como domain.ErrUpstreamUnavailable
+func (e *StatusError) Unwrap() error {
+	return domain.ErrUpstreamUnavailable
+}
+
+// parseRetryAfter interpreta el header Retry-After en segundos o como fecha HTTP
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// RetryStats resume los intentos realizados por el repositorio desde su creación
+type RetryStats struct {
+	// Calls es el número de llamadas a GetBooks
+	Calls uint64 `json:"calls"`
+	// Attempts es el número total de peticiones HTTP realizadas
+	Attempts uint64 `json:"attempts"`
+	// Retries es el número de peticiones que fueron reintentos
+	Retries uint64 `json:"retries"`
+	// Exhausted es el número de llamadas que fallaron tras agotar los reintentos
+	Exhausted uint64 `json:"exhausted"`
+}
+
+// retryCounters acumula las estadísticas de reintentos de forma segura para concurrencia
+type retryCounters struct {
+	calls     atomic.Uint64
+	attempts  atomic.Uint64
+	retries   atomic.Uint64
+	exhausted atomic.Uint64
+}
+
+func (c *retryCounters) snapshot() RetryStats {
+	return RetryStats{
+		Calls:     c.calls.Load(),
+		Attempts:  c.attempts.Load(),
+		Retries:   c.retries.Load(),
+		Exhausted: c.exhausted.Load(),
+	}
+}
@@ -0,0 +1,235 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// fastRetryPolicy devuelve una política con esperas cortas para las pruebas
+func fastRetryPolicy(maxAttempts int) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return policy
+}
+
+func TestHTTPBooksRepository_Retry_TransientThenSuccess(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"id": 1, "name": "Book", "author": "Author", "units_sold": 10, "price": 5}]`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected success after retries, got %v", err)
+	}
+	if len(books) != 1 {
+		t.Errorf("Expected 1 book, got %d", len(books))
+	}
+
+	stats := repository.RetryStats()
+	if stats.Calls != 1 || stats.Attempts != 3 || stats.Retries != 2 || stats.Exhausted != 0 {
+		t.Errorf("Unexpected retry stats: %+v", stats)
+	}
+}
+
+func TestHTTPBooksRepository_Retry_Exhausted(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(4)))
+
+	_, err := repository.GetBooks(context.Background())
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Expected StatusError 502, got %v", err)
+	}
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("Expected 4 attempts, got %d", calls.Load())
+	}
+	if stats := repository.RetryStats(); stats.Exhausted != 1 {
+		t.Errorf("Expected 1 exhausted call, got %+v", stats)
+	}
+}
+
+func TestHTTPBooksRepository_Retry_NonRetryableStatus(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+	if _, err := repository.GetBooks(context.Background()); err == nil {
+		t.Fatal("Expected an error for 404")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt for a non-retryable status, got %d", calls.Load())
+	}
+}
+
+func TestHTTPBooksRepository_Retry_MalformedPayloadNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, domain.ErrMalformedPayload) {
+		t.Fatalf("Expected ErrMalformedPayload, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt for a malformed payload, got %d", calls.Load())
+	}
+}
+
+func TestHTTPBooksRepository_Retry_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var firstCall time.Time
+	var secondCall time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			firstCall = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		secondCall = time.Now()
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(2)))
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected success, got %v", err)
+	}
+	if wait := secondCall.Sub(firstCall); wait < time.Second {
+		t.Errorf("Expected to wait at least the Retry-After value, waited %v", wait)
+	}
+}
+
+func TestHTTPBooksRepository_Retry_RetryAfterAboveLimit(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := fastRetryPolicy(3)
+	policy.MaxRetryAfter = time.Second
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(policy))
+
+	// Sin deadline en el contexto, como en las peticiones de gin: no debe esperarse un día
+	start := time.Now()
+	_, err := repository.GetBooks(context.Background())
+
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Fatalf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to fail fast, took %v", elapsed)
+	}
+	if stats := repository.RetryStats(); stats.Exhausted != 1 {
+		t.Errorf("Expected the retries to count as exhausted, got %+v", stats)
+	}
+}
+
+func TestHTTPBooksRepository_Retry_StopsAtContextDeadline(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithRetryPolicy(fastRetryPolicy(5)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repository.GetBooks(ctx)
+
+	// El Retry-After excede el deadline, así que no debe esperarse ni reintentarse
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Fatalf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls.Load())
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("Expected to fail fast, took %v", elapsed)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 1 * time.Second, Multiplier: 2, Jitter: 0.5}
+
+	for retry, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		for i := 0; i < 20; i++ {
+			delay := policy.backoff(retry)
+			if delay > ceiling || delay < ceiling/2 {
+				t.Errorf("Retry %d: delay %v outside [%v, %v]", retry, delay, ceiling/2, ceiling)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-1", 0},
+		{"garbage", 0},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{now.Add(-10 * time.Second).Format(http.TimeFormat), 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
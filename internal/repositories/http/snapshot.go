@@ -0,0 +1,256 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+const (
+	// snapshotFormatVersion es la versión del formato de los snapshots escritos
+	snapshotFormatVersion = 1
+	snapshotPrefix        = "books-"
+	snapshotSuffix        = ".json"
+)
+
+// SnapshotConfig define dónde y cuántos snapshots del catálogo se conservan en disco
+type SnapshotConfig struct {
+	// Dir es el directorio donde se guardan los snapshots (se crea si no existe)
+	Dir string
+	// Retention es la cantidad máxima de snapshots conservados (0 sin límite)
+	Retention int
+	// MaxAge elimina los snapshots más antiguos que este valor (0 sin límite)
+	// El snapshot más reciente nunca se elimina
+	MaxAge time.Duration
+}
+
+// DefaultSnapshotConfig devuelve una configuración que conserva los últimos 5 snapshots de una semana
+func DefaultSnapshotConfig(dir string) SnapshotConfig {
+	return SnapshotConfig{
+		Dir:       dir,
+		Retention: 5,
+		MaxAge:    7 * 24 * time.Hour,
+	}
+}
+
+// WithSnapshots guarda un snapshot tras cada obtención exitosa y lo sirve si el proveedor falla
+// El snapshot más reciente se carga en memoria al crear el repositorio
+func WithSnapshots(config SnapshotConfig) Option {
+	return func(r *HTTPBooksRepository) {
+		r.snapshots = newSnapshotStore(config)
+	}
+}
+
+// snapshotFile es el contenido de un snapshot en disco
+type snapshotFile struct {
+	Version   int             `json:"version"`
+	CreatedAt time.Time       `json:"created_at"`
+	Source    string          `json:"source"`
+	Checksum  string          `json:"checksum"`
+	Books     json.RawMessage `json:"books"`
+}
+
+// snapshot es un snapshot válido cargado en memoria
+type snapshot struct {
+	createdAt time.Time
+	books     []domain.Book
+}
+
+// snapshotStore escribe, valida, carga y depura los snapshots de un directorio
+type snapshotStore struct {
+	config SnapshotConfig
+	now    func() time.Time
+
+	mu     sync.Mutex
+	latest *snapshot
+	loaded bool
+}
+
+func newSnapshotStore(config SnapshotConfig) *snapshotStore {
+	return &snapshotStore{config: config, now: time.Now}
+}
+
+// save escribe el catálogo como un nuevo snapshot de forma atómica y depura los antiguos
+func (s *snapshotStore) save(source string, books []domain.Book) error {
+	if books == nil {
+		books = []domain.Book{}
+	}
+	raw, err := json.Marshal(books)
+	if err != nil {
+		return err
+	}
+	createdAt := s.now().UTC()
+	content, err := json.Marshal(snapshotFile{
+		Version:   snapshotFormatVersion,
+		CreatedAt: createdAt,
+		Source:    source,
+		Checksum:  booksChecksum(raw),
+		Books:     raw,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.config.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating snapshot dir: %w", err)
+	}
+
+	// Escribir en un archivo temporal y renombrarlo para no dejar snapshots a medio escribir
+	tmp, err := os.CreateTemp(s.config.Dir, ".tmp-"+snapshotPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("creating snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing snapshot: %w", err)
+	}
+	name := fmt.Sprintf("%s%020d%s", snapshotPrefix, createdAt.UnixNano(), snapshotSuffix)
+	if err := os.Rename(tmp.Name(), filepath.Join(s.config.Dir, name)); err != nil {
+		return fmt.Errorf("publishing snapshot: %w", err)
+	}
+
+	s.latest = &snapshot{createdAt: createdAt, books: slices.Clone(books)}
+	s.loaded = true
+	return s.pruneLocked()
+}
+
+// latestValid devuelve el snapshot válido más reciente, leyéndolo de disco la primera vez
+func (s *snapshotStore) latestValid() (*snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loaded {
+		if s.latest == nil {
+			return nil, errors.New("no valid snapshot available")
+		}
+		return s.latest, nil
+	}
+
+	names, err := s.listLocked()
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, name := range names {
+		loaded, err := readSnapshot(filepath.Join(s.config.Dir, name))
+		if err != nil {
+			// Un snapshot corrupto se saltea y se intenta con el anterior
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		s.latest, s.loaded = loaded, true
+		return loaded, nil
+	}
+	s.loaded = true
+	errs = append(errs, errors.New("no valid snapshot available"))
+	return nil, errors.Join(errs...)
+}
+
+// listLocked devuelve los nombres de los snapshots del directorio, del más reciente al más antiguo
+func (s *snapshotStore) listLocked() ([]string, error) {
+	entries, err := os.ReadDir(s.config.Dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing snapshots: %w", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if _, ok := snapshotTimestamp(entry.Name()); ok && !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	// El nombre incluye el timestamp con ancho fijo, así que el orden lexicográfico es cronológico
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	return names, nil
+}
+
+// pruneLocked elimina los snapshots que exceden Retention o MaxAge, conservando siempre el más reciente
+func (s *snapshotStore) pruneLocked() error {
+	names, err := s.listLocked()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i, name := range names {
+		if i == 0 {
+			continue
+		}
+		createdAt, _ := snapshotTimestamp(name)
+		tooMany := s.config.Retention > 0 && i >= s.config.Retention
+		tooOld := s.config.MaxAge > 0 && s.now().Sub(createdAt) > s.config.MaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(filepath.Join(s.config.Dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readSnapshot lee y valida un snapshot (versión de formato y checksum)
+func readSnapshot(path string) (*snapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file snapshotFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	if file.Version != snapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", file.Version)
+	}
+	// El checksum cubre los bytes guardados y no una nueva serialización, que cambia si cambia
+	// el formato de domain.Book (por ejemplo, los precios numéricos anteriores a domain.Money)
+	if checksum := booksChecksum(file.Books); checksum != file.Checksum {
+		return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", file.Checksum, checksum)
+	}
+	books := []domain.Book{}
+	if err := json.Unmarshal(file.Books, &books); err != nil {
+		return nil, fmt.Errorf("decoding snapshot books: %w", err)
+	}
+	return &snapshot{createdAt: file.CreatedAt, books: books}, nil
+}
+
+// booksChecksum calcula el SHA-256 de la serialización JSON del catálogo tal como se guarda
+func booksChecksum(raw json.RawMessage) string {
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// snapshotTimestamp extrae el momento de creación del nombre de un snapshot
+func snapshotTimestamp(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, snapshotPrefix) || !strings.HasSuffix(name, snapshotSuffix) {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, snapshotPrefix), snapshotSuffix), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// toggleServer sirve un catálogo mientras healthy sea verdadero y responde 503 en caso contrario
+func toggleServer(healthy *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "Book 1", "author": "Author", "units_sold": 10, "price": 5}]`))
+	}))
+}
+
+func listSnapshots(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, snapshotPrefix+"*"+snapshotSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return matches
+}
+
+func TestHTTPBooksRepository_Snapshot_ServedOnFailure(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := toggleServer(&healthy)
+	defer server.Close()
+
+	dir := t.TempDir()
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(NoRetryPolicy()), WithConditionalRequests(false), WithSnapshots(DefaultSnapshotConfig(dir)))
+
+	if _, err := repository.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(listSnapshots(t, dir)); n != 1 {
+		t.Fatalf("Expected 1 snapshot on disk, got %d", n)
+	}
+
+	healthy.Store(false)
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := repository.GetBooks(ctx)
+	if err != nil {
+		t.Fatalf("Expected the snapshot to be served, got %v", err)
+	}
+	if len(books) != 1 || books[0].Name != "Book 1" {
+		t.Errorf("Unexpected snapshot books: %v", books)
+	}
+
+	info, _ := domain.FetchInfoFromContext(ctx)
+	if !info.FromSnapshot || info.SnapshotAt.IsZero() || len(info.Warnings) != 1 {
+		t.Errorf("Expected snapshot metadata to be recorded, got %+v", info)
+	}
+}
+
+func TestHTTPBooksRepository_Snapshot_LoadedOnStartup(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := toggleServer(&healthy)
+	defer server.Close()
+
+	dir := t.TempDir()
+	first := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithSnapshots(DefaultSnapshotConfig(dir)))
+	if _, err := first.GetBooks(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Un repositorio nuevo (como tras un reinicio) sirve el snapshot aunque el proveedor esté caído
+	healthy.Store(false)
+	restarted := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(NoRetryPolicy()), WithSnapshots(DefaultSnapshotConfig(dir)))
+
+	books, err := restarted.GetBooks(context.Background())
+	if err != nil || len(books) != 1 {
+		t.Fatalf("Expected the snapshot after restart, got %v (%v)", books, err)
+	}
+}
+
+func TestHTTPBooksRepository_Snapshot_NoSnapshotReturnsCause(t *testing.T) {
+	var healthy atomic.Bool
+	server := toggleServer(&healthy)
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithRetryPolicy(NoRetryPolicy()), WithSnapshots(DefaultSnapshotConfig(t.TempDir())))
+
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, domain.ErrUpstreamUnavailable) {
+		t.Errorf("Expected ErrUpstreamUnavailable, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_Snapshot_NotServedOnCancel(t *testing.T) {
+	dir := t.TempDir()
+	store := newSnapshotStore(DefaultSnapshotConfig(dir))
+	if err := store.save("test", []domain.Book{{ID: 1, Name: "Book"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	repository := NewHTTPBooksRepositoryWithConfig(http.DefaultClient, "http://127.0.0.1:0", WithSnapshots(DefaultSnapshotConfig(dir)))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repository.GetBooks(ctx)
+	if !errors.Is(err, domain.ErrContextCanceled) {
+		t.Errorf("Expected ErrContextCanceled, got %v", err)
+	}
+}
+
+func TestHTTPBooksRepository_Snapshot_SaveFailureIsReportedAsWarning(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := toggleServer(&healthy)
+	defer server.Close()
+
+	// Un archivo en lugar del directorio hace fallar el guardado
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithSnapshots(SnapshotConfig{Dir: dir}))
+
+	ctx := domain.WithFetchInfo(context.Background())
+	books, err := repository.GetBooks(ctx)
+	if err != nil || len(books) != 1 {
+		t.Fatalf("Expected the catalog despite the snapshot failure, got %v, %v", books, err)
+	}
+	info, _ := domain.FetchInfoFromContext(ctx)
+	if len(info.Warnings) != 1 || !strings.Contains(info.Warnings[0], "could not save books snapshot") {
+		t.Errorf("Expected a snapshot warning, got %v", info.Warnings)
+	}
+}
+
+func TestSnapshotStore_SkipsCorruptedSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newSnapshotStore(SnapshotConfig{Dir: dir})
+	store.now = func() time.Time { return clock }
+
+	if err := store.save("test", []domain.Book{{ID: 1, Name: "Old"}}); err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(time.Hour)
+	if err := store.save("test", []domain.Book{{ID: 2, Name: "New"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Alterar el snapshot más reciente para que su checksum no coincida
+	snapshots := listSnapshots(t, dir)
+	newest := snapshots[len(snapshots)-1]
+	content, _ := os.ReadFile(newest)
+	tampered := []byte(string(content[:len(content)-10]) + `"Hacked"}]}`)
+	os.WriteFile(newest, tampered, 0o644)
+
+	fresh := newSnapshotStore(SnapshotConfig{Dir: dir})
+	snap, err := fresh.latestValid()
+	if err != nil {
+		t.Fatalf("Expected the older valid snapshot, got %v", err)
+	}
+	if snap.books[0].Name != "Old" {
+		t.Errorf("Expected the older snapshot to be used, got %v", snap.books)
+	}
+}
+
+func TestSnapshotStore_ReadsSnapshotWithNumericPrice(t *testing.T) {
+	dir := t.TempDir()
+	// Snapshot escrito antes de domain.Money, con el precio como un número de unidades enteras
+	books := `[{"id":1,"name":"Book 1","author":"Author","units_sold":10,"price":40}]`
+	content := `{"version":1,"created_at":"2024-01-01T00:00:00Z","source":"test","checksum":"` + booksChecksum(json.RawMessage(books)) + `","books":` + books + `}`
+	name := fmt.Sprintf("%s%020d%s", snapshotPrefix, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(), snapshotSuffix)
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	snap, err := newSnapshotStore(SnapshotConfig{Dir: dir}).latestValid()
+	if err != nil {
+		t.Fatalf("Expected the snapshot to be valid, got %v", err)
+	}
+	if len(snap.books) != 1 || snap.books[0].Price != domain.Price(40) {
+		t.Errorf("Expected the book at 40 USD, got %+v", snap.books)
+	}
+}
+
+func TestSnapshotStore_Pruning(t *testing.T) {
+	dir := t.TempDir()
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newSnapshotStore(SnapshotConfig{Dir: dir, Retention: 3, MaxAge: 90 * time.Minute})
+	store.now = func() time.Time { return clock }
+
+	for i := 0; i < 5; i++ {
+		if err := store.save("test", []domain.Book{{ID: uint(i + 1), Name: "Book"}}); err != nil {
+			t.Fatal(err)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	if n := len(listSnapshots(t, dir)); n != 3 {
+		t.Errorf("Expected retention to keep 3 snapshots, got %d", n)
+	}
+
+	// Tras mucho tiempo sólo sobrevive el snapshot más reciente
+	clock = clock.Add(24 * time.Hour)
+	if err := store.save("test", []domain.Book{{ID: 9, Name: "Latest"}}); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(listSnapshots(t, dir)); n != 1 {
+		t.Errorf("Expected max age to prune all but the latest snapshot, got %d", n)
+	}
+}
@@ -0,0 +1,225 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportConfig define el cliente HTTP con el que se consulta al proveedor
+type TransportConfig struct {
+	// Timeout limita la duración total de cada intento, incluida la lectura del cuerpo
+	Timeout time.Duration
+
+	// ProxyURL es el proxy de salida; vacío usa HTTP_PROXY, HTTPS_PROXY y NO_PROXY del entorno
+	ProxyURL string
+	// NoProxy lista, separados por comas, los hosts o dominios que no pasan por ProxyURL
+	// (con la misma sintaxis que NO_PROXY, por ejemplo "localhost,.internal,10.0.0.0/8")
+	NoProxy string
+	// DisableProxy ignora ProxyURL y el entorno y conecta siempre de forma directa
+	DisableProxy bool
+
+	// MaxIdleConns es la cantidad máxima de conexiones inactivas en total (0 sin límite)
+	MaxIdleConns int
+	// MaxIdleConnsPerHost es la cantidad máxima de conexiones inactivas por host
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limita las conexiones por host, activas o no (0 sin límite)
+	MaxConnsPerHost int
+	// IdleConnTimeout es cuánto se conserva una conexión inactiva
+	IdleConnTimeout time.Duration
+
+	// DialTimeout limita el establecimiento de la conexión TCP, incluida la resolución DNS
+	DialTimeout time.Duration
+	// TLSHandshakeTimeout limita la negociación TLS
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout limita la espera de los headers de la respuesta (0 sin límite propio)
+	ResponseHeaderTimeout time.Duration
+
+	// KeepAlive es el intervalo de los keep-alive TCP (negativo los desactiva)
+	KeepAlive time.Duration
+	// DisableKeepAlives abre una conexión nueva para cada petición
+	DisableKeepAlives bool
+	// HTTP2 intenta usar HTTP/2 con los servidores que lo admiten
+	HTTP2 bool
+	// CAFile es un bundle PEM de autoridades adicional a las del sistema, para proveedores con CA privada
+	CAFile string
+}
+
+// DefaultTransportConfig devuelve los valores de http.DefaultTransport con el timeout del repositorio
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		Timeout:             timeout,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: http.DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     90 * time.Second,
+		DialTimeout:         30 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		KeepAlive:           30 * time.Second,
+		HTTP2:               true,
+	}
+}
+
+// NewHTTPClient crea un cliente HTTP según la configuración
+func NewHTTPClient(config TransportConfig) (*http.Client, error) {
+	transport, err := config.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: config.Timeout}, nil
+}
+
+// NewHTTPBooksRepositoryWithTransport crea el repositorio para la API por defecto con un cliente
+// construido según la configuración, que queda visible en UpstreamInfo
+// Las opciones se aplican después, por lo que los middlewares envuelven a este transporte
+func NewHTTPBooksRepositoryWithTransport(config TransportConfig, opts ...Option) (*HTTPBooksRepository, error) {
+	client, err := NewHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{func(r *HTTPBooksRepository) { r.transportConfig = &config }}, opts...)
+	return NewHTTPBooksRepositoryWithConfig(client, defaultBooksAPIURL, opts...), nil
+}
+
+func (c TransportConfig) newTransport() (*http.Transport, error) {
+	proxy, err := c.proxyFunc()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: c.DialTimeout, KeepAlive: c.KeepAlive}
+	transport := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          c.MaxIdleConns,
+		MaxIdleConnsPerHost:   c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       c.MaxConnsPerHost,
+		IdleConnTimeout:       c.IdleConnTimeout,
+		TLSHandshakeTimeout:   c.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: c.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		DisableKeepAlives:     c.DisableKeepAlives,
+		ForceAttemptHTTP2:     c.HTTP2,
+	}
+	if !c.HTTP2 {
+		// Un mapa vacío (no nil) desactiva la negociación de HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if c.CAFile != "" {
+		pool, err := loadCertPool(c.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return transport, nil
+}
+
+// proxyFunc resuelve el proxy de cada petición respetando NoProxy
+func (c TransportConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	switch {
+	case c.DisableProxy:
+		return nil, nil
+	case c.ProxyURL == "":
+		return http.ProxyFromEnvironment, nil
+	}
+	if _, err := url.Parse(c.ProxyURL); err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", redactURLError(err))
+	}
+	proxy := (&httpproxy.Config{HTTPProxy: c.ProxyURL, HTTPSProxy: c.ProxyURL, NoProxy: c.NoProxy}).ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}, nil
+}
+
+// loadCertPool agrega las autoridades del bundle a las del sistema
+func loadCertPool(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s has no valid certificates", path)
+	}
+	return pool, nil
+}
+
+// UpstreamInfo describe la conexión con el proveedor para el endpoint de diagnóstico:
+// la URL, el transporte, la política de reintentos y el estado de los límites, sin credenciales
+func (p *HTTPBooksRepository) UpstreamInfo() map[string]any {
+	info := map[string]any{
+		"url": redactURL(p.apiURL),
+		"retry_policy": map[string]any{
+			"max_attempts":    p.retryPolicy.MaxAttempts,
+			"base_delay":      p.retryPolicy.BaseDelay.String(),
+			"max_delay":       p.retryPolicy.MaxDelay.String(),
+			"max_retry_after": p.retryPolicy.retryAfterLimit().String(),
+		},
+		"retry_stats": p.RetryStats(),
+	}
+	if p.transportConfig != nil {
+		info["transport"] = p.transportConfig.describe()
+	} else {
+		info["transport"] = map[string]any{"timeout": p.client.Timeout.String(), "custom": true}
+	}
+	if stats := p.RateLimitStats(); stats != nil {
+		info["rate_limits"] = stats
+	}
+	return info
+}
+
+// describe devuelve la configuración para el endpoint de diagnóstico, sin credenciales del proxy
+func (c TransportConfig) describe() map[string]any {
+	proxy := "environment"
+	switch {
+	case c.DisableProxy:
+		proxy = "disabled"
+	case c.ProxyURL != "":
+		proxy = redactURL(c.ProxyURL)
+	}
+	return map[string]any{
+		"timeout":                 c.Timeout.String(),
+		"proxy":                   proxy,
+		"no_proxy":                c.NoProxy,
+		"max_idle_conns":          c.MaxIdleConns,
+		"max_idle_conns_per_host": c.MaxIdleConnsPerHost,
+		"max_conns_per_host":      c.MaxConnsPerHost,
+		"idle_conn_timeout":       c.IdleConnTimeout.String(),
+		"dial_timeout":            c.DialTimeout.String(),
+		"tls_handshake_timeout":   c.TLSHandshakeTimeout.String(),
+		"response_header_timeout": c.ResponseHeaderTimeout.String(),
+		"keep_alive":              c.KeepAlive.String(),
+		"disable_keep_alives":     c.DisableKeepAlives,
+		"http2":                   c.HTTP2,
+		"ca_file":                 c.CAFile,
+	}
+}
+
+// redactURL oculta la contraseña y los parámetros de una URL
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "invalid URL"
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = "REDACTED"
+	}
+	return u.Redacted()
+}
+
+// redactURLError evita que el error de url.Parse muestre la URL con credenciales
+func redactURLError(err error) error {
+	if urlErr, ok := err.(*url.Error); ok {
+		return urlErr.Err
+	}
+	return err
+}
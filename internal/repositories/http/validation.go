@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/validation"
+)
+
+// WithValidation reemplaza el pipeline de validación por defecto; nil desactiva la validación
+func WithValidation(pipeline *validation.Pipeline) Option {
+	return func(r *HTTPBooksRepository) {
+		r.validation = pipeline
+	}
+}
+
+// quarantineStore guarda los libros rechazados en la última obtención validada
+type quarantineStore struct {
+	mu    sync.RWMutex
+	books []domain.QuarantinedBook
+}
+
+func (q *quarantineStore) replace(books []domain.QuarantinedBook) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.books = books
+}
+
+func (q *quarantineStore) list() []domain.QuarantinedBook {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	result := make([]domain.QuarantinedBook, len(q.books))
+	for i, book := range q.books {
+		book.Reasons = slices.Clone(book.Reasons)
+		result[i] = book
+	}
+	return result
+}
+
+// Quarantined devuelve los libros rechazados por la validación en la última obtención
+func (p *HTTPBooksRepository) Quarantined() []domain.QuarantinedBook {
+	return p.quarantine.list()
+}
+
+// validate aplica el pipeline de validación a los libros; con record se reemplaza la cuarentena,
+// lo que sólo corresponde cuando se obtuvo el catálogo completo
+func (p *HTTPBooksRepository) validate(ctx context.Context, books []domain.Book, record bool) ([]domain.Book, error) {
+	if p.validation == nil {
+		return books, nil
+	}
+
+	result, err := p.validation.Apply(books)
+	if record {
+		p.quarantine.replace(result.Quarantined)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Quarantined) > 0 {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) {
+			info.Warnings = append(info.Warnings, fmt.Sprintf("%d books quarantined by validation", len(result.Quarantined)))
+		})
+	}
+	return result.Books, nil
+}
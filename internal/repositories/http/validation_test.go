@@ -0,0 +1,105 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/validation"
+)
+
+const invalidCatalog = `[
+	{"id": 1, "name": "Valid Book", "author": " Some   Author ", "units_sold": 10, "price": 20},
+	{"id": 2, "name": "", "author": "Author", "units_sold": 10, "price": 20},
+	{"id": 3, "name": "Free Book", "author": "Author", "units_sold": 10, "price": 0}
+]`
+
+func invalidCatalogServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(invalidCatalog))
+	}))
+}
+
+func TestHTTPBooksRepository_Validation_DefaultKeepsInvalidBooks(t *testing.T) {
+	server := invalidCatalogServer()
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL)
+	ctx := domain.WithFetchInfo(context.Background())
+
+	books, err := repository.GetBooks(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 3 {
+		t.Fatalf("Expected 3 books with the keep policy, got %d", len(books))
+	}
+	if books[0].Author != "Some Author" {
+		t.Errorf("Expected normalized author, got %q", books[0].Author)
+	}
+
+	quarantined := repository.Quarantined()
+	if len(quarantined) != 2 || quarantined[0].Book.ID != 2 || quarantined[1].Book.ID != 3 {
+		t.Errorf("Expected books 2 and 3 in quarantine, got %+v", quarantined)
+	}
+	if info, _ := domain.FetchInfoFromContext(ctx); len(info.Warnings) != 1 {
+		t.Errorf("Expected a quarantine warning, got %v", info.Warnings)
+	}
+}
+
+func TestHTTPBooksRepository_Validation_DropPolicy(t *testing.T) {
+	server := invalidCatalogServer()
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithValidation(validation.NewPipeline(validation.PolicyDrop, validation.DefaultRules()...)))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 1 || books[0].ID != 1 {
+		t.Errorf("Expected only the valid book, got %+v", books)
+	}
+	if len(repository.Quarantined()) != 2 {
+		t.Errorf("Expected 2 quarantined books, got %d", len(repository.Quarantined()))
+	}
+}
+
+func TestHTTPBooksRepository_Validation_FailPolicy(t *testing.T) {
+	server := invalidCatalogServer()
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL,
+		WithValidation(validation.NewPipeline(validation.PolicyFail, validation.DefaultRules()...)))
+
+	_, err := repository.GetBooks(context.Background())
+	if !errors.Is(err, domain.ErrMalformedPayload) {
+		t.Fatalf("Expected ErrMalformedPayload, got %v", err)
+	}
+	// La cuarentena se expone aunque la obtención haya fallado
+	if len(repository.Quarantined()) != 2 {
+		t.Errorf("Expected 2 quarantined books, got %d", len(repository.Quarantined()))
+	}
+}
+
+func TestHTTPBooksRepository_Validation_Disabled(t *testing.T) {
+	server := invalidCatalogServer()
+	defer server.Close()
+
+	repository := NewHTTPBooksRepositoryWithConfig(server.Client(), server.URL, WithValidation(nil))
+
+	books, err := repository.GetBooks(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(books) != 3 || books[0].Author != " Some   Author " {
+		t.Errorf("Expected books untouched, got %+v", books)
+	}
+	if len(repository.Quarantined()) != 0 {
+		t.Errorf("Expected empty quarantine, got %+v", repository.Quarantined())
+	}
+}
@@ -1,29 +1,124 @@
 package memory
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
 
 	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/watch"
 )
 
 // MemoryBooksRepository implementa el repositorio de libros en memoria
-type MemoryBooksRepository struct{}
+// Es seguro para uso concurrente y admite escrituras, que se pierden al reiniciar
+// Cada escritura se informa a los suscriptores de Watch
+type MemoryBooksRepository struct {
+	mu     sync.RWMutex
+	books  map[uint]domain.Book
+	events *watch.Broadcaster
+}
 
 // NewMemoryBooksRepository crea una nueva instancia del repositorio de libros en memoria
+// con el catálogo de ejemplo
 func NewMemoryBooksRepository() *MemoryBooksRepository {
-	return &MemoryBooksRepository{}
+	return NewMemoryBooksRepositoryWithBooks([]domain.Book{
+		{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: domain.Price(40)},
+		{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+		{ID: 3, Name: "The Pragmatic Programmer", Author: "Andrew Hunt", UnitsSold: 13000, Price: domain.Price(45)},
+	})
+}
+
+// NewMemoryBooksRepositoryWithBooks crea el repositorio con el catálogo indicado
+// Los libros conservan su versión; si hay IDs repetidos prevalece el último
+func NewMemoryBooksRepositoryWithBooks(books []domain.Book) *MemoryBooksRepository {
+	m := &MemoryBooksRepository{books: make(map[uint]domain.Book, len(books)), events: watch.NewBroadcaster(watch.DefaultBuffer)}
+	for _, book := range books {
+		m.books[book.ID] = book
+	}
+	return m
 }
 
 // GetBooks implementa la interfaz BooksRepository
-// Nota: el contexto se ignora con _ ya que los datos son estáticos
-// En una implementación real, el contexto se usaría para cancelación de operaciones
-func (m *MemoryBooksRepository) GetBooks(_ context.Context) []domain.Book {
-	// Este repositorio en memoria no usa el contexto porque devuelve datos estáticos
-	// En una implementación real, el contexto sería útil para cancelar
-	// operaciones HTTP o de base de datos
-	return []domain.Book{
-		{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: 40},
-		{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: 50},
-		{ID: 3, Name: "The Pragmatic Programmer", Author: "Andrew Hunt", UnitsSold: 13000, Price: 45},
-	}
-}
\ No newline at end of file
+// Los libros se devuelven ordenados por ID; el contexto no se usa porque no hay operaciones de E/S
+func (m *MemoryBooksRepository) GetBooks(_ context.Context) ([]domain.Book, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	books := make([]domain.Book, 0, len(m.books))
+	for _, book := range m.books {
+		books = append(books, book)
+	}
+	slices.SortFunc(books, func(a, b domain.Book) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return books, nil
+}
+
+// CreateBook implementa la interfaz BooksWriter
+func (m *MemoryBooksRepository) CreateBook(_ context.Context, book domain.Book) (domain.Book, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if book.ID == 0 {
+		book.ID = m.nextID()
+	} else if _, exists := m.books[book.ID]; exists {
+		return domain.Book{}, fmt.Errorf("%w: id %d", domain.ErrBookExists, book.ID)
+	}
+	book.Version = 1
+	m.books[book.ID] = book
+	// Publicar con el lock tomado garantiza que los eventos lleguen en el orden de las escrituras
+	m.events.Publish(domain.BookEvent{Type: domain.BookAdded, After: &book, At: time.Now()})
+	return book, nil
+}
+
+// UpdateBook implementa la interfaz BooksWriter
+func (m *MemoryBooksRepository) UpdateBook(_ context.Context, book domain.Book) (domain.Book, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, exists := m.books[book.ID]
+	if !exists {
+		return domain.Book{}, fmt.Errorf("%w: id %d", domain.ErrBookNotFound, book.ID)
+	}
+	if current.Version != book.Version {
+		return domain.Book{}, fmt.Errorf("%w: id %d is at version %d, not %d", domain.ErrVersionConflict, book.ID, current.Version, book.Version)
+	}
+	book.Version++
+	m.books[book.ID] = book
+	m.events.Publish(domain.BookEvent{Type: domain.BookUpdated, Before: &current, After: &book, At: time.Now()})
+	return book, nil
+}
+
+// DeleteBook implementa la interfaz BooksWriter
+func (m *MemoryBooksRepository) DeleteBook(_ context.Context, id uint, version uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, exists := m.books[id]
+	if !exists {
+		return fmt.Errorf("%w: id %d", domain.ErrBookNotFound, id)
+	}
+	if version != 0 && current.Version != version {
+		return fmt.Errorf("%w: id %d is at version %d, not %d", domain.ErrVersionConflict, id, current.Version, version)
+	}
+	delete(m.books, id)
+	m.events.Publish(domain.BookEvent{Type: domain.BookRemoved, Before: &current, At: time.Now()})
+	return nil
+}
+
+// Watch implementa la interfaz WatchableBooksRepository
+func (m *MemoryBooksRepository) Watch(ctx context.Context) <-chan domain.BookEvent {
+	return m.events.Subscribe(ctx)
+}
+
+// nextID devuelve el siguiente ID libre; se llama con el lock tomado
+func (m *MemoryBooksRepository) nextID() uint {
+	var maxID uint
+	for id := range m.books {
+		maxID = max(maxID, id)
+	}
+	return maxID + 1
+}
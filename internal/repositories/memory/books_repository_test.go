@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBooksRepository_DefaultCatalog(t *testing.T) {
+	books, err := NewMemoryBooksRepository().GetBooks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, books, 3)
+	assert.Equal(t, []uint{1, 2, 3}, []uint{books[0].ID, books[1].ID, books[2].ID})
+}
+
+func TestMemoryBooksRepository_CreateUpdateDelete(t *testing.T) {
+	repository := NewMemoryBooksRepository()
+	ctx := context.Background()
+
+	created, err := repository.CreateBook(ctx, domain.Book{Name: "New Book", Author: "Jane Doe", Price: domain.Price(10)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+	assert.Equal(t, uint64(1), created.Version)
+
+	_, err = repository.CreateBook(ctx, domain.Book{ID: 4, Name: "Other", Author: "Someone", Price: domain.Price(1)})
+	assert.ErrorIs(t, err, domain.ErrBookExists)
+
+	created.Price = domain.Price(12)
+	updated, err := repository.UpdateBook(ctx, created)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), updated.Version)
+
+	// Escribir con la versión anterior es un conflicto
+	_, err = repository.UpdateBook(ctx, created)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	assert.ErrorIs(t, repository.DeleteBook(ctx, 4, 1), domain.ErrVersionConflict)
+
+	require.NoError(t, repository.DeleteBook(ctx, 4, 2))
+	assert.ErrorIs(t, repository.DeleteBook(ctx, 4, 0), domain.ErrBookNotFound)
+	_, err = repository.UpdateBook(ctx, updated)
+	assert.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	books, err := repository.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Len(t, books, 3)
+}
+
+func TestMemoryBooksRepository_ConcurrentUpdatesConflict(t *testing.T) {
+	repository := NewMemoryBooksRepositoryWithBooks([]domain.Book{{ID: 1, Name: "Book", Author: "Author", Price: domain.Price(10), Version: 1}})
+	ctx := context.Background()
+
+	// Todas las escrituras parten de la misma versión: sólo una puede ganar
+	var wg sync.WaitGroup
+	results := make([]error, 20)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, results[i] = repository.UpdateBook(ctx, domain.Book{ID: 1, Name: "Book", Author: "Author", Price: domain.Price(uint(i + 1)), Version: 1})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range results {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, domain.ErrVersionConflict)
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+}
+
+func TestMemoryBooksRepository_Watch(t *testing.T) {
+	repository := NewMemoryBooksRepository()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repository.Watch(ctx)
+
+	created, err := repository.CreateBook(ctx, domain.Book{Name: "New Book", Author: "Jane Doe", Price: domain.Price(10)})
+	require.NoError(t, err)
+	created.Price = domain.Price(15)
+	_, err = repository.UpdateBook(ctx, created)
+	require.NoError(t, err)
+	require.NoError(t, repository.DeleteBook(ctx, created.ID, 0))
+
+	added := <-events
+	assert.Equal(t, domain.BookAdded, added.Type)
+	assert.Nil(t, added.Before)
+	assert.Equal(t, uint(4), added.After.ID)
+
+	updated := <-events
+	assert.Equal(t, domain.BookUpdated, updated.Type)
+	assert.Equal(t, domain.Price(10), updated.Before.Price)
+	assert.Equal(t, domain.Price(15), updated.After.Price)
+
+	removed := <-events
+	assert.Equal(t, domain.BookRemoved, removed.Type)
+	assert.Equal(t, uint64(2), removed.Before.Version)
+	assert.Nil(t, removed.After)
+
+	// Las escrituras fallidas no generan eventos
+	assert.Error(t, repository.DeleteBook(ctx, created.ID, 0))
+	cancel()
+	_, open := <-events
+	assert.False(t, open)
+}
@@ -0,0 +1,68 @@
+package sales
+
+import (
+	"context"
+	"slices"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// SalesBooksRepository decora un BooksRepository sumando a UnitsSold las ventas de la proyección
+// El catálogo envuelto aporta las unidades vendidas hasta que empezaron a publicarse los eventos;
+// conviene ubicarlo por encima de la caché para que las ventas se vean apenas se consumen
+type SalesBooksRepository struct {
+	inner      ports.BooksRepository
+	projection *Projection
+}
+
+// NewSalesBooksRepository crea el decorador sobre la proyección indicada
+func NewSalesBooksRepository(inner ports.BooksRepository, projection *Projection) *SalesBooksRepository {
+	return &SalesBooksRepository{
+		inner:      inner,
+		projection: projection,
+	}
+}
+
+// GetBooks devuelve el catálogo envuelto con las ventas de la proyección sumadas
+// Las ventas de libros que no están en el catálogo se ignoran
+func (r *SalesBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	books, err := r.inner.GetBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.addSales(books), nil
+}
+
+// QueryBooks delega la consulta en la fuente y suma las ventas al resultado
+// Las consultas que filtran u ordenan por UnitsSold se resuelven en memoria, porque la fuente
+// no conoce las ventas de la proyección
+func (r *SalesBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if query.MinUnitsSold > 0 || query.MaxUnitsSold > 0 || query.SortBy == domain.SortByUnitsSold {
+		books, err := r.GetBooks(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return query.Apply(books), nil
+	}
+	books, err := ports.QueryBooks(ctx, r.inner, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.addSales(books), nil
+}
+
+// addSales devuelve una copia del catálogo con las ventas de la proyección sumadas
+func (r *SalesBooksRepository) addSales(books []domain.Book) []domain.Book {
+	sold := r.projection.Sold()
+	if len(sold) == 0 {
+		return books
+	}
+
+	// Trabajar sobre una copia: el repositorio envuelto puede compartir su slice (por ejemplo una caché)
+	books = slices.Clone(books)
+	for i := range books {
+		books[i].UnitsSold += uint(sold[books[i].ID])
+	}
+	return books
+}
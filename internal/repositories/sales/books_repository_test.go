@@ -0,0 +1,95 @@
+package sales
+
+import (
+	"context"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBooksRepository devuelve siempre el mismo slice, como una caché
+type stubBooksRepository struct {
+	books []domain.Book
+	err   error
+}
+
+func (s *stubBooksRepository) GetBooks(context.Context) ([]domain.Book, error) {
+	return s.books, s.err
+}
+
+func TestSalesBooksRepository_AddsProjectedSales(t *testing.T) {
+	inner := &stubBooksRepository{books: []domain.Book{
+		{ID: 1, Name: "Book 1", Author: "Author", UnitsSold: 100, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author", UnitsSold: 50, Price: domain.Price(20)},
+	}}
+	projection := NewProjection(0)
+	projection.Apply(domain.BookSold{EventID: "e1", BookID: 1, Qty: 5})
+	projection.Apply(domain.BookSold{EventID: "e2", BookID: 99, Qty: 5})
+	repository := NewSalesBooksRepository(inner, projection)
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint(105), books[0].UnitsSold)
+	assert.Equal(t, uint(50), books[1].UnitsSold)
+
+	// El slice del repositorio envuelto no se modifica
+	assert.Equal(t, uint(100), inner.books[0].UnitsSold)
+}
+
+func TestSalesBooksRepository_PropagatesErrors(t *testing.T) {
+	inner := &stubBooksRepository{err: domain.ErrUpstreamUnavailable}
+	repository := NewSalesBooksRepository(inner, NewProjection(0))
+
+	_, err := repository.GetBooks(context.Background())
+	assert.ErrorIs(t, err, domain.ErrUpstreamUnavailable)
+}
+
+func TestPublisher_PublishesSale(t *testing.T) {
+	broker := NewMemoryBroker()
+	publisher := NewPublisher(broker, "")
+
+	require.NoError(t, publisher.PublishSale(context.Background(), domain.BookSold{EventID: "e1", BookID: 7, Qty: 1}))
+	assert.ErrorIs(t, publisher.PublishSale(context.Background(), domain.BookSold{BookID: 7, Qty: 1}), domain.ErrInvalidSale)
+
+	messages := broker.Messages(DefaultTopic)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "7", string(messages[0].Key))
+	assert.JSONEq(t, `{"event_id":"e1","book_id":7,"qty":1,"ts":"0001-01-01T00:00:00Z"}`, string(messages[0].Value))
+}
+
+// queryableBooksRepository filtra en la fuente y registra las consultas recibidas
+type queryableBooksRepository struct {
+	stubBooksRepository
+	queries []domain.BooksQuery
+}
+
+func (q *queryableBooksRepository) QueryBooks(_ context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	q.queries = append(q.queries, query)
+	return query.Apply(q.books), nil
+}
+
+func TestSalesBooksRepository_QueryBooks(t *testing.T) {
+	inner := &queryableBooksRepository{stubBooksRepository: stubBooksRepository{books: []domain.Book{
+		{ID: 1, Name: "Book 1", Author: "Author 1", UnitsSold: 100, Price: domain.Price(10)},
+		{ID: 2, Name: "Book 2", Author: "Author 2", UnitsSold: 120, Price: domain.Price(20)},
+	}}}
+	projection := NewProjection(0)
+	projection.Apply(domain.BookSold{EventID: "e1", BookID: 1, Qty: 50})
+	repository := NewSalesBooksRepository(inner, projection)
+
+	// El filtro por autor se ejecuta en la fuente y el resultado incluye las ventas
+	books, err := repository.QueryBooks(context.Background(), domain.BooksQuery{Author: "Author 1"})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	assert.Equal(t, uint(150), books[0].UnitsSold)
+	assert.Len(t, inner.queries, 1)
+
+	// Ordenar por unidades vendidas requiere las ventas, así que se resuelve en memoria
+	books, err = repository.QueryBooks(context.Background(), domain.BooksQuery{SortBy: domain.SortByUnitsSold, Descending: true, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+	assert.Equal(t, uint(1), books[0].ID)
+	assert.Len(t, inner.queries, 1)
+}
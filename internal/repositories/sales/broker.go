@@ -0,0 +1,134 @@
+// Package sales mantiene UnitsSold al día consumiendo los eventos de venta de un broker de mensajes
+package sales
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrBrokerClosed indica que el broker se cerró
+var ErrBrokerClosed = errors.New("broker closed")
+
+// Message es un mensaje de un topic, identificado por su offset como en Kafka
+type Message struct {
+	Topic   string            `json:"topic"`
+	Offset  int64             `json:"offset"`
+	Key     []byte            `json:"key,omitempty"`
+	Value   []byte            `json:"value"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Broker es la parte de un broker de mensajes con log persistente (Kafka, Redpanda o NATS JetStream)
+// que necesita el consumidor; los offsets de cada topic empiezan en 0 y son consecutivos
+type Broker interface {
+	// Publish agrega el mensaje al final del topic y devuelve el offset asignado
+	Publish(ctx context.Context, topic string, message Message) (int64, error)
+	// Fetch devuelve hasta limit mensajes a partir de offset, esperando mientras no haya ninguno
+	// Devuelve el error del contexto si se cancela antes de que llegue un mensaje
+	Fetch(ctx context.Context, topic string, offset int64, limit int) ([]Message, error)
+}
+
+// MemoryBroker es un broker en memoria para pruebas y para correr el servicio sin infraestructura
+// Conserva todos los mensajes, por lo que un consumidor puede volver a leer desde cualquier offset
+type MemoryBroker struct {
+	mu     sync.Mutex
+	topics map[string][]Message
+	// notify se cierra y reemplaza con cada publicación para despertar a los Fetch en espera
+	notify chan struct{}
+	closed bool
+	now    func() time.Time
+}
+
+// NewMemoryBroker crea un broker en memoria vacío
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		topics: map[string][]Message{},
+		notify: make(chan struct{}),
+		now:    time.Now,
+	}
+}
+
+// Publish implementa la interfaz Broker
+func (b *MemoryBroker) Publish(ctx context.Context, topic string, message Message) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, ErrBrokerClosed
+	}
+
+	message.Topic = topic
+	message.Offset = int64(len(b.topics[topic]))
+	message.Value = slices.Clone(message.Value)
+	message.Key = slices.Clone(message.Key)
+	if message.Time.IsZero() {
+		message.Time = b.now()
+	}
+	b.topics[topic] = append(b.topics[topic], message)
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+	return message.Offset, nil
+}
+
+// Fetch implementa la interfaz Broker
+func (b *MemoryBroker) Fetch(ctx context.Context, topic string, offset int64, limit int) ([]Message, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d", offset)
+	}
+	limit = max(limit, 1)
+	for {
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return nil, ErrBrokerClosed
+		}
+		messages := b.topics[topic]
+		if offset < int64(len(messages)) {
+			end := min(offset+int64(limit), int64(len(messages)))
+			batch := slices.Clone(messages[offset:end])
+			b.mu.Unlock()
+			return batch, nil
+		}
+		notify := b.notify
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// EndOffset devuelve el offset que tendrá el próximo mensaje del topic
+func (b *MemoryBroker) EndOffset(topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int64(len(b.topics[topic]))
+}
+
+// Messages devuelve una copia de todos los mensajes del topic, por ejemplo para revisar la cola de mensajes fallidos
+func (b *MemoryBroker) Messages(topic string) []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.topics[topic])
+}
+
+// Close despierta a los Fetch en espera y rechaza las operaciones posteriores
+func (b *MemoryBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.notify)
+}
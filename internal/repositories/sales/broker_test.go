@@ -0,0 +1,70 @@
+package sales
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryBroker_PublishAndFetch(t *testing.T) {
+	broker := NewMemoryBroker()
+	ctx := context.Background()
+
+	for i, value := range []string{"a", "b", "c"} {
+		offset, err := broker.Publish(ctx, "sales", Message{Value: []byte(value)})
+		require.NoError(t, err)
+		assert.Equal(t, int64(i), offset)
+	}
+
+	batch, err := broker.Fetch(ctx, "sales", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, batch, 2)
+	assert.Equal(t, "b", string(batch[0].Value))
+	assert.Equal(t, int64(2), batch[1].Offset)
+	assert.Equal(t, "sales", batch[1].Topic)
+	assert.False(t, batch[1].Time.IsZero())
+
+	// El límite se respeta y los topics son independientes
+	batch, err = broker.Fetch(ctx, "sales", 0, 1)
+	require.NoError(t, err)
+	assert.Len(t, batch, 1)
+	assert.Equal(t, int64(3), broker.EndOffset("sales"))
+	assert.Equal(t, int64(0), broker.EndOffset("other"))
+}
+
+func TestMemoryBroker_FetchWaitsForMessages(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		_, _ = broker.Publish(context.Background(), "sales", Message{Value: []byte("late")})
+	})
+	batch, err := broker.Fetch(context.Background(), "sales", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, batch, 1)
+	assert.Equal(t, "late", string(batch[0].Value))
+
+	// Sin mensajes, Fetch termina al cancelar el contexto
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = broker.Fetch(ctx, "sales", 1, 10)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMemoryBroker_Close(t *testing.T) {
+	broker := NewMemoryBroker()
+
+	done := make(chan error)
+	go func() {
+		_, err := broker.Fetch(context.Background(), "sales", 0, 10)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	broker.Close()
+	assert.ErrorIs(t, <-done, ErrBrokerClosed)
+
+	_, err := broker.Publish(context.Background(), "sales", Message{})
+	assert.ErrorIs(t, err, ErrBrokerClosed)
+}
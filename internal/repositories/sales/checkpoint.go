@@ -0,0 +1,126 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// CheckpointStore guarda el estado de la proyección para retomar el consumo tras un reinicio
+type CheckpointStore interface {
+	// Load devuelve el último estado guardado, o un estado vacío si no hay ninguno
+	Load(ctx context.Context) (State, error)
+	// Save reemplaza el estado guardado
+	Save(ctx context.Context, state State) error
+}
+
+// MemoryCheckpointStore guarda el estado en memoria; sirve para pruebas o si se acepta releer el topic al reiniciar
+type MemoryCheckpointStore struct {
+	mu    sync.Mutex
+	state State
+	saves int
+}
+
+// NewMemoryCheckpointStore crea un almacén en memoria vacío
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load implementa la interfaz CheckpointStore
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyState(s.state), nil
+}
+
+// Save implementa la interfaz CheckpointStore
+func (s *MemoryCheckpointStore) Save(ctx context.Context, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = copyState(state)
+	s.saves++
+	return nil
+}
+
+// Saves devuelve la cantidad de checkpoints guardados
+func (s *MemoryCheckpointStore) Saves() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saves
+}
+
+func copyState(state State) State {
+	return State{Offset: state.Offset, Sold: maps.Clone(state.Sold), Seen: slices.Clone(state.Seen)}
+}
+
+// FileCheckpointStore guarda el estado como JSON en un archivo
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore crea un almacén que guarda el estado en path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implementa la interfaz CheckpointStore
+func (s *FileCheckpointStore) Load(ctx context.Context) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return State{}, nil
+	}
+	if err != nil {
+		return State{}, fmt.Errorf("reading sales checkpoint: %w", err)
+	}
+	var state State
+	if err := json.Unmarshal(content, &state); err != nil {
+		return State{}, fmt.Errorf("decoding sales checkpoint %s: %w", s.path, err)
+	}
+	return state, nil
+}
+
+// Save implementa la interfaz CheckpointStore
+// Escribe en un archivo temporal y lo renombra para no dejar checkpoints a medio escribir
+func (s *FileCheckpointStore) Save(ctx context.Context, state State) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encoding sales checkpoint: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating sales checkpoint dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-"+filepath.Base(s.path)+"*")
+	if err != nil {
+		return fmt.Errorf("creating sales checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing sales checkpoint: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing sales checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing sales checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("publishing sales checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Topics por defecto de los eventos de venta y de los mensajes que no pudieron aplicarse
+const (
+	DefaultTopic           = "bookshop.sales"
+	DefaultDeadLetterTopic = "bookshop.sales.dlq"
+)
+
+// Headers que se agregan a los mensajes enviados a la cola de mensajes fallidos
+const (
+	HeaderDeadLetterError  = "dlq-error"
+	HeaderDeadLetterTopic  = "dlq-topic"
+	HeaderDeadLetterOffset = "dlq-offset"
+)
+
+// Config define cómo se consumen los eventos de venta
+type Config struct {
+	Topic string
+	// DeadLetterTopic recibe los mensajes que no son un BookSold válido ("" los descarta)
+	DeadLetterTopic string
+	// BatchSize es la cantidad máxima de mensajes por lectura; el checkpoint se guarda después de cada lote
+	BatchSize int
+	// RetryDelay es la espera antes de volver a leer tras un error del broker
+	RetryDelay time.Duration
+	// OnError, si no es nil, recibe los errores del broker, de los checkpoints y los mensajes descartados
+	OnError func(error)
+}
+
+// DefaultConfig devuelve la configuración por defecto
+func DefaultConfig() Config {
+	return Config{
+		Topic:           DefaultTopic,
+		DeadLetterTopic: DefaultDeadLetterTopic,
+		BatchSize:       100,
+		RetryDelay:      time.Second,
+	}
+}
+
+// ConsumerStats resume la actividad del consumidor desde que arrancó
+type ConsumerStats struct {
+	// Offset es el próximo offset a leer
+	Offset int64 `json:"offset"`
+	// Applied es la cantidad de eventos sumados a la proyección
+	Applied uint64 `json:"applied"`
+	// Duplicates es la cantidad de eventos descartados por haberse aplicado antes
+	Duplicates uint64 `json:"duplicates"`
+	// DeadLettered es la cantidad de mensajes enviados a la cola de mensajes fallidos
+	DeadLettered uint64 `json:"dead_lettered"`
+	// LastEventAt es la fecha del evento aplicado más reciente
+	LastEventAt time.Time `json:"last_event_at,omitempty"`
+}
+
+// Consumer lee los eventos BookSold del broker y los aplica a la proyección
+// Cada evento se aplica una sola vez aunque el broker lo entregue de nuevo: los repetidos se
+// reconocen por su ID, y tras un reinicio se retoma desde el offset del último checkpoint
+type Consumer struct {
+	broker      Broker
+	projection  *Projection
+	checkpoints CheckpointStore
+	config      Config
+
+	mu    sync.Mutex
+	stats ConsumerStats
+}
+
+// NewConsumer crea el consumidor; Run empieza a consumir
+func NewConsumer(broker Broker, projection *Projection, checkpoints CheckpointStore, config Config) *Consumer {
+	defaults := DefaultConfig()
+	if config.Topic == "" {
+		config.Topic = defaults.Topic
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaults.RetryDelay
+	}
+	return &Consumer{
+		broker:      broker,
+		projection:  projection,
+		checkpoints: checkpoints,
+		config:      config,
+	}
+}
+
+// Stats devuelve una copia de las estadísticas del consumidor
+func (c *Consumer) Stats() ConsumerStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Offset = c.projection.Offset()
+	return stats
+}
+
+// Run restaura la proyección desde el último checkpoint y consume hasta que se cancele ctx
+// Los errores del broker se reintentan; sólo devuelve un error si no puede leer el checkpoint
+// o si el broker se cierra
+func (c *Consumer) Run(ctx context.Context) error {
+	state, err := c.checkpoints.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("loading sales checkpoint: %w", err)
+	}
+	c.projection.Restore(state)
+
+	for {
+		batch, err := c.broker.Fetch(ctx, c.config.Topic, c.projection.Offset(), c.config.BatchSize)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Is(err, ErrBrokerClosed) {
+			return err
+		}
+		if err != nil {
+			c.report(fmt.Errorf("fetching sales events: %w", err))
+			if !c.wait(ctx) {
+				return nil
+			}
+			continue
+		}
+
+		processed, err := c.process(ctx, batch)
+		if processed > 0 {
+			if err := c.checkpoints.Save(ctx, c.projection.State()); err != nil {
+				c.report(fmt.Errorf("saving sales checkpoint: %w", err))
+			}
+		}
+		if err != nil {
+			c.report(err)
+			if !c.wait(ctx) {
+				return nil
+			}
+		}
+	}
+}
+
+// process aplica los mensajes en orden y devuelve cuántos procesó
+// Se detiene en el primer mensaje que no pudo procesarse para volver a intentarlo en la próxima lectura
+func (c *Consumer) process(ctx context.Context, batch []Message) (int, error) {
+	for i, message := range batch {
+		if err := c.handle(ctx, message); err != nil {
+			return i, err
+		}
+		c.projection.advance(message.Offset + 1)
+	}
+	return len(batch), nil
+}
+
+// handle aplica un mensaje; los que no son un BookSold válido van a la cola de mensajes fallidos
+func (c *Consumer) handle(ctx context.Context, message Message) error {
+	var event domain.BookSold
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return c.deadLetter(ctx, message, fmt.Errorf("%w: decoding event: %w", domain.ErrInvalidSale, err))
+	}
+	if err := event.Validate(); err != nil {
+		return c.deadLetter(ctx, message, err)
+	}
+
+	applied := c.projection.Apply(event)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !applied {
+		c.stats.Duplicates++
+		return nil
+	}
+	c.stats.Applied++
+	if event.Timestamp.After(c.stats.LastEventAt) {
+		c.stats.LastEventAt = event.Timestamp
+	}
+	return nil
+}
+
+// deadLetter publica el mensaje en la cola de mensajes fallidos con el motivo en los headers
+// Si la publicación falla el mensaje no se da por procesado
+func (c *Consumer) deadLetter(ctx context.Context, message Message, cause error) error {
+	c.report(fmt.Errorf("sales event at offset %d rejected: %w", message.Offset, cause))
+	if c.config.DeadLetterTopic != "" {
+		headers := maps.Clone(message.Headers)
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		headers[HeaderDeadLetterError] = cause.Error()
+		headers[HeaderDeadLetterTopic] = message.Topic
+		headers[HeaderDeadLetterOffset] = strconv.FormatInt(message.Offset, 10)
+		deadLetter := Message{Key: message.Key, Value: message.Value, Headers: headers}
+		if _, err := c.broker.Publish(ctx, c.config.DeadLetterTopic, deadLetter); err != nil {
+			return fmt.Errorf("publishing sales event at offset %d to %s: %w", message.Offset, c.config.DeadLetterTopic, err)
+		}
+	}
+
+	c.mu.Lock()
+	c.stats.DeadLettered++
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Consumer) report(err error) {
+	if c.config.OnError != nil {
+		c.config.OnError(err)
+	}
+}
+
+// wait espera RetryDelay; devuelve false si ctx se canceló antes
+func (c *Consumer) wait(ctx context.Context) bool {
+	timer := time.NewTimer(c.config.RetryDelay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,227 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func publishSale(t *testing.T, broker Broker, event domain.BookSold) {
+	t.Helper()
+	value, err := json.Marshal(event)
+	require.NoError(t, err)
+	_, err = broker.Publish(context.Background(), DefaultTopic, Message{Value: value})
+	require.NoError(t, err)
+}
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.RetryDelay = 5 * time.Millisecond
+	return config
+}
+
+// runConsumer corre el consumidor en segundo plano; la función devuelta lo detiene y devuelve el error de Run
+func runConsumer(consumer *Consumer) func() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+// waitForOffset espera a que el consumidor procese los mensajes anteriores a offset
+func waitForOffset(t *testing.T, consumer *Consumer, offset int64) {
+	t.Helper()
+	require.Eventually(t, func() bool { return consumer.Stats().Offset >= offset }, time.Second, time.Millisecond)
+}
+
+func TestConsumer_AppliesSales(t *testing.T) {
+	broker := NewMemoryBroker()
+	projection := NewProjection(0)
+	consumer := NewConsumer(broker, projection, NewMemoryCheckpointStore(), testConfig())
+	stop := runConsumer(consumer)
+	defer stop()
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	publishSale(t, broker, domain.BookSold{EventID: "e1", BookID: 1, Qty: 2, Timestamp: at})
+	publishSale(t, broker, domain.BookSold{EventID: "e2", BookID: 1, Qty: 3, Timestamp: at.Add(time.Minute)})
+	publishSale(t, broker, domain.BookSold{EventID: "e3", BookID: 2, Qty: 1, Timestamp: at})
+	waitForOffset(t, consumer, 3)
+
+	assert.Equal(t, uint64(5), projection.UnitsSold(1))
+	assert.Equal(t, uint64(1), projection.UnitsSold(2))
+	stats := consumer.Stats()
+	assert.Equal(t, uint64(3), stats.Applied)
+	assert.Equal(t, at.Add(time.Minute), stats.LastEventAt)
+}
+
+func TestConsumer_IgnoresDuplicateEvents(t *testing.T) {
+	broker := NewMemoryBroker()
+	projection := NewProjection(0)
+	consumer := NewConsumer(broker, projection, NewMemoryCheckpointStore(), testConfig())
+	stop := runConsumer(consumer)
+	defer stop()
+
+	// El productor reintentó y el broker entregó la misma venta dos veces
+	event := domain.BookSold{EventID: "e1", BookID: 1, Qty: 2, Timestamp: time.Now()}
+	publishSale(t, broker, event)
+	publishSale(t, broker, event)
+	waitForOffset(t, consumer, 2)
+
+	assert.Equal(t, uint64(2), projection.UnitsSold(1))
+	assert.Equal(t, uint64(1), consumer.Stats().Duplicates)
+}
+
+func TestConsumer_DeadLettersInvalidMessages(t *testing.T) {
+	broker := NewMemoryBroker()
+	projection := NewProjection(0)
+	var mu sync.Mutex
+	var reported []error
+	config := testConfig()
+	config.OnError = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+	consumer := NewConsumer(broker, projection, NewMemoryCheckpointStore(), config)
+	stop := runConsumer(consumer)
+	defer stop()
+
+	_, err := broker.Publish(context.Background(), DefaultTopic, Message{Value: []byte("not json"), Headers: map[string]string{"source": "pos"}})
+	require.NoError(t, err)
+	publishSale(t, broker, domain.BookSold{EventID: "e1", BookID: 1})
+	publishSale(t, broker, domain.BookSold{EventID: "e2", BookID: 1, Qty: 1})
+	waitForOffset(t, consumer, 3)
+
+	// Los mensajes inválidos no frenan a los siguientes
+	assert.Equal(t, uint64(1), projection.UnitsSold(1))
+	deadLetters := broker.Messages(DefaultDeadLetterTopic)
+	require.Len(t, deadLetters, 2)
+	assert.Equal(t, "not json", string(deadLetters[0].Value))
+	assert.Equal(t, "pos", deadLetters[0].Headers["source"])
+	assert.Equal(t, DefaultTopic, deadLetters[0].Headers[HeaderDeadLetterTopic])
+	assert.Equal(t, "0", deadLetters[0].Headers[HeaderDeadLetterOffset])
+	assert.Contains(t, deadLetters[1].Headers[HeaderDeadLetterError], "qty must be positive")
+	assert.Equal(t, uint64(2), consumer.Stats().DeadLettered)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reported, 2)
+	assert.ErrorIs(t, reported[1], domain.ErrInvalidSale)
+}
+
+// failingDeadLetterBroker rechaza las publicaciones en la cola de mensajes fallidos mientras failing sea true
+type failingDeadLetterBroker struct {
+	*MemoryBroker
+	mu      sync.Mutex
+	failing bool
+}
+
+func (b *failingDeadLetterBroker) Publish(ctx context.Context, topic string, message Message) (int64, error) {
+	b.mu.Lock()
+	failing := b.failing && topic == DefaultDeadLetterTopic
+	b.mu.Unlock()
+	if failing {
+		return 0, errors.New("dead letter topic unavailable")
+	}
+	return b.MemoryBroker.Publish(ctx, topic, message)
+}
+
+func (b *failingDeadLetterBroker) recover() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failing = false
+}
+
+func TestConsumer_RetriesWhenDeadLetterFails(t *testing.T) {
+	broker := &failingDeadLetterBroker{MemoryBroker: NewMemoryBroker(), failing: true}
+	projection := NewProjection(0)
+	consumer := NewConsumer(broker, projection, NewMemoryCheckpointStore(), testConfig())
+	stop := runConsumer(consumer)
+	defer stop()
+
+	publishSale(t, broker, domain.BookSold{EventID: "e1", BookID: 1, Qty: 1})
+	publishSale(t, broker, domain.BookSold{EventID: "bad", BookID: 1})
+	publishSale(t, broker, domain.BookSold{EventID: "e2", BookID: 1, Qty: 1})
+
+	// El mensaje inválido no se da por procesado hasta poder enviarlo a la cola de mensajes fallidos
+	waitForOffset(t, consumer, 1)
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int64(1), consumer.Stats().Offset)
+	assert.Equal(t, uint64(1), projection.UnitsSold(1))
+
+	broker.recover()
+	waitForOffset(t, consumer, 3)
+	assert.Equal(t, uint64(2), projection.UnitsSold(1))
+	assert.Len(t, broker.Messages(DefaultDeadLetterTopic), 1)
+}
+
+func TestConsumer_ResumesFromCheckpoint(t *testing.T) {
+	broker := NewMemoryBroker()
+	checkpoints := NewFileCheckpointStore(filepath.Join(t.TempDir(), "sales", "checkpoint.json"))
+
+	first := NewConsumer(broker, NewProjection(0), checkpoints, testConfig())
+	stop := runConsumer(first)
+	for i := 1; i <= 3; i++ {
+		publishSale(t, broker, domain.BookSold{EventID: fmt.Sprintf("e%d", i), BookID: 1, Qty: 1})
+	}
+	waitForOffset(t, first, 3)
+	require.NoError(t, stop())
+
+	// Tras el reinicio la proyección se restaura y sólo se leen los eventos nuevos,
+	// incluida una repetición de un evento ya aplicado
+	publishSale(t, broker, domain.BookSold{EventID: "e3", BookID: 1, Qty: 1})
+	publishSale(t, broker, domain.BookSold{EventID: "e4", BookID: 1, Qty: 1})
+	projection := NewProjection(0)
+	second := NewConsumer(broker, projection, checkpoints, testConfig())
+	stop = runConsumer(second)
+	defer stop()
+	waitForOffset(t, second, 5)
+
+	assert.Equal(t, uint64(4), projection.UnitsSold(1))
+	assert.Equal(t, uint64(1), second.Stats().Applied)
+	assert.Equal(t, uint64(1), second.Stats().Duplicates)
+
+	state, err := checkpoints.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), state.Offset)
+	assert.Equal(t, map[uint]uint64{1: 4}, state.Sold)
+}
+
+func TestConsumer_StopsWhenBrokerCloses(t *testing.T) {
+	broker := NewMemoryBroker()
+	consumer := NewConsumer(broker, NewProjection(0), NewMemoryCheckpointStore(), testConfig())
+
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(context.Background()) }()
+	broker.Close()
+	assert.ErrorIs(t, <-done, ErrBrokerClosed)
+}
+
+func TestProjection_DedupWindow(t *testing.T) {
+	projection := NewProjection(2)
+	assert.True(t, projection.Apply(domain.BookSold{EventID: "e1", BookID: 1, Qty: 1}))
+	assert.True(t, projection.Apply(domain.BookSold{EventID: "e2", BookID: 1, Qty: 1}))
+	assert.False(t, projection.Apply(domain.BookSold{EventID: "e1", BookID: 1, Qty: 1}))
+	assert.True(t, projection.Apply(domain.BookSold{EventID: "e3", BookID: 1, Qty: 1}))
+
+	// e1 salió de la ventana y el estado sólo guarda los IDs recordados
+	state := projection.State()
+	assert.Equal(t, []string{"e2", "e3"}, state.Seen)
+
+	restored := NewProjection(2)
+	restored.Restore(state)
+	assert.False(t, restored.Apply(domain.BookSold{EventID: "e3", BookID: 1, Qty: 1}))
+	assert.Equal(t, uint64(3), restored.UnitsSold(1))
+}
@@ -0,0 +1,125 @@
+package sales
+
+import (
+	"maps"
+	"slices"
+	"sync"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// DefaultDedupWindow es la cantidad de IDs de eventos recordados por defecto para descartar repeticiones
+const DefaultDedupWindow = 100_000
+
+// State es el estado de la proyección que se guarda en cada checkpoint
+// Offset y ventas se guardan juntos para que, al reiniciar, el consumidor siga justo después
+// del último evento incluido en las ventas y no cuente ninguno dos veces
+type State struct {
+	// Offset es el próximo offset a leer del topic
+	Offset int64 `json:"offset"`
+	// Sold son las unidades vendidas por ID de libro según los eventos aplicados
+	Sold map[uint]uint64 `json:"sold"`
+	// Seen son los IDs de los últimos eventos aplicados, del más antiguo al más reciente
+	Seen []string `json:"seen"`
+}
+
+// Projection acumula las ventas por libro aplicando cada evento una única vez
+// Los IDs de eventos se recuerdan en una ventana acotada: una repetición más antigua
+// que la ventana ya quedó cubierta por el offset del checkpoint
+type Projection struct {
+	mu     sync.RWMutex
+	window int
+	offset int64
+	sold   map[uint]uint64
+	seen   map[string]struct{}
+	order  []string
+}
+
+// NewProjection crea una proyección vacía que recuerda window IDs de eventos (DefaultDedupWindow si es 0)
+func NewProjection(window int) *Projection {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	return &Projection{
+		window: window,
+		sold:   map[uint]uint64{},
+		seen:   map[string]struct{}{},
+	}
+}
+
+// Apply suma la venta a la proyección; devuelve false si el evento ya se había aplicado
+func (p *Projection) Apply(event domain.BookSold) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.seen[event.EventID]; ok {
+		return false
+	}
+	p.sold[event.BookID] += uint64(event.Qty)
+	p.remember(event.EventID)
+	return true
+}
+
+// remember agrega el ID a la ventana descartando el más antiguo si está llena
+func (p *Projection) remember(eventID string) {
+	p.seen[eventID] = struct{}{}
+	p.order = append(p.order, eventID)
+	if len(p.order) > p.window {
+		delete(p.seen, p.order[0])
+		p.order = p.order[1:]
+	}
+}
+
+// UnitsSold devuelve las unidades vendidas del libro según los eventos aplicados
+func (p *Projection) UnitsSold(bookID uint) uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.sold[bookID]
+}
+
+// Sold devuelve una copia de las unidades vendidas por libro
+func (p *Projection) Sold() map[uint]uint64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return maps.Clone(p.sold)
+}
+
+// Offset devuelve el próximo offset a leer
+func (p *Projection) Offset() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.offset
+}
+
+// advance registra que se procesaron los mensajes anteriores a offset
+func (p *Projection) advance(offset int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.offset = max(p.offset, offset)
+}
+
+// State devuelve una copia del estado para guardarlo en un checkpoint
+func (p *Projection) State() State {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return State{
+		Offset: p.offset,
+		Sold:   maps.Clone(p.sold),
+		Seen:   slices.Clone(p.order),
+	}
+}
+
+// Restore reemplaza el estado de la proyección por el de un checkpoint
+func (p *Projection) Restore(state State) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.offset = state.Offset
+	p.sold = maps.Clone(state.Sold)
+	if p.sold == nil {
+		p.sold = map[uint]uint64{}
+	}
+	p.seen = map[string]struct{}{}
+	p.order = nil
+	for _, eventID := range state.Seen {
+		p.remember(eventID)
+	}
+}
@@ -0,0 +1,40 @@
+package sales
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// Publisher publica eventos BookSold en el topic de ventas, con el ID del libro como clave
+// para que un broker particionado conserve el orden de las ventas de cada libro
+type Publisher struct {
+	broker Broker
+	topic  string
+}
+
+// NewPublisher crea un publicador sobre el topic indicado (DefaultTopic si es "")
+func NewPublisher(broker Broker, topic string) *Publisher {
+	if topic == "" {
+		topic = DefaultTopic
+	}
+	return &Publisher{broker: broker, topic: topic}
+}
+
+// PublishSale implementa la interfaz SalesPublisher
+func (p *Publisher) PublishSale(ctx context.Context, event domain.BookSold) error {
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encoding sale event: %w", err)
+	}
+	key := []byte(fmt.Sprint(event.BookID))
+	if _, err := p.broker.Publish(ctx, p.topic, Message{Key: key, Value: value}); err != nil {
+		return fmt.Errorf("publishing sale event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/repositories/watch"
+
+	// Driver de SQLite en Go puro (no requiere cgo)
+	_ "modernc.org/sqlite"
+)
+
+const driverName = "sqlite"
+
+// SQLiteBooksRepository implementa el repositorio de libros sobre una base SQLite local
+// Las escrituras hechas a través del repositorio se informan a los suscriptores de Watch
+type SQLiteBooksRepository struct {
+	db     *sql.DB
+	events *watch.Broadcaster
+}
+
+// Open abre (o crea) la base de datos en path, aplica las migraciones pendientes
+// y devuelve el repositorio listo para usar. Con ":memory:" se usa una base en memoria
+func Open(ctx context.Context, path string) (*SQLiteBooksRepository, error) {
+	dsn := path
+	if path != ":memory:" {
+		dsn = "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+	// Una única conexión evita errores de bloqueo y hace que ":memory:" sea una sola base
+	db.SetMaxOpenConns(1)
+
+	if _, err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return NewSQLiteBooksRepository(db), nil
+}
+
+// NewSQLiteBooksRepository crea el repositorio sobre una conexión ya migrada
+func NewSQLiteBooksRepository(db *sql.DB) *SQLiteBooksRepository {
+	return &SQLiteBooksRepository{db: db, events: watch.NewBroadcaster(watch.DefaultBuffer)}
+}
+
+// Close cierra la conexión a la base de datos y los canales de los suscriptores
+func (r *SQLiteBooksRepository) Close() error {
+	r.events.Close()
+	return r.db.Close()
+}
+
+// Watch implementa la interfaz WatchableBooksRepository
+// Sólo se informan las escrituras hechas por este proceso, no las de otros procesos sobre el mismo archivo
+func (r *SQLiteBooksRepository) Watch(ctx context.Context) <-chan domain.BookEvent {
+	return r.events.Subscribe(ctx)
+}
+
+// GetBooks implementa la interfaz BooksRepository
+func (r *SQLiteBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	return queryBooks(ctx, r.db, `SELECT id, name, author, units_sold, price, currency, version FROM books ORDER BY id`)
+}
+
+// sortColumns traduce los campos de orden de la consulta a columnas de la tabla
+// El precio se ordena primero por moneda, igual que domain.CompareMoney
+var sortColumns = map[domain.SortField][]string{
+	"":                     {"id"},
+	domain.SortByID:        {"id"},
+	domain.SortByName:      {"name"},
+	domain.SortByAuthor:    {"author"},
+	domain.SortByUnitsSold: {"units_sold"},
+	domain.SortByPrice:     {"currency", "price"},
+}
+
+// QueryBooks implementa la interfaz QueryableBooksRepository ejecutando el filtro en SQL
+func (r *SQLiteBooksRepository) QueryBooks(ctx context.Context, query domain.BooksQuery) ([]domain.Book, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []any
+	if query.Author != "" {
+		conditions = append(conditions, "author = ?")
+		args = append(args, query.Author)
+	}
+	// Los precios sólo se comparan dentro de la moneda del filtro
+	if !query.MinPrice.IsZero() {
+		conditions = append(conditions, "currency = ? AND price >= ?")
+		args = append(args, query.MinPrice.Currency, query.MinPrice.Amount)
+	}
+	if !query.MaxPrice.IsZero() {
+		conditions = append(conditions, "currency = ? AND price <= ?")
+		args = append(args, query.MaxPrice.Currency, query.MaxPrice.Amount)
+	}
+	if query.MinUnitsSold > 0 {
+		conditions = append(conditions, "units_sold >= ?")
+		args = append(args, query.MinUnitsSold)
+	}
+	if query.MaxUnitsSold > 0 {
+		conditions = append(conditions, "units_sold <= ?")
+		args = append(args, query.MaxUnitsSold)
+	}
+	if len(query.IDs) > 0 {
+		conditions = append(conditions, "id IN (?"+strings.Repeat(", ?", len(query.IDs)-1)+")")
+		for _, id := range query.IDs {
+			args = append(args, id)
+		}
+	}
+
+	statement := `SELECT id, name, author, units_sold, price, currency, version FROM books`
+	if len(conditions) > 0 {
+		statement += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	direction := "ASC"
+	if query.Descending {
+		direction = "DESC"
+	}
+	// El ID desempata en el mismo sentido que el orden principal, igual que BooksQuery.Apply
+	var order []string
+	for _, column := range append(slices.Clip(sortColumns[query.SortBy]), "id") {
+		order = append(order, column+" "+direction)
+	}
+	statement += " ORDER BY " + strings.Join(order, ", ")
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+	}
+
+	return queryBooks(ctx, r.db, statement, args...)
+}
+
+// querier es implementado por *sql.DB y *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// queryBooks ejecuta una consulta que devuelve libros completos
+func queryBooks(ctx context.Context, q querier, statement string, args ...any) ([]domain.Book, error) {
+	rows, err := q.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, classifyError(ctx, err)
+	}
+	defer rows.Close()
+
+	books := []domain.Book{}
+	for rows.Next() {
+		var book domain.Book
+		if err := rows.Scan(&book.ID, &book.Name, &book.Author, &book.UnitsSold, &book.Price.Amount, &book.Price.Currency, &book.Version); err != nil {
+			return nil, fmt.Errorf("%w: scanning book: %w", domain.ErrMalformedPayload, err)
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, classifyError(ctx, err)
+	}
+	return books, nil
+}
+
+// ReplaceBooks reemplaza el catálogo completo en una única transacción
+func (r *SQLiteBooksRepository) ReplaceBooks(ctx context.Context, books []domain.Book) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return classifyError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	previous, err := queryBooks(ctx, tx, `SELECT id, name, author, units_sold, price, currency, version FROM books ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM books`); err != nil {
+		return classifyError(ctx, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO books (id, name, author, units_sold, price, currency, version) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return classifyError(ctx, err)
+	}
+	defer stmt.Close()
+
+	for _, book := range books {
+		if _, err := stmt.ExecContext(ctx, book.ID, book.Name, book.Author, book.UnitsSold, book.Price.Amount, book.Price.Currency, book.Version); err != nil {
+			return fmt.Errorf("inserting book %d: %w", book.ID, classifyError(ctx, err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return classifyError(ctx, err)
+	}
+	r.events.Publish(domain.DiffBooks(previous, books, time.Now())...)
+	return nil
+}
+
+// CreateBook implementa la interfaz BooksWriter
+// Con ID 0 SQLite asigna el siguiente ID de la tabla
+func (r *SQLiteBooksRepository) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	if book.ID != 0 {
+		if _, err := currentBook(ctx, tx, book.ID); err == nil {
+			return domain.Book{}, fmt.Errorf("%w: id %d", domain.ErrBookExists, book.ID)
+		} else if !errors.Is(err, domain.ErrBookNotFound) {
+			return domain.Book{}, err
+		}
+	}
+
+	var id any
+	if book.ID != 0 {
+		id = book.ID
+	}
+	result, err := tx.ExecContext(ctx, `INSERT INTO books (id, name, author, units_sold, price, currency, version) VALUES (?, ?, ?, ?, ?, ?, 1)`,
+		id, book.Name, book.Author, book.UnitsSold, book.Price.Amount, book.Price.Currency)
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	insertedID, err := result.LastInsertId()
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+
+	book.ID = uint(insertedID)
+	book.Version = 1
+	r.events.Publish(domain.BookEvent{Type: domain.BookAdded, After: &book, At: time.Now()})
+	return book, nil
+}
+
+// UpdateBook implementa la interfaz BooksWriter
+// La comparación de versión y la escritura se hacen en una única sentencia
+func (r *SQLiteBooksRepository) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	before, err := currentBook(ctx, tx, book.ID)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	result, err := tx.ExecContext(ctx, `UPDATE books SET name = ?, author = ?, units_sold = ?, price = ?, currency = ?, version = version + 1 WHERE id = ? AND version = ?`,
+		book.Name, book.Author, book.UnitsSold, book.Price.Amount, book.Price.Currency, book.ID, book.Version)
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	if err := checkWritten(ctx, tx, result, book.ID, book.Version); err != nil {
+		return domain.Book{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+
+	book.Version++
+	r.events.Publish(domain.BookEvent{Type: domain.BookUpdated, Before: &before, After: &book, At: time.Now()})
+	return book, nil
+}
+
+// DeleteBook implementa la interfaz BooksWriter
+func (r *SQLiteBooksRepository) DeleteBook(ctx context.Context, id uint, version uint64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return classifyError(ctx, err)
+	}
+	defer tx.Rollback()
+
+	before, err := currentBook(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `DELETE FROM books WHERE id = ? AND (? = 0 OR version = ?)`, id, version, version)
+	if err != nil {
+		return classifyError(ctx, err)
+	}
+	if err := checkWritten(ctx, tx, result, id, version); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return classifyError(ctx, err)
+	}
+	r.events.Publish(domain.BookEvent{Type: domain.BookRemoved, Before: &before, At: time.Now()})
+	return nil
+}
+
+// checkWritten distingue, cuando una escritura condicional no afectó filas, si el libro no existe
+// o si su versión no coincide
+func checkWritten(ctx context.Context, tx *sql.Tx, result sql.Result, id uint, version uint64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return classifyError(ctx, err)
+	}
+	if affected > 0 {
+		return nil
+	}
+	current, err := currentBook(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: id %d is at version %d, not %d", domain.ErrVersionConflict, id, current.Version, version)
+}
+
+// currentBook devuelve el libro guardado o domain.ErrBookNotFound
+func currentBook(ctx context.Context, tx *sql.Tx, id uint) (domain.Book, error) {
+	var book domain.Book
+	err := tx.QueryRowContext(ctx, `SELECT id, name, author, units_sold, price, currency, version FROM books WHERE id = ?`, id).
+		Scan(&book.ID, &book.Name, &book.Author, &book.UnitsSold, &book.Price.Amount, &book.Price.Currency, &book.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Book{}, fmt.Errorf("%w: id %d", domain.ErrBookNotFound, id)
+	}
+	if err != nil {
+		return domain.Book{}, classifyError(ctx, err)
+	}
+	return book, nil
+}
+
+// classifyError traduce un error de la base de datos al error tipado correspondiente
+func classifyError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %w", domain.ErrContextCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamTimeout, err)
+	default:
+		return fmt.Errorf("%w: %w", domain.ErrUpstreamUnavailable, err)
+	}
+}
@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testBooks = []domain.Book{
+	{ID: 1, Name: "The Go Programming Language", Author: "Alan Donovan", UnitsSold: 5000, Price: domain.Price(40)},
+	{ID: 2, Name: "Clean Code", Author: "Robert C. Martin", UnitsSold: 15000, Price: domain.Price(50)},
+	{ID: 3, Name: "The Pragmatic Programmer", Author: "Andrew Hunt", UnitsSold: 13000, Price: domain.Price(45)},
+}
+
+func openTestRepository(t *testing.T) *SQLiteBooksRepository {
+	t.Helper()
+	repository, err := Open(context.Background(), ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { repository.Close() })
+	return repository
+}
+
+func TestSQLiteBooksRepository_EmptyCatalog(t *testing.T) {
+	repository := openTestRepository(t)
+
+	books, err := repository.GetBooks(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, books)
+	assert.Empty(t, books)
+}
+
+func TestSQLiteBooksRepository_ReplaceAndGet(t *testing.T) {
+	repository := openTestRepository(t)
+	ctx := context.Background()
+
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks))
+	books, err := repository.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+
+	// Reemplazar el catálogo elimina los libros que ya no están
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks[:1]))
+	books, err = repository.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testBooks[:1], books)
+}
+
+func TestSQLiteBooksRepository_ReplaceIsAtomic(t *testing.T) {
+	repository := openTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks))
+
+	// Un ID duplicado hace fallar la transacción y se conserva el catálogo anterior
+	duplicated := []domain.Book{testBooks[0], testBooks[0]}
+	assert.ErrorIs(t, repository.ReplaceBooks(ctx, duplicated), domain.ErrUpstreamUnavailable)
+
+	books, err := repository.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+}
+
+func TestSQLiteBooksRepository_ContextCanceled(t *testing.T) {
+	repository := openTestRepository(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repository.GetBooks(ctx)
+	assert.ErrorIs(t, err, domain.ErrContextCanceled)
+}
+
+func TestSQLiteBooksRepository_PersistsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "books.db")
+	ctx := context.Background()
+
+	repository, err := Open(ctx, path)
+	require.NoError(t, err)
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks))
+	require.NoError(t, repository.Close())
+
+	reopened, err := Open(ctx, path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	books, err := reopened.GetBooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testBooks, books)
+}
+
+func TestSQLiteBooksRepository_WithMetricsService(t *testing.T) {
+	repository := openTestRepository(t)
+	require.NoError(t, repository.ReplaceBooks(context.Background(), testBooks))
+
+	// El servicio de métricas funciona sobre SQLite sin cambios
+	service := services.NewMetricsService(repository)
+	books, err := service.GetBooks(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(11000), service.GetMeanUnitsSold(books))
+	assert.Equal(t, "The Go Programming Language", service.GetCheapestBook(books).Name)
+	assert.Equal(t, uint(1), service.GetBooksWrittenByAuthor(books, "Robert C. Martin"))
+}
+
+func TestSQLiteBooksRepository_QueryBooks(t *testing.T) {
+	repository := openTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repository.ReplaceBooks(ctx, append(slices.Clone(testBooks),
+		domain.Book{ID: 4, Name: "Refactoring", Author: "Robert C. Martin", UnitsSold: 9000, Price: domain.Price(45)},
+	)))
+
+	tests := []struct {
+		name     string
+		query    domain.BooksQuery
+		expected []uint
+	}{
+		{"all", domain.BooksQuery{}, []uint{1, 2, 3, 4}},
+		{"author", domain.BooksQuery{Author: "Robert C. Martin"}, []uint{2, 4}},
+		{"price range", domain.BooksQuery{MinPrice: domain.Price(41), MaxPrice: domain.Price(45)}, []uint{3, 4}},
+		{"units range", domain.BooksQuery{MinUnitsSold: 10000}, []uint{2, 3}},
+		{"ids", domain.BooksQuery{IDs: []uint{4, 1, 99}}, []uint{1, 4}},
+		{"sort by price desc", domain.BooksQuery{SortBy: domain.SortByPrice, Descending: true}, []uint{2, 4, 3, 1}},
+		{"sort and limit", domain.BooksQuery{SortBy: domain.SortByUnitsSold, Limit: 2}, []uint{1, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books, err := repository.QueryBooks(ctx, tt.query)
+			require.NoError(t, err)
+
+			ids := []uint{}
+			for _, book := range books {
+				ids = append(ids, book.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+
+			// El resultado en SQL debe coincidir con el filtro en memoria
+			all, err := repository.GetBooks(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.query.Apply(all), books)
+		})
+	}
+
+	_, err := repository.QueryBooks(ctx, domain.BooksQuery{SortBy: "isbn"})
+	assert.ErrorIs(t, err, domain.ErrInvalidQuery)
+}
+
+func TestSQLiteBooksRepository_CountByAuthorUsesPushDown(t *testing.T) {
+	repository := openTestRepository(t)
+	require.NoError(t, repository.ReplaceBooks(context.Background(), testBooks))
+
+	service := services.NewMetricsService(repository)
+	count, err := service.CountBooksWrittenByAuthor(context.Background(), "Alan Donovan")
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), count)
+}
+
+func TestSQLiteBooksRepository_Writes(t *testing.T) {
+	repository := openTestRepository(t)
+	ctx := context.Background()
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks))
+
+	created, err := repository.CreateBook(ctx, domain.Book{Name: "Refactoring", Author: "Martin Fowler", UnitsSold: 100, Price: domain.Price(45)})
+	require.NoError(t, err)
+	assert.Equal(t, uint(4), created.ID)
+	assert.Equal(t, uint64(1), created.Version)
+
+	_, err = repository.CreateBook(ctx, domain.Book{ID: 1, Name: "Duplicate", Author: "Someone", Price: domain.Price(1)})
+	assert.ErrorIs(t, err, domain.ErrBookExists)
+
+	// Los libros importados no tienen versión y se actualizan desde la versión 0
+	imported := testBooks[0]
+	imported.Price = domain.Price(35)
+	updated, err := repository.UpdateBook(ctx, imported)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), updated.Version)
+
+	_, err = repository.UpdateBook(ctx, imported)
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+	_, err = repository.UpdateBook(ctx, domain.Book{ID: 99, Name: "Missing", Author: "Nobody", Price: domain.Price(1)})
+	assert.ErrorIs(t, err, domain.ErrBookNotFound)
+
+	assert.ErrorIs(t, repository.DeleteBook(ctx, 4, 7), domain.ErrVersionConflict)
+	require.NoError(t, repository.DeleteBook(ctx, 4, 1))
+	assert.ErrorIs(t, repository.DeleteBook(ctx, 4, 0), domain.ErrBookNotFound)
+
+	books, err := repository.GetBooks(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 3)
+	assert.Equal(t, updated, books[0])
+}
+
+func TestSQLiteBooksRepository_Watch(t *testing.T) {
+	repository := openTestRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repository.Watch(ctx)
+
+	// Reemplazar el catálogo informa la diferencia con el anterior
+	require.NoError(t, repository.ReplaceBooks(ctx, testBooks))
+	for _, book := range testBooks {
+		event := <-events
+		assert.Equal(t, domain.BookAdded, event.Type)
+		assert.Equal(t, book, *event.After)
+	}
+
+	updated := testBooks[1]
+	updated.UnitsSold = 16000
+	_, err := repository.UpdateBook(ctx, updated)
+	require.NoError(t, err)
+	require.NoError(t, repository.DeleteBook(ctx, 3, 0))
+
+	event := <-events
+	assert.Equal(t, domain.BookUpdated, event.Type)
+	assert.Equal(t, uint(15000), event.Before.UnitsSold)
+	assert.Equal(t, uint(16000), event.After.UnitsSold)
+
+	event = <-events
+	assert.Equal(t, domain.BookRemoved, event.Type)
+	assert.Equal(t, testBooks[2], *event.Before)
+
+	// Una escritura fallida no genera eventos
+	_, err = repository.UpdateBook(ctx, updated)
+	require.ErrorIs(t, err, domain.ErrVersionConflict)
+	select {
+	case event := <-events:
+		t.Fatalf("Unexpected event %+v", event)
+	default:
+	}
+}
@@ -0,0 +1,7 @@
+CREATE TABLE books (
+    id         INTEGER PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    author     TEXT    NOT NULL,
+    units_sold INTEGER NOT NULL DEFAULT 0 CHECK (units_sold >= 0),
+    price      INTEGER NOT NULL DEFAULT 0 CHECK (price >= 0)
+);
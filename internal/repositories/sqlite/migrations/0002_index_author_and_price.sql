@@ -0,0 +1,2 @@
+CREATE INDEX idx_books_author ON books (author);
+CREATE INDEX idx_books_price ON books (price);
@@ -0,0 +1 @@
+ALTER TABLE books ADD COLUMN version INTEGER NOT NULL DEFAULT 0 CHECK (version >= 0);
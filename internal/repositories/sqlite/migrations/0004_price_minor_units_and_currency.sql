@@ -0,0 +1,6 @@
+-- Los precios pasan a guardarse en unidades menores (centavos) junto con su moneda ISO 4217;
+-- los existentes estaban en unidades enteras de USD
+ALTER TABLE books ADD COLUMN currency TEXT NOT NULL DEFAULT 'USD' CHECK (length(currency) = 3);
+UPDATE books SET price = price * 100;
+DROP INDEX idx_books_price;
+CREATE INDEX idx_books_currency_price ON books (currency, price);
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(driverName, ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrate_AppliesAllAndIsIdempotent(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	applied, err := Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, applied)
+
+	applied, err = Migrate(ctx, db)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	version, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 4, version)
+}
+
+func TestMigrate_CreatesIndexes(t *testing.T) {
+	db := openTestDB(t)
+	_, err := Migrate(context.Background(), db)
+	require.NoError(t, err)
+
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'books' ORDER BY name`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		indexes = append(indexes, name)
+	}
+	assert.Equal(t, []string{"idx_books_author", "idx_books_currency_price"}, indexes)
+}
+
+func TestApplyMigrations_FailedMigrationIsRolledBack(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	migrations := []Migration{
+		{Version: 1, Name: "0001_ok", SQL: `CREATE TABLE things (id INTEGER PRIMARY KEY)`},
+		{Version: 2, Name: "0002_broken", SQL: `CREATE TABLE other (id INTEGER); INSERT INTO missing VALUES (1)`},
+	}
+
+	applied, err := applyMigrations(ctx, db, migrations)
+	assert.Error(t, err)
+	assert.Equal(t, []int{1}, applied)
+
+	version, err := SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	// La tabla creada por la migración fallida no debe existir
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'other'`).Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestLoadMigrations_Ordered(t *testing.T) {
+	migrations, err := loadMigrations()
+	require.NoError(t, err)
+	require.Len(t, migrations, 4)
+	assert.Equal(t, "0001_create_books", migrations[0].Name)
+	assert.Equal(t, "0002_index_author_and_price", migrations[1].Name)
+	assert.Equal(t, "0003_add_book_version", migrations[2].Name)
+	assert.Equal(t, "0004_price_minor_units_and_currency", migrations[3].Name)
+}
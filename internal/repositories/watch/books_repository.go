@@ -0,0 +1,124 @@
+package watch
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
+)
+
+// Config define el comportamiento del repositorio que detecta cambios por sondeo
+type Config struct {
+	// Interval es el tiempo entre consultas al repositorio envuelto
+	Interval time.Duration
+	// Buffer es la cantidad de eventos que un suscriptor puede tener pendientes
+	Buffer int
+}
+
+// DefaultConfig devuelve la configuración por defecto
+func DefaultConfig() Config {
+	return Config{
+		Interval: 30 * time.Second,
+		Buffer:   DefaultBuffer,
+	}
+}
+
+// PollingBooksRepository agrega Watch a un repositorio que no informa sus cambios,
+// comparando catálogos sucesivos obtenidos cada Interval
+// Sólo se consulta al repositorio envuelto mientras haya suscriptores
+type PollingBooksRepository struct {
+	inner       ports.BooksRepository
+	config      Config
+	broadcaster *Broadcaster
+	now         func() time.Time
+
+	mu       sync.Mutex
+	previous []domain.Book
+	baseline bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPollingBooksRepository crea el decorador e inicia el sondeo en segundo plano
+// Close detiene el sondeo
+func NewPollingBooksRepository(inner ports.BooksRepository, config Config) *PollingBooksRepository {
+	if config.Interval <= 0 {
+		config.Interval = DefaultConfig().Interval
+	}
+	r := &PollingBooksRepository{
+		inner:       inner,
+		config:      config,
+		broadcaster: NewBroadcaster(config.Buffer),
+		now:         time.Now,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// GetBooks delega en el repositorio envuelto
+func (r *PollingBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	return r.inner.GetBooks(ctx)
+}
+
+// Watch implementa la interfaz WatchableBooksRepository
+func (r *PollingBooksRepository) Watch(ctx context.Context) <-chan domain.BookEvent {
+	return r.broadcaster.Subscribe(ctx)
+}
+
+// Close detiene el sondeo y cierra los canales de los suscriptores
+func (r *PollingBooksRepository) Close() {
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	<-r.done
+	r.broadcaster.Close()
+}
+
+func (r *PollingBooksRepository) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			if r.broadcaster.Subscribers() > 0 {
+				r.poll()
+			}
+		}
+	}
+}
+
+// poll obtiene el catálogo y publica las diferencias con la consulta anterior
+// La primera consulta sólo fija la referencia. Los fallos, los catálogos parciales y los servidos
+// desde un snapshot o un catálogo alternativo se ignoran porque no reflejan el estado actual de la fuente
+func (r *PollingBooksRepository) poll() {
+	ctx, cancel := context.WithTimeout(domain.WithFetchInfo(context.Background()), r.config.Interval)
+	defer cancel()
+
+	books, err := r.inner.GetBooks(ctx)
+	if err != nil {
+		return
+	}
+	if info, ok := domain.FetchInfoFromContext(ctx); ok && (info.Partial || info.FromSnapshot || info.Fallback) {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.baseline {
+		r.broadcaster.Publish(domain.DiffBooks(r.previous, books, r.now())...)
+	}
+	r.previous = slices.Clone(books)
+	r.baseline = true
+}
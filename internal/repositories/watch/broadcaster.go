@@ -0,0 +1,108 @@
+package watch
+
+import (
+	"context"
+	"sync"
+
+	"educabot.com/bookshop/internal/core/domain"
+)
+
+// DefaultBuffer es la cantidad de eventos que un suscriptor puede tener pendientes por defecto
+const DefaultBuffer = 64
+
+// Broadcaster reparte los eventos del catálogo entre los suscriptores
+// Publish nunca bloquea: un suscriptor con el buffer lleno se desconecta cerrando su canal,
+// para que no pierda eventos sin enterarse
+type Broadcaster struct {
+	mu          sync.Mutex
+	buffer      int
+	subscribers map[chan domain.BookEvent]struct{}
+	closed      bool
+	done        chan struct{}
+}
+
+// NewBroadcaster crea un broadcaster con el buffer por suscriptor indicado (DefaultBuffer si es 0)
+func NewBroadcaster(buffer int) *Broadcaster {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Broadcaster{buffer: buffer, subscribers: map[chan domain.BookEvent]struct{}{}, done: make(chan struct{})}
+}
+
+// Subscribe devuelve un canal con los eventos publicados a partir de ahora
+// El canal se cierra al cancelar ctx, al desconectarse por lento o al cerrar el broadcaster
+func (b *Broadcaster) Subscribe(ctx context.Context) <-chan domain.BookEvent {
+	ch := make(chan domain.BookEvent, b.buffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch
+	}
+	b.subscribers[ch] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.unsubscribe(ch)
+		case <-b.done:
+		}
+	}()
+	return ch
+}
+
+// Publish envía los eventos a todos los suscriptores en el orden recibido
+func (b *Broadcaster) Publish(events ...domain.BookEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		for _, event := range events {
+			select {
+			case ch <- event:
+			default:
+				// Suscriptor lento: se lo desconecta en lugar de bloquear a quien publica
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+			if _, subscribed := b.subscribers[ch]; !subscribed {
+				break
+			}
+		}
+	}
+}
+
+// Subscribers devuelve la cantidad de suscriptores activos
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close cierra los canales de todos los suscriptores; las suscripciones posteriores nacen cerradas
+func (b *Broadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.done)
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+func (b *Broadcaster) unsubscribe(ch chan domain.BookEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, subscribed := b.subscribers[ch]; subscribed {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
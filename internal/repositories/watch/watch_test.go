@@ -0,0 +1,152 @@
+package watch
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"educabot.com/bookshop/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBooksRepository devuelve el catálogo configurado y cuenta las llamadas
+type stubBooksRepository struct {
+	mu       sync.Mutex
+	books    []domain.Book
+	snapshot bool
+	calls    atomic.Int32
+}
+
+func (s *stubBooksRepository) GetBooks(ctx context.Context) ([]domain.Book, error) {
+	s.calls.Add(1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshot {
+		domain.RecordFetchInfo(ctx, func(info *domain.FetchInfo) { info.FromSnapshot = true })
+	}
+	return slices.Clone(s.books), nil
+}
+
+func (s *stubBooksRepository) set(books []domain.Book, snapshot bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.books = books
+	s.snapshot = snapshot
+}
+
+func receive(t *testing.T, ch <-chan domain.BookEvent) domain.BookEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		require.True(t, ok, "channel closed unexpectedly")
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for an event")
+		return domain.BookEvent{}
+	}
+}
+
+func TestBroadcaster_FanOut(t *testing.T) {
+	b := NewBroadcaster(4)
+	first := b.Subscribe(context.Background())
+	second := b.Subscribe(context.Background())
+
+	event := domain.BookEvent{Type: domain.BookAdded, After: &domain.Book{ID: 1}}
+	b.Publish(event)
+
+	assert.Equal(t, event, receive(t, first))
+	assert.Equal(t, event, receive(t, second))
+	b.Close()
+	_, ok := <-first
+	assert.False(t, ok)
+}
+
+func TestBroadcaster_SlowSubscriberIsDisconnected(t *testing.T) {
+	b := NewBroadcaster(2)
+	slow := b.Subscribe(context.Background())
+	fast := b.Subscribe(context.Background())
+
+	for i := uint(1); i <= 3; i++ {
+		b.Publish(domain.BookEvent{Type: domain.BookAdded, After: &domain.Book{ID: i}})
+		if i < 3 {
+			receive(t, fast)
+		}
+	}
+
+	// El suscriptor lento recibe lo que entró en su buffer y luego el canal cerrado
+	assert.Equal(t, uint(1), receive(t, slow).BookID())
+	assert.Equal(t, uint(2), receive(t, slow).BookID())
+	_, ok := <-slow
+	assert.False(t, ok)
+
+	assert.Equal(t, uint(3), receive(t, fast).BookID())
+	assert.Equal(t, 1, b.Subscribers())
+}
+
+func TestBroadcaster_UnsubscribeOnCancel(t *testing.T) {
+	b := NewBroadcaster(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := b.Subscribe(ctx)
+	cancel()
+
+	_, ok := <-ch
+	assert.False(t, ok)
+	assert.Equal(t, 0, b.Subscribers())
+}
+
+func TestPollingBooksRepository_EmitsDiffs(t *testing.T) {
+	inner := &stubBooksRepository{books: []domain.Book{{ID: 1, Name: "Book 1", Price: domain.Price(10)}}}
+	repository := NewPollingBooksRepository(inner, Config{Interval: 10 * time.Millisecond})
+	defer repository.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repository.Watch(ctx)
+
+	// Esperar a que la primera consulta fije la referencia
+	require.Eventually(t, func() bool { return inner.calls.Load() >= 1 }, 2*time.Second, 5*time.Millisecond)
+	inner.set([]domain.Book{{ID: 1, Name: "Book 1", Price: domain.Price(12)}, {ID: 2, Name: "Book 2", Price: domain.Price(5)}}, false)
+
+	updated := receive(t, events)
+	assert.Equal(t, domain.BookUpdated, updated.Type)
+	assert.Equal(t, domain.Price(10), updated.Before.Price)
+	assert.Equal(t, domain.Price(12), updated.After.Price)
+
+	added := receive(t, events)
+	assert.Equal(t, domain.BookAdded, added.Type)
+	assert.Equal(t, uint(2), added.BookID())
+}
+
+func TestPollingBooksRepository_IgnoresSnapshots(t *testing.T) {
+	inner := &stubBooksRepository{books: []domain.Book{{ID: 1}, {ID: 2}}}
+	repository := NewPollingBooksRepository(inner, Config{Interval: 10 * time.Millisecond})
+	defer repository.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repository.Watch(ctx)
+	require.Eventually(t, func() bool { return inner.calls.Load() >= 1 }, 2*time.Second, 5*time.Millisecond)
+
+	// Un snapshot viejo no debe interpretarse como la baja del libro 2
+	inner.set([]domain.Book{{ID: 1}}, true)
+	calls := inner.calls.Load()
+	require.Eventually(t, func() bool { return inner.calls.Load() >= calls+2 }, 2*time.Second, 5*time.Millisecond)
+
+	inner.set([]domain.Book{{ID: 1}, {ID: 2}, {ID: 3}}, false)
+	event := receive(t, events)
+	assert.Equal(t, domain.BookAdded, event.Type)
+	assert.Equal(t, uint(3), event.BookID())
+}
+
+func TestPollingBooksRepository_DoesNotPollWithoutSubscribers(t *testing.T) {
+	inner := &stubBooksRepository{}
+	repository := NewPollingBooksRepository(inner, Config{Interval: 5 * time.Millisecond})
+
+	time.Sleep(50 * time.Millisecond)
+	repository.Close()
+	assert.Equal(t, int32(0), inner.calls.Load())
+}
@@ -1,13 +1,36 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"educabot.com/bookshop/internal/adapters/handlers"
+	"educabot.com/bookshop/internal/core/domain"
+	"educabot.com/bookshop/internal/core/ports"
 	"educabot.com/bookshop/internal/core/services"
+	"educabot.com/bookshop/internal/core/validation"
+	"educabot.com/bookshop/internal/repositories/breaker"
+	"educabot.com/bookshop/internal/repositories/cache"
+	"educabot.com/bookshop/internal/repositories/chaos"
+	"educabot.com/bookshop/internal/repositories/coalesce"
+	"educabot.com/bookshop/internal/repositories/file"
+	grpcrepo "educabot.com/bookshop/internal/repositories/grpc"
 	"educabot.com/bookshop/internal/repositories/http"
+	"educabot.com/bookshop/internal/repositories/http/auth"
+	"educabot.com/bookshop/internal/repositories/memory"
+	"educabot.com/bookshop/internal/repositories/sales"
+	"educabot.com/bookshop/internal/repositories/sqlite"
+	"educabot.com/bookshop/internal/repositories/watch"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 func main() {
@@ -16,18 +39,475 @@ func main() {
 		log.Fatalf("Failed to set trusted proxies: %v", err)
 	}
 
-	// Inicializar el repositorio - Usando el repositorio HTTP para obtener datos reales
-	booksRepository := http.NewHTTPBooksRepository()
+	// Inyección de fallas para game days: BOOKS_CHAOS_CONFIG apunta a un JSON con la configuración
+	// inicial y BOOKS_CHAOS_ADMIN=true permite cambiarla en caliente desde /admin/chaos
+	injector := newChaosInjector()
+
+	// Inicializar el repositorio - Por defecto se usa el repositorio HTTP para obtener datos reales
+	source := newBooksSource(injector)
+	defer source.close()
+
+	// Ventas en tiempo real: los eventos BookSold del broker se suman a UnitsSold
+	repository, salesPublisher, stopSales := newSalesProjection(source.repository)
+	defer stopSales()
 
 	// Inicializar el servicio - Aquí el contexto se propagará correctamente
-	metricsService := services.NewMetricsService(booksRepository)
+	metricsService := services.NewMetricsService(repository)
 
 	// Inicializar el handler con el servicio
 	metricsHandler := handlers.NewGetMetrics(metricsService)
 	router.GET("/", metricsHandler.Handle())
 
+	// Exponer los libros que la validación dejó en cuarentena junto con los motivos
+	quarantineHandler := handlers.NewGetQuarantine(source.quarantine)
+	router.GET("/quarantine", quarantineHandler.Handle())
+
+	// Las fuentes que admiten escrituras exponen la gestión del catálogo; cada escritura
+	// invalida la caché para que las métricas reflejen el cambio
+	if source.writer != nil {
+		catalogService := services.NewCatalogService(source.writer, source.invalidates...)
+		router.POST("/books", handlers.NewCreateBook(catalogService).Handle())
+		router.PUT("/books/:id", handlers.NewUpdateBook(catalogService).Handle())
+		router.DELETE("/books/:id", handlers.NewDeleteBook(catalogService).Handle())
+	}
+
+	// Transmitir los cambios del catálogo para que los dashboards no tengan que consultar "/"
+	router.GET("/books/events", handlers.NewWatchBooks(source.watcher).Handle())
+
+	// Diagnóstico de la conexión con el proveedor: transporte efectivo, reintentos y límites
+	// Expone la configuración interna, así que sólo se habilita con BOOKS_DEBUG_UPSTREAM=true
+	if source.upstream != nil && os.Getenv("BOOKS_DEBUG_UPSTREAM") == "true" {
+		router.GET("/debug/upstream", handlers.NewGetUpstreamInfo(source.upstream).Handle())
+	}
+
+	// Con el broker embebido las ventas se registran publicándolas directamente
+	if salesPublisher != nil {
+		router.POST("/sales/events", handlers.NewPublishSale(salesPublisher).Handle())
+	}
+
+	if injector != nil && os.Getenv("BOOKS_CHAOS_ADMIN") == "true" {
+		router.GET("/admin/chaos", handlers.NewGetChaos(injector).Handle())
+		router.PUT("/admin/chaos", handlers.NewUpdateChaos(injector).Handle())
+	}
+
 	fmt.Println("Starting server on :3000")
 	if err := router.Run(":3000"); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// booksSource agrupa el repositorio de lectura con las capacidades opcionales de la fuente elegida
+type booksSource struct {
+	repository ports.BooksRepository
+	// quarantine es nil si la fuente no valida sus datos
+	quarantine ports.QuarantineSource
+	// writer es nil si la fuente es de sólo lectura
+	writer      ports.BooksWriter
+	invalidates []ports.CacheInvalidator
+	// watcher informa los cambios del catálogo, de forma nativa o comparando consultas sucesivas
+	watcher ports.WatchableBooksRepository
+	// upstream es nil si la fuente no consulta a un proveedor externo
+	upstream ports.UpstreamInspector
+	close    func()
+}
+
+// newBooksSource construye la fuente de libros según el entorno
+// Con BOOKS_SQLITE_PATH se usa la base SQLite local (cargada con cmd/seed), con
+// BOOKS_FILE_PATH un archivo JSON, NDJSON o CSV que se recarga al cambiar (por ejemplo data/books.json),
+// con BOOKS_GRPC_TARGET el servicio gRPC BooksCatalog (por ejemplo dns:///catalog.internal:443),
+// con BOOKS_STORE=memory un catálogo en memoria editable;
+// si no, la API externa protegida por circuit breaker, coalescing y caché
+// Sólo la API externa valida sus datos, por lo que es la única fuente con cuarentena,
+// y sólo SQLite y la memoria admiten escrituras
+// Si injector no es nil, las fallas se inyectan en el transporte de la API externa o, para
+// las demás fuentes, en la lectura del catálogo
+func newBooksSource(injector *chaos.Injector) booksSource {
+	withChaos := func(repository ports.BooksRepository) ports.BooksRepository {
+		if injector == nil {
+			return repository
+		}
+		return chaos.NewChaosBooksRepository(repository, injector)
+	}
+
+	if path := os.Getenv("BOOKS_FILE_PATH"); path != "" {
+		fileConfig := file.DefaultConfig()
+		fileConfig.OnReload = func(err error) {
+			if err != nil {
+				log.Printf("Books file reload rejected, keeping previous catalog: %v", err)
+				return
+			}
+			log.Printf("Books file %s reloaded", path)
+		}
+		fileBooksRepository, err := file.NewFileBooksRepository(path, fileConfig)
+		if err != nil {
+			log.Fatalf("Failed to load books file: %v", err)
+		}
+		watchConfig := watch.DefaultConfig()
+		watchConfig.Interval = fileConfig.PollInterval
+		pollingBooksRepository := watch.NewPollingBooksRepository(fileBooksRepository, watchConfig)
+		return booksSource{
+			repository: withChaos(fileBooksRepository),
+			watcher:    pollingBooksRepository,
+			close: func() {
+				pollingBooksRepository.Close()
+				fileBooksRepository.Close()
+			},
+		}
+	}
+
+	if path := os.Getenv("BOOKS_SQLITE_PATH"); path != "" {
+		sqliteBooksRepository, err := sqlite.Open(context.Background(), path)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite books database: %v", err)
+		}
+		return booksSource{
+			repository: withChaos(sqliteBooksRepository),
+			writer:     sqliteBooksRepository,
+			watcher:    sqliteBooksRepository,
+			close:      func() { sqliteBooksRepository.Close() },
+		}
+	}
+
+	if target := os.Getenv("BOOKS_GRPC_TARGET"); target != "" {
+		return newGRPCBooksSource(target, withChaos)
+	}
+
+	if os.Getenv("BOOKS_STORE") == "memory" {
+		memoryBooksRepository := memory.NewMemoryBooksRepository()
+		cachedBooksRepository := cache.NewCachedBooksRepository(memoryBooksRepository, cache.DefaultConfig())
+		return booksSource{
+			repository:  withChaos(cachedBooksRepository),
+			writer:      memoryBooksRepository,
+			invalidates: []ports.CacheInvalidator{cachedBooksRepository},
+			watcher:     memoryBooksRepository,
+			close:       func() {},
+		}
+	}
+
+	// Con BOOKS_SNAPSHOT_DIR se guarda el catálogo en disco para poder servirlo si el proveedor
+	// está caído, incluso después de un reinicio
+	var httpOptions []http.Option
+	if dir := os.Getenv("BOOKS_SNAPSHOT_DIR"); dir != "" {
+		httpOptions = append(httpOptions, http.WithSnapshots(http.DefaultSnapshotConfig(dir)))
+	}
+	// Con BOOKS_MAPPING_FILE se lee un catálogo con otro esquema según un mapeo declarativo en JSON
+	if path := os.Getenv("BOOKS_MAPPING_FILE"); path != "" {
+		mapping, err := http.LoadMapping(path)
+		if err != nil {
+			log.Fatalf("Failed to load books field mapping: %v", err)
+		}
+		httpOptions = append(httpOptions, http.WithMapping(mapping))
+	}
+
+	// BOOKS_VALIDATION_POLICY define qué hacer con los libros inválidos: drop (por defecto), keep o fail
+	validationPolicy := validation.PolicyDrop
+	if name := os.Getenv("BOOKS_VALIDATION_POLICY"); name != "" {
+		policy, err := validation.ParsePolicy(name)
+		if err != nil {
+			log.Fatalf("Invalid BOOKS_VALIDATION_POLICY: %v", err)
+		}
+		validationPolicy = policy
+	}
+	// Credenciales del proveedor: cada middleware posterior envuelve a los anteriores, así que la
+	// inyección de fallas ve la petición antes de que se autentique y una falla inyectada no pide tokens
+	httpOptions = append(httpOptions, upstreamAuth()...)
+
+	// BOOKS_UPSTREAM_RATE (peticiones por segundo) y BOOKS_UPSTREAM_MAX_IN_FLIGHT respetan la cuota del
+	// proveedor; con BOOKS_UPSTREAM_FAIL_FAST=true se rechaza en lugar de esperar el cupo
+	if limit, ok := upstreamRateLimit(); ok {
+		httpOptions = append(httpOptions, http.WithRateLimits(http.RateLimitConfig{Default: limit}))
+	}
+	if injector != nil {
+		httpOptions = append(httpOptions, http.WithTransportMiddleware(chaos.Middleware(injector)))
+	}
+	httpOptions = append(httpOptions, http.WithValidation(validation.NewPipeline(validationPolicy, validation.DefaultRules()...)))
+	httpBooksRepository, err := http.NewHTTPBooksRepositoryWithTransport(upstreamTransportConfig(), httpOptions...)
+	if err != nil {
+		log.Fatalf("Failed to configure the books provider HTTP client: %v", err)
+	}
+
+	// Proteger el repositorio HTTP con un circuit breaker: mientras el proveedor esté caído
+	// se responde con el último catálogo válido o, si no lo hay, con los datos en memoria
+	breakerConfig := breaker.DefaultConfig()
+	breakerConfig.Fallback = memory.NewMemoryBooksRepository()
+	breakerConfig.OnStateChange = func(from, to breaker.State) {
+		log.Printf("Books provider circuit breaker changed state: %s -> %s", from, to)
+	}
+	breakerBooksRepository := breaker.NewCircuitBreakerBooksRepository(httpBooksRepository, breakerConfig)
+
+	// Compartir una única llamada al proveedor entre las peticiones concurrentes
+	coalescingBooksRepository := coalesce.NewCoalescingBooksRepository(breakerBooksRepository, coalesce.DefaultConfig())
+
+	// Cachear el catálogo para no consultar al proveedor en cada petición
+	cachedBooksRepository := cache.NewCachedBooksRepository(coalescingBooksRepository, cache.DefaultConfig())
+
+	// Los cambios se detectan comparando consultas sucesivas; se consulta a través de la caché
+	// para no sumar carga al proveedor
+	pollingBooksRepository := watch.NewPollingBooksRepository(cachedBooksRepository, watch.DefaultConfig())
+	return booksSource{
+		repository: cachedBooksRepository,
+		quarantine: httpBooksRepository,
+		watcher:    pollingBooksRepository,
+		upstream:   httpBooksRepository,
+		close:      pollingBooksRepository.Close,
+	}
+}
+
+// newGRPCBooksSource consulta el catálogo al servicio gRPC con la misma protección que la API externa
+// BOOKS_GRPC_TLS=true cifra la conexión con los certificados del sistema y BOOKS_GRPC_STREAMING=false
+// usa la llamada unaria en lugar del streaming; los reintentos los hace el cliente gRPC
+func newGRPCBooksSource(target string, withChaos func(ports.BooksRepository) ports.BooksRepository) booksSource {
+	transportCredentials := insecure.NewCredentials()
+	if os.Getenv("BOOKS_GRPC_TLS") == "true" {
+		transportCredentials = credentials.NewTLS(nil)
+	}
+	grpcConfig := grpcrepo.DefaultConfig()
+	grpcConfig.Streaming = os.Getenv("BOOKS_GRPC_STREAMING") != "false"
+	grpcBooksRepository, err := grpcrepo.Dial(target, grpcConfig, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		log.Fatalf("Failed to configure the books catalog gRPC client: %v", err)
+	}
+
+	breakerConfig := breaker.DefaultConfig()
+	breakerConfig.Fallback = memory.NewMemoryBooksRepository()
+	breakerConfig.OnStateChange = func(from, to breaker.State) {
+		log.Printf("Books catalog circuit breaker changed state: %s -> %s", from, to)
+	}
+	breakerBooksRepository := breaker.NewCircuitBreakerBooksRepository(withChaos(grpcBooksRepository), breakerConfig)
+	coalescingBooksRepository := coalesce.NewCoalescingBooksRepository(breakerBooksRepository, coalesce.DefaultConfig())
+	cachedBooksRepository := cache.NewCachedBooksRepository(coalescingBooksRepository, cache.DefaultConfig())
+	pollingBooksRepository := watch.NewPollingBooksRepository(cachedBooksRepository, watch.DefaultConfig())
+	return booksSource{
+		repository: cachedBooksRepository,
+		watcher:    pollingBooksRepository,
+		close: func() {
+			pollingBooksRepository.Close()
+			grpcBooksRepository.Close()
+		},
+	}
+}
+
+// upstreamTransportConfig lee la configuración del cliente HTTP del proveedor; las variables no
+// definidas conservan los valores de http.DefaultTransportConfig
+// Proxy: BOOKS_HTTP_PROXY y BOOKS_HTTP_NO_PROXY (sin ellas se usan HTTP_PROXY, HTTPS_PROXY y NO_PROXY),
+// o BOOKS_HTTP_PROXY=off para conectar siempre de forma directa
+// Duraciones (por ejemplo "5s"): BOOKS_HTTP_TIMEOUT, BOOKS_HTTP_DIAL_TIMEOUT, BOOKS_HTTP_TLS_HANDSHAKE_TIMEOUT,
+// BOOKS_HTTP_RESPONSE_HEADER_TIMEOUT, BOOKS_HTTP_IDLE_CONN_TIMEOUT y BOOKS_HTTP_KEEP_ALIVE
+// Conexiones: BOOKS_HTTP_MAX_IDLE_CONNS, BOOKS_HTTP_MAX_IDLE_CONNS_PER_HOST, BOOKS_HTTP_MAX_CONNS_PER_HOST
+// y BOOKS_HTTP_DISABLE_KEEP_ALIVES; además BOOKS_HTTP2=false y BOOKS_HTTP_CA_FILE
+func upstreamTransportConfig() http.TransportConfig {
+	config := http.DefaultTransportConfig()
+	if proxy := os.Getenv("BOOKS_HTTP_PROXY"); proxy == "off" {
+		config.DisableProxy = true
+	} else {
+		config.ProxyURL = proxy
+		config.NoProxy = os.Getenv("BOOKS_HTTP_NO_PROXY")
+	}
+
+	durations := map[string]*time.Duration{
+		"BOOKS_HTTP_TIMEOUT":                 &config.Timeout,
+		"BOOKS_HTTP_DIAL_TIMEOUT":            &config.DialTimeout,
+		"BOOKS_HTTP_TLS_HANDSHAKE_TIMEOUT":   &config.TLSHandshakeTimeout,
+		"BOOKS_HTTP_RESPONSE_HEADER_TIMEOUT": &config.ResponseHeaderTimeout,
+		"BOOKS_HTTP_IDLE_CONN_TIMEOUT":       &config.IdleConnTimeout,
+		"BOOKS_HTTP_KEEP_ALIVE":              &config.KeepAlive,
+	}
+	for name, target := range durations {
+		if value := os.Getenv(name); value != "" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				log.Fatalf("Invalid %s %q: %v", name, value, err)
+			}
+			*target = d
+		}
+	}
+
+	counts := map[string]*int{
+		"BOOKS_HTTP_MAX_IDLE_CONNS":          &config.MaxIdleConns,
+		"BOOKS_HTTP_MAX_IDLE_CONNS_PER_HOST": &config.MaxIdleConnsPerHost,
+		"BOOKS_HTTP_MAX_CONNS_PER_HOST":      &config.MaxConnsPerHost,
+	}
+	for name, target := range counts {
+		if value := os.Getenv(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				log.Fatalf("Invalid %s %q", name, value)
+			}
+			*target = n
+		}
+	}
+
+	flags := map[string]*bool{
+		"BOOKS_HTTP_DISABLE_KEEP_ALIVES": &config.DisableKeepAlives,
+		"BOOKS_HTTP2":                    &config.HTTP2,
+	}
+	for name, target := range flags {
+		if value := os.Getenv(name); value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				log.Fatalf("Invalid %s %q", name, value)
+			}
+			*target = b
+		}
+	}
+
+	config.CAFile = os.Getenv("BOOKS_HTTP_CA_FILE")
+	return config
+}
+
+// upstreamRateLimit lee los límites de las peticiones al proveedor; ok es falso si no se configuró ninguno
+func upstreamRateLimit() (http.RateLimit, bool) {
+	var limit http.RateLimit
+	if value := os.Getenv("BOOKS_UPSTREAM_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			log.Fatalf("Invalid BOOKS_UPSTREAM_RATE %q", value)
+		}
+		limit.Rate = rate
+		limit.Burst = max(int(rate), 1)
+	}
+	if value := os.Getenv("BOOKS_UPSTREAM_MAX_IN_FLIGHT"); value != "" {
+		maxInFlight, err := strconv.Atoi(value)
+		if err != nil || maxInFlight <= 0 {
+			log.Fatalf("Invalid BOOKS_UPSTREAM_MAX_IN_FLIGHT %q", value)
+		}
+		limit.MaxInFlight = maxInFlight
+	}
+	limit.FailFast = os.Getenv("BOOKS_UPSTREAM_FAIL_FAST") == "true"
+	return limit, limit.Rate > 0 || limit.MaxInFlight > 0
+}
+
+// upstreamAuth lee las credenciales del proveedor del entorno
+// BOOKS_UPSTREAM_AUTH elige el mecanismo: bearer (BOOKS_UPSTREAM_TOKEN), api_key_header o
+// api_key_query (BOOKS_UPSTREAM_API_KEY, con el nombre en BOOKS_UPSTREAM_API_KEY_NAME) u oauth2
+// (BOOKS_OAUTH_TOKEN_URL, BOOKS_OAUTH_CLIENT_ID, BOOKS_OAUTH_CLIENT_SECRET y BOOKS_OAUTH_SCOPES)
+// Los secretos también pueden leerse de un archivo con el sufijo _FILE (por ejemplo BOOKS_UPSTREAM_TOKEN_FILE)
+// Con BOOKS_UPSTREAM_CLIENT_CERT y BOOKS_UPSTREAM_CLIENT_KEY se usa TLS mutuo, verificando al
+// servidor con BOOKS_UPSTREAM_CA_FILE si se indica
+func upstreamAuth() []http.Option {
+	var options []http.Option
+	if certFile := os.Getenv("BOOKS_UPSTREAM_CLIENT_CERT"); certFile != "" {
+		tlsConfig, err := auth.MutualTLSConfig(certFile, os.Getenv("BOOKS_UPSTREAM_CLIENT_KEY"), os.Getenv("BOOKS_UPSTREAM_CA_FILE"))
+		if err != nil {
+			log.Fatalf("Failed to load upstream client certificate: %v", err)
+		}
+		// TLS mutuo reemplaza el transporte base, por lo que debe ser el primer middleware
+		options = append(options, http.WithTransportMiddleware(auth.MutualTLS(tlsConfig)))
+	}
+
+	mode := os.Getenv("BOOKS_UPSTREAM_AUTH")
+	if mode == "" {
+		return options
+	}
+	loadSecret := func(name string) auth.Secret {
+		secret, err := auth.LoadSecret(name)
+		if err != nil {
+			log.Fatalf("Failed to load upstream credentials: %v", err)
+		}
+		return secret
+	}
+	keyName := func(fallback string) string {
+		if name := os.Getenv("BOOKS_UPSTREAM_API_KEY_NAME"); name != "" {
+			return name
+		}
+		return fallback
+	}
+
+	var authenticator auth.Authenticator
+	switch mode {
+	case "bearer":
+		authenticator = auth.Bearer(loadSecret("BOOKS_UPSTREAM_TOKEN"))
+	case "api_key_header":
+		authenticator = auth.APIKeyHeader(keyName("X-Api-Key"), loadSecret("BOOKS_UPSTREAM_API_KEY"))
+	case "api_key_query":
+		authenticator = auth.APIKeyQuery(keyName("api_key"), loadSecret("BOOKS_UPSTREAM_API_KEY"))
+	case "oauth2":
+		if os.Getenv("BOOKS_OAUTH_TOKEN_URL") == "" || os.Getenv("BOOKS_OAUTH_CLIENT_ID") == "" {
+			log.Fatalf("BOOKS_OAUTH_TOKEN_URL and BOOKS_OAUTH_CLIENT_ID are required for oauth2")
+		}
+		authenticator = auth.NewClientCredentials(auth.ClientCredentialsConfig{
+			TokenURL:     os.Getenv("BOOKS_OAUTH_TOKEN_URL"),
+			ClientID:     os.Getenv("BOOKS_OAUTH_CLIENT_ID"),
+			ClientSecret: loadSecret("BOOKS_OAUTH_CLIENT_SECRET"),
+			Scopes:       strings.Fields(strings.ReplaceAll(os.Getenv("BOOKS_OAUTH_SCOPES"), ",", " ")),
+		})
+	default:
+		log.Fatalf("Invalid BOOKS_UPSTREAM_AUTH %q", mode)
+	}
+	log.Printf("Authenticating upstream requests with %s", mode)
+	return append(options, http.WithTransportMiddleware(auth.Middleware(authenticator)))
+}
+
+// newSalesProjection suma a UnitsSold las ventas consumidas del broker si BOOKS_SALES_BROKER lo habilita
+// Por ahora sólo se admite BOOKS_SALES_BROKER=memory, el broker embebido, que recibe las ventas en
+// POST /sales/events; los mensajes inválidos van a BOOKS_SALES_DLQ_TOPIC y con BOOKS_SALES_CHECKPOINT
+// la proyección se guarda en ese archivo para no contar ventas dos veces tras un reinicio
+// Devuelve el repositorio que deben leer las métricas, el publicador (nil si no hay broker)
+// y la función que detiene el consumo
+func newSalesProjection(repository ports.BooksRepository) (ports.BooksRepository, ports.SalesPublisher, func()) {
+	kind := os.Getenv("BOOKS_SALES_BROKER")
+	if kind == "" {
+		return repository, nil, func() {}
+	}
+	if kind != "memory" {
+		log.Fatalf("Invalid BOOKS_SALES_BROKER %q", kind)
+	}
+	broker := sales.NewMemoryBroker()
+
+	var checkpoints sales.CheckpointStore = sales.NewMemoryCheckpointStore()
+	if path := os.Getenv("BOOKS_SALES_CHECKPOINT"); path != "" {
+		checkpoints = sales.NewFileCheckpointStore(path)
+	}
+	config := sales.DefaultConfig()
+	if topic := os.Getenv("BOOKS_SALES_TOPIC"); topic != "" {
+		config.Topic = topic
+	}
+	if topic := os.Getenv("BOOKS_SALES_DLQ_TOPIC"); topic != "" {
+		config.DeadLetterTopic = topic
+	}
+	config.OnError = func(err error) {
+		log.Printf("Sales consumer: %v", err)
+	}
+
+	projection := sales.NewProjection(sales.DefaultDedupWindow)
+	consumer := sales.NewConsumer(broker, projection, checkpoints, config)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := consumer.Run(ctx); err != nil {
+			log.Printf("Sales consumer stopped: %v", err)
+		}
+	}()
+	stop := func() {
+		cancel()
+		<-done
+		broker.Close()
+	}
+	return sales.NewSalesBooksRepository(repository, projection), sales.NewPublisher(broker, config.Topic), stop
+}
+
+// newChaosInjector crea el inyector de fallas si está habilitado por el entorno; si no, devuelve nil
+// Con BOOKS_CHAOS_ADMIN=true y sin BOOKS_CHAOS_CONFIG el inyector arranca desactivado
+func newChaosInjector() *chaos.Injector {
+	var config domain.ChaosConfig
+	path := os.Getenv("BOOKS_CHAOS_CONFIG")
+	if path == "" && os.Getenv("BOOKS_CHAOS_ADMIN") != "true" {
+		return nil
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read chaos config: %v", err)
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			log.Fatalf("Failed to parse chaos config: %v", err)
+		}
+	}
+	injector, err := chaos.NewInjector(config)
+	if err != nil {
+		log.Fatalf("Invalid chaos config: %v", err)
+	}
+	log.Printf("Chaos fault injection available (enabled: %t)", config.Enabled)
+	return injector
+}